@@ -0,0 +1,93 @@
+package inserts
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/valyala/bytebufferpool"
+)
+
+func generateInsertQuery(ctx specifications.Context, spec *specifications.Specification) (query []byte, vr ValueRender, fields []string, returning []string, err error) {
+	vr = NewValueRender()
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	// name
+	tableName := ctx.FormatIdent(spec.Name)
+	if spec.Schema != "" {
+		schema := ctx.FormatIdent(spec.Schema)
+		tableName = fmt.Sprintf("%s.%s", schema, tableName)
+	}
+	_, _ = buf.Write(specifications.INSERT)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.INTO)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.WriteString(tableName)
+	_, _ = buf.Write(specifications.SPACE)
+
+	// column
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.LB)
+	n := 0
+	// pk
+	pk, hasPk := spec.Pk()
+	if !hasPk {
+		err = errors.Warning("pk is required")
+		return
+	}
+	pkName := ""
+	if pk.Incr() {
+		returning = append(returning, pk.Field)
+	} else {
+		pkName = ctx.FormatIdent(pk.Name)
+		_, _ = buf.WriteString(pkName)
+		vr.Add()
+		fields = append(fields, pk.Field)
+		n++
+	}
+	// ver
+	ver, hasVer := spec.AuditVersion()
+	if hasVer {
+		verName := ctx.FormatIdent(ver.Name)
+		if n > 0 {
+			_, _ = buf.Write(specifications.COMMA)
+		}
+		_, _ = buf.WriteString(verName)
+		vr.Add()
+		vr.MarkAsVersion()
+		n++
+	}
+	for _, column := range spec.Columns {
+		skip := column.Kind == specifications.Pk || column.Kind == specifications.Aol ||
+			column.Kind == specifications.Amb || column.Kind == specifications.Amt ||
+			column.Kind == specifications.Adb || column.Kind == specifications.Adt ||
+			column.Kind == specifications.Virtual ||
+			column.Kind == specifications.Link || column.Kind == specifications.Links
+		if skip {
+			continue
+		}
+		if column.Incr() {
+			returning = append(returning, column.Field)
+			continue
+		}
+
+		columnName := ctx.FormatIdent(column.Name)
+		if n > 0 {
+			_, _ = buf.Write(specifications.COMMA)
+		}
+		_, _ = buf.WriteString(columnName)
+		vr.Add()
+		fields = append(fields, column.Field)
+		n++
+	}
+
+	_, _ = buf.Write(specifications.RB)
+	_, _ = buf.Write(specifications.SPACE)
+
+	// values
+	_, _ = buf.Write(specifications.VALUES)
+	_, _ = buf.Write(specifications.SPACE)
+
+	query = []byte(buf.String())
+	return
+}
@@ -0,0 +1,64 @@
+package inserts
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"io"
+)
+
+// NewInsertGeneric
+// clickhouse has no auto increment column and RETURNING,
+// so insert of table which has incr columns is failed when it is rendered.
+// conflicts are ignored, use engine such as ReplacingMergeTree to deduplicate.
+func NewInsertGeneric(ctx specifications.Context, spec *specifications.Specification) (generic *InsertGeneric, err error) {
+	if spec.View {
+		generic = &InsertGeneric{}
+		return
+	}
+	query, vr, fields, returning, generateErr := generateInsertQuery(ctx, spec)
+	if generateErr != nil {
+		err = errors.Warning("sql: new insert generic failed").WithCause(generateErr).WithMeta("table", spec.Key)
+		return
+	}
+	var unsupported error
+	if len(returning) > 0 {
+		unsupported = fmt.Errorf("returning of incr fields %v is not supported by clickhouse", returning)
+	}
+	generic = &InsertGeneric{
+		spec:        spec,
+		content:     query,
+		vr:          vr,
+		fields:      fields,
+		unsupported: unsupported,
+	}
+	return
+}
+
+type InsertGeneric struct {
+	spec        *specifications.Specification
+	content     []byte
+	vr          ValueRender
+	fields      []string
+	unsupported error
+}
+
+func (generic *InsertGeneric) Render(ctx specifications.Context, w io.Writer, values int) (method specifications.Method, fields []string, returning []string, err error) {
+	if generic.unsupported != nil {
+		err = generic.unsupported
+		return
+	}
+	method = specifications.ExecuteMethod
+	fields = generic.fields
+
+	_, _ = w.Write(generic.content)
+
+	for i := 0; i < values; i++ {
+		if i > 0 {
+			_, _ = w.Write(specifications.COMMA)
+		}
+		_ = generic.vr.Render(ctx, w)
+	}
+
+	return
+}
@@ -0,0 +1,72 @@
+package modifiers
+
+import (
+	stdcontext "context"
+	"fmt"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/fns/context"
+	"io"
+	"strconv"
+)
+
+var (
+	contextKey = []byte("@fns:sql:clickhouse:modifiers")
+	FINAL      = []byte("FINAL")
+	SAMPLE     = []byte("SAMPLE")
+)
+
+// Modifiers
+// modifiers of table in select.
+type Modifiers struct {
+	// Final
+	// merge rows before select, such as ReplacingMergeTree.
+	Final bool
+	// Sample
+	// relative coefficient when it is in (0, 1], otherwise is approximate number of rows.
+	// table must have sampling key.
+	Sample float64
+}
+
+func (modifiers Modifiers) Exist() bool {
+	return modifiers.Final || modifiers.Sample > 0
+}
+
+func With(ctx context.Context, modifiers Modifiers) {
+	if !modifiers.Exist() {
+		return
+	}
+	ctx.SetLocalValue(contextKey, modifiers)
+}
+
+func Remove(ctx context.Context) {
+	ctx.RemoveLocalValue(contextKey)
+}
+
+// Load
+// ctx can be specifications.Context, which is not fns context, so Value is used.
+func Load(ctx stdcontext.Context) (modifiers Modifiers) {
+	modifiers, _ = ctx.Value(contextKey).(Modifiers)
+	return
+}
+
+// Render
+// FINAL SAMPLE x, it must be written after table name.
+func Render(ctx specifications.Context, w io.Writer) (err error) {
+	modifiers := Load(ctx)
+	if modifiers.Final {
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(FINAL)
+	}
+	if modifiers.Sample < 0 {
+		err = fmt.Errorf("sample must be greater than 0")
+		return
+	}
+	if modifiers.Sample > 0 {
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(SAMPLE)
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(bytex.FromString(strconv.FormatFloat(modifiers.Sample, 'f', -1, 64)))
+	}
+	return
+}
@@ -0,0 +1,25 @@
+package dialect
+
+const (
+	query = "?"
+)
+
+type Placeholder struct {
+}
+
+func (ph *Placeholder) Next() string {
+	return query
+}
+
+func (ph *Placeholder) SkipCursor(_ int) {
+}
+
+func (ph *Placeholder) Current() string {
+	return query
+}
+
+// BackslashEscapes
+// backslash escapes quote in string literals.
+func (ph *Placeholder) BackslashEscapes() bool {
+	return true
+}
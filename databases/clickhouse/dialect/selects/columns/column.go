@@ -0,0 +1,27 @@
+package columns
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+)
+
+// Fragment
+// reference, link and links are not supported, cause correlated sub query is not supported by clickhouse.
+func Fragment(ctx specifications.Context, spec *specifications.Specification, column *specifications.Column) (fragment string, err error) {
+	switch column.Kind {
+	case specifications.Reference, specifications.Link, specifications.Links:
+		err = errors.Warning("sql: render field failed").
+			WithCause(fmt.Errorf("kind of %s is not supported by clickhouse", column.Field)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		break
+	case specifications.Virtual:
+		fragment, err = Virtual(ctx, spec, column)
+		break
+	default:
+		fragment = ctx.FormatIdent(column.Name)
+		break
+	}
+	return
+}
@@ -0,0 +1,12 @@
+package clickhouse
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/clickhouse/generators"
+	"github.com/aacfactory/fns/cmd/generates/modules"
+)
+
+func FAG() []modules.FnAnnotationCodeWriter {
+	return []modules.FnAnnotationCodeWriter{
+		&generators.UseWriter{},
+	}
+}
@@ -0,0 +1,10 @@
+package clickhouse
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/clickhouse/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+)
+
+func init() {
+	specifications.RegisterDialect(dialect.NewDialect())
+}
@@ -0,0 +1,20 @@
+package clickhouse
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/clickhouse/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns/context"
+)
+
+func Insert[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	v, ok, err = dac.Insert[T](ctx, entry)
+	return
+}
+
+func InsertMulti[T Table](ctx context.Context, entries []T) (affected int64, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	affected, err = dac.InsertMulti[T](ctx, entries)
+	return
+}
@@ -0,0 +1,52 @@
+package clickhouse_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/clickhouse"
+	"github.com/aacfactory/fns-contrib/databases/clickhouse/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type Event struct {
+	Id   string `column:"ID,pk"`
+	Kind string `column:"KIND"`
+}
+
+func (event Event) TableInfo() dac.TableInfo {
+	return dac.Info("EVENT")
+}
+
+func TestInsert(t *testing.T) {
+	db := sqltest.New()
+	// clickhouse-go reports zero affected rows
+	db.ExpectExecute("INSERT INTO `EVENT`").WithArgs("1", "click").WillReturnResult(0, 0)
+	db.ExpectExecute("INSERT INTO `EVENT`").WithArgs("2", "click", "3", "view").WillReturnResult(0, 0)
+	ctx := sqltest.Context(dialect.Name)
+	if err := sqltest.Use(ctx, db); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+
+	v, ok, err := clickhouse.Insert[Event](ctx, Event{Id: "1", Kind: "click"})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if !ok || v.Id != "1" {
+		t.Errorf("insert must be succeeded when no error occurred")
+		return
+	}
+	affected, multiErr := clickhouse.InsertMulti[Event](ctx, []Event{{Id: "2", Kind: "click"}, {Id: "3", Kind: "view"}})
+	if multiErr != nil {
+		t.Errorf("%+v", multiErr)
+		return
+	}
+	if affected != 2 {
+		t.Errorf("affected must be number of entries, got %d", affected)
+		return
+	}
+	if err = db.ExpectationsWereMet(); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
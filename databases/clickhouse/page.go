@@ -0,0 +1,13 @@
+package clickhouse
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns/context"
+)
+
+func Page[T Table](ctx context.Context, no int, size int, options ...QueryOption) (page dac.Pager[T], err error) {
+	opts := acquireQueryOptions(ctx, options)
+	page, err = dac.Page[T](ctx, no, size, opts.options...)
+	releaseQueryOptions(ctx, opts)
+	return
+}
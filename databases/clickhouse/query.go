@@ -0,0 +1,119 @@
+package clickhouse
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/clickhouse/dialect"
+	"github.com/aacfactory/fns-contrib/databases/clickhouse/dialect/modifiers"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/groups"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/orders"
+	"github.com/aacfactory/fns/context"
+	"sync"
+)
+
+type QueryOptions struct {
+	options   []dac.QueryOption
+	modifiers modifiers.Modifiers
+}
+
+type QueryOption func(options *QueryOptions)
+
+func Conditions(cond conditions.Condition) QueryOption {
+	return func(options *QueryOptions) {
+		options.options = append(options.options, dac.Conditions(cond))
+	}
+}
+
+func Orders(order orders.Orders) QueryOption {
+	return func(options *QueryOptions) {
+		options.options = append(options.options, dac.Orders(order))
+	}
+}
+
+func Asc(name string) orders.Orders {
+	return orders.Asc(name)
+}
+
+func Desc(name string) orders.Orders {
+	return orders.Desc(name)
+}
+
+func GroupBy(by groups.GroupBy) QueryOption {
+	return func(options *QueryOptions) {
+		options.options = append(options.options, dac.GroupBy(by))
+	}
+}
+
+func NoCache() QueryOption {
+	return func(options *QueryOptions) {
+		options.options = append(options.options, dac.NoCache())
+	}
+}
+
+// Final
+// FROM table FINAL, rows are merged before select, such as ReplacingMergeTree and CollapsingMergeTree.
+func Final() QueryOption {
+	return func(options *QueryOptions) {
+		options.modifiers.Final = true
+	}
+}
+
+// Sample
+// FROM table SAMPLE n, n is relative coefficient when it is in (0, 1], otherwise is approximate number of rows.
+// table must have sampling key.
+func Sample(n float64) QueryOption {
+	return func(options *QueryOptions) {
+		options.modifiers.Sample = n
+	}
+}
+
+var (
+	queryOptionsPool = sync.Pool{New: func() any {
+		return &QueryOptions{
+			options: make([]dac.QueryOption, 0, 3),
+		}
+	}}
+)
+
+// acquireQueryOptions
+// modifiers are stored in context until options are released.
+func acquireQueryOptions(ctx context.Context, options []QueryOption) *QueryOptions {
+	sql.ForceDialect(ctx, dialect.Name)
+	opts := queryOptionsPool.Get().(*QueryOptions)
+	for _, option := range options {
+		option(opts)
+	}
+	modifiers.With(ctx, opts.modifiers)
+	return opts
+}
+
+func releaseQueryOptions(ctx context.Context, options *QueryOptions) {
+	if options.modifiers.Exist() {
+		modifiers.Remove(ctx)
+	}
+	options.options = options.options[:0]
+	options.modifiers = modifiers.Modifiers{}
+	queryOptionsPool.Put(options)
+}
+
+func Query[T Table](ctx context.Context, offset int, length int, options ...QueryOption) (entries []T, err error) {
+	opts := acquireQueryOptions(ctx, options)
+	entries, err = dac.Query[T](ctx, offset, length, opts.options...)
+	releaseQueryOptions(ctx, opts)
+	return
+}
+
+func One[T Table](ctx context.Context, options ...QueryOption) (entry T, has bool, err error) {
+	opts := acquireQueryOptions(ctx, options)
+	entry, has, err = dac.One[T](ctx, opts.options...)
+	releaseQueryOptions(ctx, opts)
+	return
+}
+
+func ALL[T Table](ctx context.Context, options ...QueryOption) (entries []T, err error) {
+	opts := acquireQueryOptions(ctx, options)
+	entries, err = dac.ALL[T](ctx, opts.options...)
+	releaseQueryOptions(ctx, opts)
+	return
+}
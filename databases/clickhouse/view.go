@@ -0,0 +1,27 @@
+package clickhouse
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns/context"
+)
+
+func Views[V View](ctx context.Context, offset int, length int, options ...QueryOption) (entries []V, err error) {
+	opts := acquireQueryOptions(ctx, options)
+	entries, err = dac.Views[V](ctx, offset, length, opts.options...)
+	releaseQueryOptions(ctx, opts)
+	return
+}
+
+func ViewOne[V View](ctx context.Context, options ...QueryOption) (entry V, has bool, err error) {
+	opts := acquireQueryOptions(ctx, options)
+	entry, has, err = dac.ViewOne[V](ctx, opts.options...)
+	releaseQueryOptions(ctx, opts)
+	return
+}
+
+func ViewALL[V View](ctx context.Context, options ...QueryOption) (entries []V, err error) {
+	opts := acquireQueryOptions(ctx, options)
+	entries, err = dac.ViewALL[V](ctx, opts.options...)
+	releaseQueryOptions(ctx, opts)
+	return
+}
@@ -3,6 +3,7 @@ package dialect
 import (
 	"fmt"
 	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/mysql/dialect/inserts"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
 	"github.com/aacfactory/fns/commons/bytex"
 	"github.com/valyala/bytebufferpool"
@@ -15,15 +16,19 @@ const (
 )
 
 func NewDialect() *Dialect {
-	return &Dialect{
+	dialect := &Dialect{
 		generics: &Generics{
 			values: sync.Map{},
 			group:  singleflight.Group{},
 		},
 	}
+	dialect.RecursiveTree = specifications.NewRecursiveTree(dialect)
+	return dialect
 }
 
 type Dialect struct {
+	specifications.RecursiveTree
+	specifications.StandardLock
 	generics *Generics
 }
 
@@ -88,6 +93,24 @@ func (dialect *Dialect) InsertOrUpdate(ctx specifications.Context, spec *specifi
 	return
 }
 
+// Upsert
+// insert or update with columns and condition of update branch, conflict target is not supported by mysql.
+func (dialect *Dialect) Upsert(ctx specifications.Context, spec *specifications.Specification, upsert specifications.Upsert) (method specifications.Method, query []byte, arguments []any, returning []string, err error) {
+	if spec.View {
+		err = errors.Warning("sql: dialect generate upsert failed").WithMeta("table", spec.Key).WithCause(fmt.Errorf("spec is view")).WithMeta("dialect", Name)
+		return
+	}
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+	method, arguments, returning, err = inserts.RenderUpsert(ctx, spec, upsert, buf)
+	if err != nil {
+		err = errors.Warning("sql: dialect generate upsert failed").WithMeta("table", spec.Key).WithCause(err).WithMeta("dialect", Name)
+		return
+	}
+	query = []byte(buf.String())
+	return
+}
+
 func (dialect *Dialect) InsertWhenExist(ctx specifications.Context, spec *specifications.Specification, src specifications.QueryExpr) (method specifications.Method, query []byte, fields []string, arguments []any, returning []string, err error) {
 	generic, has, getErr := dialect.generics.Get(ctx, spec)
 	if getErr != nil {
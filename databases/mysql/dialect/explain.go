@@ -0,0 +1,112 @@
+package dialect
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+var (
+	explainFormatJson = []byte("FORMAT=JSON")
+)
+
+// RenderExplain
+// EXPLAIN FORMAT=JSON
+func (dialect *Dialect) RenderExplain(_ specifications.Context, w io.Writer) (err error) {
+	_, _ = w.Write(specifications.EXPLAIN)
+	_, _ = w.Write(specifications.SPACE)
+	_, _ = w.Write(explainFormatJson)
+	return
+}
+
+// ParsePlan
+// {"query_block": {"table": {"table_name": "...", "access_type": "ALL"}, "nested_loop": [{"table": {...}}]}}
+func (dialect *Dialect) ParsePlan(p []byte) (root specifications.PlanNode, err error) {
+	explain := make(map[string]any)
+	decodeErr := json.Unmarshal(p, &explain)
+	if decodeErr != nil {
+		err = errors.Warning("sql: dialect parse plan failed").WithCause(decodeErr).WithMeta("dialect", Name)
+		return
+	}
+	block, ok := explain["query_block"].(map[string]any)
+	if !ok {
+		err = errors.Warning("sql: dialect parse plan failed").WithCause(fmt.Errorf("query_block was not found")).WithMeta("dialect", Name)
+		return
+	}
+	root = explainNode("query_block", block)
+	return
+}
+
+func explainNode(name string, v map[string]any) (node specifications.PlanNode) {
+	node = specifications.PlanNode{
+		Type:     name,
+		Children: make([]specifications.PlanNode, 0, 1),
+	}
+	if name == "table" {
+		node.Relation, _ = v["table_name"].(string)
+		node.Index, _ = v["key"].(string)
+		node.Filter, _ = v["attached_condition"].(string)
+		accessType, _ := v["access_type"].(string)
+		if accessType != "" {
+			node.Type = accessType
+		}
+		node.SequentialScan = accessType == "ALL"
+		node.Rows = explainNumber(v["rows_examined_per_scan"])
+	}
+	if costs, ok := v["cost_info"].(map[string]any); ok {
+		if cost, has := costs["query_cost"]; has {
+			node.Cost = explainNumber(cost)
+		} else {
+			node.Cost = explainNumber(costs["prefix_cost"])
+		}
+	}
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "cost_info" {
+			continue
+		}
+		switch child := v[key].(type) {
+		case map[string]any:
+			node.Children = append(node.Children, explainNode(key, child))
+			break
+		case []any:
+			for _, item := range child {
+				element, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				for elementKey, elementValue := range element {
+					if elementNode, isNode := elementValue.(map[string]any); isNode {
+						node.Children = append(node.Children, explainNode(elementKey, elementNode))
+					}
+				}
+			}
+			break
+		default:
+			break
+		}
+	}
+	return
+}
+
+func explainNumber(v any) (n float64) {
+	switch value := v.(type) {
+	case float64:
+		n = value
+		break
+	case string:
+		n, _ = strconv.ParseFloat(value, 64)
+		break
+	default:
+		break
+	}
+	return
+}
@@ -48,12 +48,8 @@ func NewInsertOrUpdateGeneric(ctx specifications.Context, spec *specifications.S
 		_, _ = buf.Write(specifications.SPACE)
 		_, _ = buf.Write(KEY)
 		_, _ = buf.Write(specifications.SPACE)
-		_, _ = buf.Write(specifications.DO)
-		_, _ = buf.Write(specifications.SPACE)
 		_, _ = buf.Write(specifications.UPDATE)
 		_, _ = buf.Write(specifications.SPACE)
-		_, _ = buf.Write(specifications.SET)
-		_, _ = buf.Write(specifications.SPACE)
 
 		n := 0
 		for _, column := range spec.Columns {
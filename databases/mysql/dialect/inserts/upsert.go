@@ -0,0 +1,152 @@
+package inserts
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/valyala/bytebufferpool"
+	"io"
+)
+
+// RenderUpsert
+// INSERT INTO {table} ({columns}) VALUES ({values}) ON DUPLICATE KEY UPDATE {column} = VALUES({column}), {column} = {column} + VALUES({column}).
+// mysql has no conflict target, so conflicts and constraint are not supported, any unique key triggers update.
+// mysql has no where of update branch, so cond is rendered in each column, such as
+// {column} = IF(({cond}), {value}, {column}), and arguments of cond are repeated.
+// assignments are evaluated in order with updated values, so the column which is referred by cond is the last one,
+// and cond which refers more than one updated column is not supported.
+func RenderUpsert(ctx specifications.Context, spec *specifications.Specification, upsert specifications.Upsert, w io.Writer) (method specifications.Method, arguments []any, returning []string, err error) {
+	method = specifications.ExecuteMethod
+	if len(upsert.Conflicts) > 0 || upsert.Constraint != "" {
+		err = errors.Warning("sql: render upsert failed").WithCause(fmt.Errorf("conflict target is not supported by mysql, unique keys of table are used")).WithMeta("table", spec.Key)
+		return
+	}
+
+	query, vr, fields, insertReturning, generateErr := generateInsertQuery(ctx, spec)
+	if generateErr != nil {
+		err = errors.Warning("sql: render upsert failed").WithCause(generateErr).WithMeta("table", spec.Key)
+		return
+	}
+	returning = insertReturning
+
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+	_, _ = buf.Write(query)
+	_ = vr.Render(ctx, buf)
+	for _, field := range fields {
+		arguments = append(arguments, specifications.FieldArgument(field))
+	}
+
+	columns, columnsErr := specifications.UpsertColumns(spec, upsert)
+	if columnsErr != nil {
+		err = errors.Warning("sql: render upsert failed").WithCause(columnsErr)
+		return
+	}
+	cond, condArguments, condErr := specifications.RenderUpsertCond(ctx, upsert)
+	if condErr != nil {
+		err = errors.Warning("sql: render upsert failed").WithCause(condErr).WithMeta("table", spec.Key)
+		return
+	}
+	var ver *specifications.UpsertColumn
+	var referred *specifications.UpsertColumn
+	sets := make([]specifications.UpsertColumn, 0, len(columns))
+	for i, column := range columns {
+		if len(cond) > 0 && bytes.Contains(cond, []byte(ctx.FormatIdent(column.Name))) {
+			if referred != nil {
+				err = errors.Warning("sql: render upsert failed").WithCause(fmt.Errorf("cond of update which refers more than one updated column is not supported by mysql")).WithMeta("table", spec.Key)
+				return
+			}
+			referred = &columns[i]
+			continue
+		}
+		if column.Kind == specifications.Aol {
+			ver = &columns[i]
+			continue
+		}
+		sets = append(sets, column)
+	}
+	if ver != nil {
+		sets = append(sets, *ver)
+	}
+	if referred != nil {
+		sets = append(sets, *referred)
+	}
+
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.ON)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(DUPLICATE)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(KEY)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.UPDATE)
+	_, _ = buf.Write(specifications.SPACE)
+	for i, column := range sets {
+		if i > 0 {
+			_, _ = buf.Write(specifications.COMMA)
+		}
+		columnName := ctx.FormatIdent(column.Name)
+		_, _ = buf.WriteString(columnName)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.EQ)
+		_, _ = buf.Write(specifications.SPACE)
+		if len(cond) > 0 {
+			_, _ = buf.Write(specifications.IF)
+			_, _ = buf.Write(specifications.LB)
+			_, _ = buf.Write(specifications.LB)
+			_, _ = buf.Write(cond)
+			_, _ = buf.Write(specifications.RB)
+			_, _ = buf.Write(specifications.COMMA)
+			arguments = append(arguments, condArguments...)
+		}
+		switch column.Kind {
+		case specifications.Aol:
+			_, _ = buf.WriteString(columnName)
+			_, _ = buf.Write(specifications.PLUS)
+			_, _ = buf.Write([]byte("1"))
+			break
+		case specifications.Amb, specifications.Amt:
+			_, _ = buf.WriteString(ctx.NextQueryPlaceholder())
+			arguments = append(arguments, specifications.FieldArgument(column.Field))
+			break
+		default:
+			if column.Mode == specifications.UpsertIncr {
+				_, _ = buf.WriteString(columnName)
+				_, _ = buf.Write(specifications.SPACE)
+				_, _ = buf.Write(specifications.PLUS)
+				_, _ = buf.Write(specifications.SPACE)
+			}
+			_, _ = buf.Write(specifications.VALUES)
+			_, _ = buf.Write(specifications.LB)
+			_, _ = buf.WriteString(columnName)
+			_, _ = buf.Write(specifications.RB)
+			break
+		}
+		if len(cond) > 0 {
+			_, _ = buf.Write(specifications.COMMA)
+			_, _ = buf.WriteString(columnName)
+			_, _ = buf.Write(specifications.RB)
+		}
+	}
+
+	// returning
+	if len(returning) > 0 {
+		method = specifications.QueryMethod
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.RETURNING)
+		_, _ = buf.Write(specifications.SPACE)
+		for i, r := range returning {
+			if i > 0 {
+				_, _ = buf.Write(specifications.COMMA)
+			}
+			column, has := spec.ColumnByField(r)
+			if has {
+				_, _ = buf.WriteString(ctx.FormatIdent(column.Name))
+			}
+		}
+	}
+
+	_, err = w.Write([]byte(buf.String()))
+	return
+}
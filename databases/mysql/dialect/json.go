@@ -0,0 +1,146 @@
+package dialect
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
+	"io"
+	"strings"
+)
+
+var (
+	jsonContains     = []byte("JSON_CONTAINS")
+	jsonContainsPath = []byte("JSON_CONTAINS_PATH")
+	jsonOverlaps     = []byte("JSON_OVERLAPS")
+	jsonExtract      = []byte("JSON_EXTRACT")
+	memberOf         = []byte("MEMBER OF")
+	one              = []byte("'one'")
+)
+
+const (
+	castAsJson = "CAST(%s AS JSON)"
+)
+
+// RenderJsonPredicate
+// JSON_CONTAINS, JSON_CONTAINS_PATH, JSON_OVERLAPS (8.0.17), MEMBER OF (8.0.17) and comparison of JSON_EXTRACT.
+func (dialect *Dialect) RenderJsonPredicate(ctx specifications.Context, w io.Writer, column string, operator conditions.Operator, expr conditions.Json) (arguments []any, err error) {
+	switch operator {
+	case conditions.JSONCONTAINS, conditions.JSONOVERLAPS:
+		value, valueErr := specifications.EncodeJsonValue(expr.Value)
+		if valueErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(valueErr).WithMeta("dialect", Name)
+			return
+		}
+		if operator == conditions.JSONCONTAINS {
+			_, _ = w.Write(jsonContains)
+		} else {
+			_, _ = w.Write(jsonOverlaps)
+		}
+		_, _ = w.Write(specifications.LB)
+		_, _ = w.Write(bytex.FromString(column))
+		_, _ = w.Write(specifications.COMMA)
+		_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+		_, _ = w.Write(specifications.RB)
+		arguments = append(arguments, value)
+		break
+	case conditions.JSONHASKEY:
+		path, pathErr := jsonPath(expr.Path)
+		if pathErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(pathErr).WithMeta("dialect", Name)
+			return
+		}
+		_, _ = w.Write(jsonContainsPath)
+		_, _ = w.Write(specifications.LB)
+		_, _ = w.Write(bytex.FromString(column))
+		_, _ = w.Write(specifications.COMMA)
+		_, _ = w.Write(one)
+		_, _ = w.Write(specifications.COMMA)
+		_, _ = w.Write(bytex.FromString(path))
+		_, _ = w.Write(specifications.RB)
+		break
+	case conditions.JSONMEMBEROF:
+		value, valueErr := specifications.EncodeJsonValue(expr.Value)
+		if valueErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(valueErr).WithMeta("dialect", Name)
+			return
+		}
+		_, _ = w.Write(bytex.FromString(fmt.Sprintf(castAsJson, ctx.NextQueryPlaceholder())))
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(memberOf)
+		_, _ = w.Write(specifications.LB)
+		_, _ = w.Write(bytex.FromString(column))
+		_, _ = w.Write(specifications.RB)
+		arguments = append(arguments, value)
+		break
+	case conditions.Equal, conditions.NotEqual, conditions.GreatThan, conditions.GreatThanOrEqual, conditions.LessThan, conditions.LessThanOrEqual:
+		value, valueErr := specifications.EncodeJsonValue(expr.Value)
+		if valueErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(valueErr).WithMeta("dialect", Name)
+			return
+		}
+		_, err = dialect.RenderJsonPathOrder(ctx, w, column, expr.Path)
+		if err != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(err).WithMeta("dialect", Name)
+			return
+		}
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(operator.Bytes())
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(bytex.FromString(fmt.Sprintf(castAsJson, ctx.NextQueryPlaceholder())))
+		arguments = append(arguments, value)
+		break
+	default:
+		err = errors.Warning("sql: render json predicate failed").WithCause(fmt.Errorf("%s is not supported", operator)).WithMeta("dialect", Name)
+		return
+	}
+	return
+}
+
+// RenderJsonPathOrder
+// JSON_EXTRACT(column, path), so values are compared as json values.
+func (dialect *Dialect) RenderJsonPathOrder(_ specifications.Context, w io.Writer, column string, path string) (arguments []any, err error) {
+	p, pathErr := jsonPath(path)
+	if pathErr != nil {
+		err = errors.Warning("sql: render json path failed").WithCause(pathErr).WithMeta("dialect", Name)
+		return
+	}
+	_, _ = w.Write(jsonExtract)
+	_, _ = w.Write(specifications.LB)
+	_, _ = w.Write(bytex.FromString(column))
+	_, _ = w.Write(specifications.COMMA)
+	_, _ = w.Write(bytex.FromString(p))
+	_, _ = w.Write(specifications.RB)
+	return
+}
+
+// jsonPath
+// convert `a.b.0` to '$."a"."b"[0]'
+func jsonPath(path string) (v string, err error) {
+	keys := specifications.JsonPathKeys(path)
+	if len(keys) == 0 {
+		err = fmt.Errorf("path is required")
+		return
+	}
+	sb := strings.Builder{}
+	sb.WriteString("'$")
+	for _, key := range keys {
+		if specifications.IsJsonPathIndex(key) {
+			sb.WriteByte('[')
+			sb.WriteString(key)
+			sb.WriteByte(']')
+			continue
+		}
+		if strings.ContainsAny(key, `\"'`) {
+			err = fmt.Errorf("invalid key %s of path", key)
+			return
+		}
+		sb.WriteString(`."`)
+		sb.WriteString(key)
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('\'')
+	v = sb.String()
+	return
+}
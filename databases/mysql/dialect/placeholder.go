@@ -17,3 +17,9 @@ func (ph *Placeholder) SkipCursor(_ int) {
 func (ph *Placeholder) Current() string {
 	return query
 }
+
+// BackslashEscapes
+// backslash escapes quote in string literals.
+func (ph *Placeholder) BackslashEscapes() bool {
+	return true
+}
@@ -40,6 +40,14 @@ func Virtual(ctx specifications.Context, spec *specifications.Specification, col
 		_, _ = buf.Write(specifications.SPACE)
 		_, _ = buf.WriteString(ctx.FormatIdent(fmt.Sprintf("%s_%s", column.Name, query)))
 		break
+	case specifications.WindowVirtualQuery:
+		window, _ := column.Window()
+		specifications.RenderWindow(ctx, buf, window)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.AS)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.WriteString(name)
+		break
 	default:
 		err = errors.Warning("sql: render virtual field failed").
 			WithCause(fmt.Errorf("kind of %s is not valid virtual", column.Field)).
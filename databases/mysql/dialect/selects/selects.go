@@ -5,10 +5,9 @@ import (
 	"github.com/aacfactory/errors"
 	"github.com/aacfactory/fns-contrib/databases/mysql/dialect/selects/columns"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
 	"github.com/valyala/bytebufferpool"
 	"io"
-	"strconv"
-	"unsafe"
 )
 
 func NewQueryGeneric(ctx specifications.Context, spec *specifications.Specification) (generic *QueryGeneric, err error) {
@@ -78,24 +77,21 @@ func (generic *QueryGeneric) Render(ctx specifications.Context, w io.Writer, con
 
 	if len(orders) > 0 {
 		_, _ = w.Write(specifications.SPACE)
-		_, orderErr := orders.Render(ctx, w)
+		orderArguments, orderErr := orders.Render(ctx, w)
 		if orderErr != nil {
 			err = orderErr
 			return
 		}
+		arguments = append(arguments, orderArguments...)
 	}
 
 	if length > 0 {
-		_, _ = w.Write(specifications.SPACE)
-		_, _ = w.Write(specifications.OFFSET)
-		_, _ = w.Write(specifications.SPACE)
-		os := strconv.Itoa(offset)
-		_, _ = w.Write(unsafe.Slice(unsafe.StringData(os), len(os)))
 		_, _ = w.Write(specifications.SPACE)
 		_, _ = w.Write(specifications.LIMIT)
 		_, _ = w.Write(specifications.SPACE)
-		ls := strconv.Itoa(length)
-		_, _ = w.Write(unsafe.Slice(unsafe.StringData(ls), len(ls)))
+		_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+		_, _ = w.Write(specifications.COMMA)
+		_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
 	}
 
 	return
@@ -0,0 +1,69 @@
+package dialect
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
+	"io"
+)
+
+var (
+	stDistance   = []byte("ST_Distance")
+	stIntersects = []byte("ST_Intersects")
+	stContains   = []byte("ST_Contains")
+	lte          = []byte("<=")
+)
+
+// EncodeGeometry
+// mysql accepts internal format (srid + wkb) as geometry value.
+func (dialect *Dialect) EncodeGeometry(geometry sql.Geometry) (v any, err error) {
+	v, err = geometry.MysqlInternal()
+	return
+}
+
+// RenderSpatialPredicate
+// distance of ST_Distance is meter when srid of column is geographic.
+func (dialect *Dialect) RenderSpatialPredicate(ctx specifications.Context, w io.Writer, column string, _ bool, operator conditions.Operator, spatial conditions.Spatial) (arguments []any, err error) {
+	switch operator {
+	case conditions.DWITHIN:
+		_, _ = w.Write(stDistance)
+		break
+	case conditions.INTERSECTS:
+		_, _ = w.Write(stIntersects)
+		break
+	case conditions.CONTAINS:
+		_, _ = w.Write(stContains)
+		break
+	default:
+		err = errors.Warning("sql: render spatial predicate failed").WithCause(fmt.Errorf("%s is not supported", operator)).WithMeta("dialect", Name)
+		return
+	}
+	_, _ = w.Write(specifications.LB)
+	_, _ = w.Write(bytex.FromString(column))
+	_, _ = w.Write(specifications.COMMA)
+	_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+	_, _ = w.Write(specifications.RB)
+	arguments = append(arguments, spatial.Geometry)
+	if operator == conditions.DWITHIN {
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(lte)
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+		arguments = append(arguments, spatial.Distance)
+	}
+	return
+}
+
+func (dialect *Dialect) RenderNearestOrder(ctx specifications.Context, w io.Writer, column string, _ bool, geometry sql.Geometry) (arguments []any, err error) {
+	_, _ = w.Write(stDistance)
+	_, _ = w.Write(specifications.LB)
+	_, _ = w.Write(bytex.FromString(column))
+	_, _ = w.Write(specifications.COMMA)
+	_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+	_, _ = w.Write(specifications.RB)
+	arguments = append(arguments, geometry)
+	return
+}
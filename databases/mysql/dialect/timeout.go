@@ -0,0 +1,43 @@
+package dialect
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+var (
+	maxExecutionTime = []byte("MAX_EXECUTION_TIME")
+	selectPrefix     = []byte("SELECT")
+)
+
+// Hint
+// SELECT /*+ MAX_EXECUTION_TIME(n) */ ..., it is only valid for select.
+func (dialect *Dialect) Hint(query []byte, timeout time.Duration) []byte {
+	ms := timeout.Milliseconds()
+	if ms <= 0 {
+		return query
+	}
+	trimmed := bytes.TrimLeft(query, " \t\r\n")
+	if len(trimmed) <= len(selectPrefix) || !bytes.EqualFold(trimmed[:len(selectPrefix)], selectPrefix) {
+		return query
+	}
+	if c := trimmed[len(selectPrefix)]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+		return query
+	}
+	if bytes.Contains(query, maxExecutionTime) {
+		return query
+	}
+	hint := fmt.Sprintf(" /*+ MAX_EXECUTION_TIME(%d) */", ms)
+	v := make([]byte, 0, len(trimmed)+len(hint))
+	v = append(v, trimmed[:len(selectPrefix)]...)
+	v = append(v, hint...)
+	v = append(v, trimmed[len(selectPrefix):]...)
+	return v
+}
+
+// Session
+// mysql does not use session limit, because it is kept in connection after transaction.
+func (dialect *Dialect) Session(_ time.Duration) []byte {
+	return nil
+}
@@ -5,10 +5,9 @@ import (
 	"github.com/aacfactory/errors"
 	"github.com/aacfactory/fns-contrib/databases/mysql/dialect/selects/columns"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
 	"github.com/valyala/bytebufferpool"
 	"io"
-	"strconv"
-	"unsafe"
 )
 
 func NewViewGeneric(ctx specifications.Context, spec *specifications.Specification) (generic *ViewGeneric, err error) {
@@ -74,47 +73,51 @@ func (generic *ViewGeneric) Render(ctx specifications.Context, w io.Writer, cond
 	method = specifications.QueryMethod
 	fields = generic.fields
 
+	arguments, err = specifications.RenderWith(ctx, w, generic.spec)
+	if err != nil {
+		return
+	}
+
 	_, _ = w.Write(generic.content)
 
 	if cond.Exist() {
 		_, _ = w.Write(specifications.SPACE)
 		_, _ = w.Write(specifications.WHERE)
 		_, _ = w.Write(specifications.SPACE)
-		arguments, err = cond.Render(ctx, w)
-		if err != nil {
+		condArguments, condErr := cond.Render(ctx, w)
+		if condErr != nil {
+			err = condErr
 			return
 		}
+		arguments = append(arguments, condArguments...)
 	}
 
-	if len(orders) > 0 {
+	if groupBy.Exist() {
 		_, _ = w.Write(specifications.SPACE)
-		_, orderErr := orders.Render(ctx, w)
-		if orderErr != nil {
-			err = orderErr
+		_, groupByErr := groupBy.Render(specifications.SwitchKey(ctx, generic.spec.Instance()), w)
+		if groupByErr != nil {
+			err = groupByErr
 			return
 		}
 	}
 
-	if groupBy.Exist() {
+	if len(orders) > 0 {
 		_, _ = w.Write(specifications.SPACE)
-		_, groupByErr := groupBy.Render(specifications.SwitchKey(ctx, generic.spec.Instance()), w)
-		if groupByErr != nil {
-			err = groupByErr
+		orderArguments, orderErr := orders.Render(ctx, w)
+		if orderErr != nil {
+			err = orderErr
 			return
 		}
+		arguments = append(arguments, orderArguments...)
 	}
 
 	if length > 0 {
-		_, _ = w.Write(specifications.SPACE)
-		_, _ = w.Write(specifications.OFFSET)
-		_, _ = w.Write(specifications.SPACE)
-		os := strconv.Itoa(offset)
-		_, _ = w.Write(unsafe.Slice(unsafe.StringData(os), len(os)))
 		_, _ = w.Write(specifications.SPACE)
 		_, _ = w.Write(specifications.LIMIT)
 		_, _ = w.Write(specifications.SPACE)
-		ls := strconv.Itoa(length)
-		_, _ = w.Write(unsafe.Slice(unsafe.StringData(ls), len(ls)))
+		_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+		_, _ = w.Write(specifications.COMMA)
+		_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
 	}
 
 	return
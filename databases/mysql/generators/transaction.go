@@ -82,7 +82,7 @@ func (writer *TransactionWriter) HandleBefore(ctx context.Context, params []stri
 
 	stmt.Tab().Token("if err = mysql.Begin(ctx")
 	if readonly {
-		stmt.Token(", mysql.Readonly()")
+		stmt.Token(", mysql.ReadOnly()")
 	}
 	if isolation != sql.LevelDefault {
 		stmt.Token(", mysql.WithIsolation(")
@@ -0,0 +1,45 @@
+package mysql_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/mysql/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"github.com/aacfactory/json"
+	"testing"
+)
+
+type Doc struct {
+	Id     string          `column:"ID,pk"`
+	Tags   json.RawMessage `column:"TAGS,json"`
+	Labels []string        `column:"LABELS,json"`
+}
+
+func (doc Doc) TableInfo() dac.TableInfo {
+	return dac.Info("DOC")
+}
+
+func TestJson(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := dac.JsonContains("Tags", json.RawMessage(`{"kind":"a"}`)).
+		And(dac.JsonHasKey("Tags", "meta.owner")).
+		And(dac.JsonMemberOf("Labels", "x")).
+		And(dac.JsonContainsAny("Labels", "y", "z")).
+		And(dac.JsonPathGte("Tags", "meta.items.0", 3))
+	orders := specifications.Orders(dac.DescJsonPath("Tags", "meta.level"))
+	_, query, arguments, _, err := specifications.BuildQuery[Doc](ctx, specifications.Condition{Condition: cond}, orders, 0, 0)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, "SELECT `ID`, `TAGS`, `LABELS` FROM `DOC` WHERE JSON_CONTAINS(`TAGS`, ?) AND JSON_CONTAINS_PATH(`TAGS`, 'one', '$.\"meta\".\"owner\"') AND CAST(? AS JSON) MEMBER OF(`LABELS`) AND JSON_OVERLAPS(`LABELS`, ?) AND JSON_EXTRACT(`TAGS`, '$.\"meta\".\"items\"[0]') >= CAST(? AS JSON) ORDER BY JSON_EXTRACT(`TAGS`, '$.\"meta\".\"level\"') DESC")
+	sqltest.AssertArguments(t, arguments, `{"kind":"a"}`, `"x"`, `["y","z"]`, `3`)
+}
+
+func TestJson_InvalidPath(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := dac.JsonHasKey("Tags", `meta.'owner`)
+	if _, _, _, _, err := specifications.BuildQuery[Doc](ctx, specifications.Condition{Condition: cond}, nil, 0, 0); err == nil {
+		t.Errorf("quote in path must be rejected")
+	}
+}
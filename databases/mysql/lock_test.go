@@ -0,0 +1,31 @@
+package mysql_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/mysql/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type Job struct {
+	Id     string `column:"ID,pk"`
+	Status string `column:"STATUS"`
+}
+
+func (job Job) TableInfo() dac.TableInfo {
+	return dac.Info("JOB")
+}
+
+func TestLock(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := specifications.Condition{Condition: dac.Eq("Status", "ready")}
+	lock := specifications.Lock{Mode: specifications.ForUpdateLock, Wait: specifications.SkipLockedLock}
+	_, query, arguments, _, err := specifications.BuildLockingQuery[Job](ctx, cond, nil, lock, 20, 10)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, "SELECT `ID`, `STATUS` FROM `JOB` WHERE `STATUS` = ? LIMIT ?, ? FOR UPDATE SKIP LOCKED")
+	sqltest.AssertArguments(t, arguments, "ready", 20, 10)
+}
@@ -0,0 +1,33 @@
+package mysql
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/json"
+)
+
+// ContainsJsonObject
+// JSON_CONTAINS(field, object)
+func ContainsJsonObject(field string, object json.RawMessage) conditions.Condition {
+	return conditions.New(conditions.JsonContains(field, object))
+}
+
+// ContainsJsonKey
+// JSON_CONTAINS_PATH(field, 'one', key), key can be dot separated path.
+func ContainsJsonKey(field string, key string) conditions.Condition {
+	return conditions.New(conditions.JsonHasKey(field, key))
+}
+
+// ContainsJsonObjectOfArray
+// object MEMBER OF(field)
+func ContainsJsonObjectOfArray(field string, object json.RawMessage) conditions.Condition {
+	return conditions.New(conditions.JsonMemberOf(field, object))
+}
+
+// ContainsJsonObjectsOfArray
+// JSON_CONTAINS(field, elements) when all, otherwise JSON_OVERLAPS(field, elements).
+func ContainsJsonObjectsOfArray(field string, all bool, elements ...any) conditions.Condition {
+	if all {
+		return conditions.New(conditions.JsonContainsAll(field, elements...))
+	}
+	return conditions.New(conditions.JsonContainsAny(field, elements...))
+}
@@ -0,0 +1,30 @@
+package mysql_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/mysql/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type User struct {
+	Id   string `column:"ID,pk"`
+	Name string `column:"NAME"`
+}
+
+func (user User) TableInfo() dac.TableInfo {
+	return dac.Info("USER")
+}
+
+func TestQuery_Limit(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := specifications.Condition{Condition: dac.Eq("Name", "foo")}
+	_, query, arguments, _, err := specifications.BuildQuery[User](ctx, cond, nil, 20, 10)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, "SELECT `ID`, `NAME` FROM `USER` WHERE `NAME` = ? LIMIT ?, ?")
+	sqltest.AssertArguments(t, arguments, "foo", 20, 10)
+}
@@ -11,6 +11,12 @@ func Begin(ctx context.Context, options ...databases.TransactionOption) (err err
 	return
 }
 
+// ReadOnly
+// transaction is read only, execute is rejected in it.
+func ReadOnly() databases.TransactionOption {
+	return sql.ReadOnly()
+}
+
 func Commit(ctx context.Context) (err error) {
 	err = sql.Commit(ctx)
 	return
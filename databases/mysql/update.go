@@ -16,7 +16,7 @@ func Update[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
 }
 
 func Field(name string, value any) FieldValues {
-	return FieldValues{{name, value}}
+	return FieldValues{{Name: name, Value: value}}
 }
 
 type FieldValues dac.FieldValues
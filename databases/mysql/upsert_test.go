@@ -0,0 +1,82 @@
+package mysql_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/mysql/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"strings"
+	"testing"
+)
+
+type Counter struct {
+	Id      string `column:"ID,pk"`
+	Name    string `column:"NAME"`
+	Count   int64  `column:"COUNT"`
+	Note    string `column:"NOTE"`
+	Version int64  `column:"VERSION,aol"`
+}
+
+func (counter Counter) TableInfo() dac.TableInfo {
+	return dac.Info("COUNTER", dac.Conflicts("Name"))
+}
+
+func TestUpsert(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	upsert := specifications.Upsert{
+		Sets: []specifications.UpsertSet{
+			{Field: "Count", Mode: specifications.UpsertIncr},
+			{Field: "Note", Mode: specifications.UpsertAssign},
+		},
+		Cond: specifications.Condition{Condition: dac.Lt("Count", 100)},
+	}
+	_, query, arguments, _, err := specifications.BuildUpsert[Counter](ctx, []Counter{{Id: "1", Name: "a", Count: 1, Note: "n"}}, upsert)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if strings.Contains(string(query), "@") {
+		t.Errorf("user variable must not be used, got %s", query)
+	}
+	// column which is referred by cond is the last one, so cond is evaluated with values before update
+	sqltest.AssertQuery(t, query, "INSERT INTO `COUNTER` (`ID`, `VERSION`, `NAME`, `COUNT`, `NOTE`) VALUES (?, 1, ?, ?, ?) ON DUPLICATE KEY UPDATE "+
+		"`NOTE` = IF((`COUNT` < ?), VALUES(`NOTE`), `NOTE`), `VERSION` = IF((`COUNT` < ?), `VERSION`+1, `VERSION`), `COUNT` = IF((`COUNT` < ?), `COUNT` + VALUES(`COUNT`), `COUNT`)")
+	sqltest.AssertArguments(t, arguments, "1", "a", int64(1), "n", 100, 100, 100)
+}
+
+func TestUpsert_CondReferredColumns(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	upsert := specifications.Upsert{
+		Sets: []specifications.UpsertSet{
+			{Field: "Count", Mode: specifications.UpsertIncr},
+			{Field: "Note", Mode: specifications.UpsertAssign},
+		},
+		Cond: specifications.Condition{Condition: dac.Lt("Count", 100).And(dac.Eq("Note", "n"))},
+	}
+	if _, _, _, _, err := specifications.BuildUpsert[Counter](ctx, []Counter{{Id: "1", Name: "a"}}, upsert); err == nil {
+		t.Errorf("cond which refers more than one updated column must be rejected by mysql")
+	}
+}
+
+func TestUpsert_ConflictTarget(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	entries := []Counter{{Id: "1", Name: "a"}}
+	if _, _, _, _, err := specifications.BuildUpsert[Counter](ctx, entries, specifications.Upsert{Conflicts: []string{"Name"}}); err == nil {
+		t.Errorf("conflicts must be rejected by mysql")
+	}
+	if _, _, _, _, err := specifications.BuildUpsert[Counter](ctx, entries, specifications.Upsert{Constraint: "COUNTER_NAME_UK"}); err == nil {
+		t.Errorf("constraint must be rejected by mysql")
+	}
+}
+
+func TestInsertOrUpdate(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	_, query, _, _, err := specifications.BuildInsertOrUpdate[Counter](ctx, []Counter{{Id: "1", Name: "a"}})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if !strings.Contains(string(query), "ON DUPLICATE KEY UPDATE `") {
+		t.Errorf("update branch must not have DO or SET, got %s", query)
+	}
+}
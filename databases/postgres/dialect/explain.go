@@ -0,0 +1,72 @@
+package dialect
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/json"
+	"io"
+)
+
+var (
+	explainFormatJson = []byte("(FORMAT JSON)")
+)
+
+// RenderExplain
+// EXPLAIN (FORMAT JSON)
+func (dialect *Dialect) RenderExplain(_ specifications.Context, w io.Writer) (err error) {
+	_, _ = w.Write(specifications.EXPLAIN)
+	_, _ = w.Write(specifications.SPACE)
+	_, _ = w.Write(explainFormatJson)
+	return
+}
+
+type explainPlan struct {
+	NodeType     string        `json:"Node Type"`
+	RelationName string        `json:"Relation Name"`
+	IndexName    string        `json:"Index Name"`
+	Filter       string        `json:"Filter"`
+	IndexCond    string        `json:"Index Cond"`
+	PlanRows     float64       `json:"Plan Rows"`
+	TotalCost    float64       `json:"Total Cost"`
+	Plans        []explainPlan `json:"Plans"`
+}
+
+func (plan explainPlan) node() (node specifications.PlanNode) {
+	node = specifications.PlanNode{
+		Type:           plan.NodeType,
+		Relation:       plan.RelationName,
+		Index:          plan.IndexName,
+		Filter:         plan.Filter,
+		Rows:           plan.PlanRows,
+		Cost:           plan.TotalCost,
+		SequentialScan: plan.NodeType == "Seq Scan",
+		Children:       make([]specifications.PlanNode, 0, len(plan.Plans)),
+	}
+	if node.Filter == "" {
+		node.Filter = plan.IndexCond
+	}
+	for _, child := range plan.Plans {
+		node.Children = append(node.Children, child.node())
+	}
+	return
+}
+
+// ParsePlan
+// [{"Plan": {"Node Type": "Seq Scan", "Relation Name": "...", "Plans": [...]}}]
+func (dialect *Dialect) ParsePlan(p []byte) (root specifications.PlanNode, err error) {
+	explains := make([]struct {
+		Plan explainPlan `json:"Plan"`
+	}, 0, 1)
+	decodeErr := json.Unmarshal(p, &explains)
+	if decodeErr != nil {
+		err = errors.Warning("sql: dialect parse plan failed").WithCause(decodeErr).WithMeta("dialect", Name)
+		return
+	}
+	if len(explains) == 0 {
+		err = errors.Warning("sql: dialect parse plan failed").WithCause(fmt.Errorf("plan is empty")).WithMeta("dialect", Name)
+		return
+	}
+	root = explains[0].Plan.node()
+	return
+}
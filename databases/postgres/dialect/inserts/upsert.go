@@ -0,0 +1,159 @@
+package inserts
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/valyala/bytebufferpool"
+	"io"
+)
+
+var (
+	CONSTRAINT = []byte("CONSTRAINT")
+)
+
+// RenderUpsert
+// INSERT INTO {table} ({columns}) VALUES ({values}) ON CONFLICT ({conflicts}) | ON CONFLICT ON CONSTRAINT {constraint}
+// DO UPDATE SET {column} = EXCLUDED.{column}, {column} = {table}.{column} + EXCLUDED.{column} [WHERE {cond}] [RETURNING {returning}]
+func RenderUpsert(ctx specifications.Context, spec *specifications.Specification, upsert specifications.Upsert, w io.Writer) (method specifications.Method, arguments []any, returning []string, err error) {
+	method = specifications.ExecuteMethod
+
+	query, vr, fields, insertReturning, generateErr := generateInsertQuery(ctx, spec)
+	if generateErr != nil {
+		err = errors.Warning("sql: render upsert failed").WithCause(generateErr).WithMeta("table", spec.Key)
+		return
+	}
+	returning = insertReturning
+
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+	_, _ = buf.WriteString(query)
+	_ = vr.Render(ctx, buf)
+	for _, field := range fields {
+		arguments = append(arguments, specifications.FieldArgument(field))
+	}
+
+	// name
+	tableName := ctx.FormatIdent(spec.Name)
+	if spec.Schema != "" {
+		schema := ctx.FormatIdent(spec.Schema)
+		tableName = fmt.Sprintf("%s.%s", schema, tableName)
+	}
+
+	// conflict
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.ON)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.CONFLICT)
+	_, _ = buf.Write(specifications.SPACE)
+	if upsert.Constraint != "" {
+		_, _ = buf.Write(specifications.ON)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(CONSTRAINT)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.WriteString(ctx.FormatIdent(upsert.Constraint))
+	} else {
+		conflicts, conflictsErr := specifications.UpsertConflicts(spec, upsert)
+		if conflictsErr != nil {
+			err = errors.Warning("sql: render upsert failed").WithCause(conflictsErr)
+			return
+		}
+		if len(conflicts) == 0 {
+			err = errors.Warning("sql: render upsert failed").WithCause(fmt.Errorf("conflicts or constraint is required")).WithMeta("table", spec.Key)
+			return
+		}
+		_, _ = buf.Write(specifications.LB)
+		for i, conflict := range conflicts {
+			if i > 0 {
+				_, _ = buf.Write(specifications.COMMA)
+			}
+			_, _ = buf.WriteString(ctx.FormatIdent(conflict.Name))
+		}
+		_, _ = buf.Write(specifications.RB)
+	}
+
+	// set
+	columns, columnsErr := specifications.UpsertColumns(spec, upsert)
+	if columnsErr != nil {
+		err = errors.Warning("sql: render upsert failed").WithCause(columnsErr)
+		return
+	}
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.DO)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.UPDATE)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.SET)
+	_, _ = buf.Write(specifications.SPACE)
+	for i, column := range columns {
+		if i > 0 {
+			_, _ = buf.Write(specifications.COMMA)
+		}
+		columnName := ctx.FormatIdent(column.Name)
+		_, _ = buf.WriteString(columnName)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.EQ)
+		_, _ = buf.Write(specifications.SPACE)
+		switch column.Kind {
+		case specifications.Aol:
+			_, _ = buf.WriteString(tableName)
+			_, _ = buf.Write(specifications.DOT)
+			_, _ = buf.WriteString(columnName)
+			_, _ = buf.Write(specifications.PLUS)
+			_, _ = buf.Write([]byte("1"))
+			break
+		case specifications.Amb, specifications.Amt:
+			_, _ = buf.WriteString(ctx.NextQueryPlaceholder())
+			arguments = append(arguments, specifications.FieldArgument(column.Field))
+			break
+		default:
+			if column.Mode == specifications.UpsertIncr {
+				_, _ = buf.WriteString(tableName)
+				_, _ = buf.Write(specifications.DOT)
+				_, _ = buf.WriteString(columnName)
+				_, _ = buf.Write(specifications.SPACE)
+				_, _ = buf.Write(specifications.PLUS)
+				_, _ = buf.Write(specifications.SPACE)
+			}
+			_, _ = buf.Write(specifications.EXCLUDED)
+			_, _ = buf.Write(specifications.DOT)
+			_, _ = buf.WriteString(columnName)
+			break
+		}
+	}
+
+	// where, columns are qualified by table name, cause they are ambiguous with EXCLUDED
+	cond, condArguments, condErr := specifications.RenderUpsertCond(specifications.Qualify(ctx, tableName), upsert)
+	if condErr != nil {
+		err = errors.Warning("sql: render upsert failed").WithCause(condErr).WithMeta("table", spec.Key)
+		return
+	}
+	if len(cond) > 0 {
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.WHERE)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(cond)
+		arguments = append(arguments, condArguments...)
+	}
+
+	// returning
+	if len(returning) > 0 {
+		method = specifications.QueryMethod
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.RETURNING)
+		_, _ = buf.Write(specifications.SPACE)
+		for i, r := range returning {
+			if i > 0 {
+				_, _ = buf.Write(specifications.COMMA)
+			}
+			column, has := spec.ColumnByField(r)
+			if has {
+				_, _ = buf.WriteString(ctx.FormatIdent(column.Name))
+			}
+		}
+	}
+
+	_, err = w.Write(bytex.FromString(buf.String()))
+	return
+}
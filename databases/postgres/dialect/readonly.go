@@ -0,0 +1,11 @@
+package dialect
+
+var (
+	setTransactionReadOnly = []byte("SET TRANSACTION READ ONLY")
+)
+
+// ReadOnly
+// SET TRANSACTION READ ONLY, it is executed before any query of transaction.
+func (dialect *Dialect) ReadOnly() []byte {
+	return setTransactionReadOnly
+}
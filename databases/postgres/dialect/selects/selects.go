@@ -77,11 +77,12 @@ func (generic *QueryGeneric) Render(ctx specifications.Context, w io.Writer, con
 
 	if len(orders) > 0 {
 		_, _ = w.Write(specifications.SPACE)
-		_, orderErr := orders.Render(ctx, w)
+		orderArguments, orderErr := orders.Render(ctx, w)
 		if orderErr != nil {
 			err = orderErr
 			return
 		}
+		arguments = append(arguments, orderArguments...)
 	}
 
 	if length > 0 {
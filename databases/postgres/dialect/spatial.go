@@ -0,0 +1,81 @@
+package dialect
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
+	"io"
+)
+
+var (
+	stDWithin     = []byte("ST_DWithin")
+	stIntersects  = []byte("ST_Intersects")
+	stContains    = []byte("ST_Contains")
+	geometryCast  = []byte("::geometry")
+	geographyCast = []byte("::geography")
+	knn           = []byte("<->")
+)
+
+// EncodeGeometry
+// postgis accepts hex ewkb as text input of geometry and geography, see sql.Geometry Value.
+func (dialect *Dialect) EncodeGeometry(geometry sql.Geometry) (v any, err error) {
+	v, err = geometry.Value()
+	return
+}
+
+// RenderSpatialPredicate
+// argument is cast as geography when column is geography, and ST_Contains is not supported by geography.
+func (dialect *Dialect) RenderSpatialPredicate(ctx specifications.Context, w io.Writer, column string, geography bool, operator conditions.Operator, spatial conditions.Spatial) (arguments []any, err error) {
+	if geography && operator == conditions.CONTAINS {
+		err = errors.Warning("sql: render spatial predicate failed").WithCause(fmt.Errorf("%s is not supported by geography", operator)).WithMeta("dialect", Name).WithMeta("column", column)
+		return
+	}
+	switch operator {
+	case conditions.DWITHIN:
+		_, _ = w.Write(stDWithin)
+		break
+	case conditions.INTERSECTS:
+		_, _ = w.Write(stIntersects)
+		break
+	case conditions.CONTAINS:
+		_, _ = w.Write(stContains)
+		break
+	default:
+		err = errors.Warning("sql: render spatial predicate failed").WithCause(fmt.Errorf("%s is not supported", operator)).WithMeta("dialect", Name)
+		return
+	}
+	_, _ = w.Write(specifications.LB)
+	_, _ = w.Write(bytex.FromString(column))
+	_, _ = w.Write(specifications.COMMA)
+	_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+	_, _ = w.Write(spatialCast(geography))
+	arguments = append(arguments, spatial.Geometry)
+	if operator == conditions.DWITHIN {
+		_, _ = w.Write(specifications.COMMA)
+		_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+		arguments = append(arguments, spatial.Distance)
+	}
+	_, _ = w.Write(specifications.RB)
+	return
+}
+
+func (dialect *Dialect) RenderNearestOrder(ctx specifications.Context, w io.Writer, column string, geography bool, geometry sql.Geometry) (arguments []any, err error) {
+	_, _ = w.Write(bytex.FromString(column))
+	_, _ = w.Write(specifications.SPACE)
+	_, _ = w.Write(knn)
+	_, _ = w.Write(specifications.SPACE)
+	_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+	_, _ = w.Write(spatialCast(geography))
+	arguments = append(arguments, geometry)
+	return
+}
+
+func spatialCast(geography bool) []byte {
+	if geography {
+		return geographyCast
+	}
+	return geometryCast
+}
@@ -0,0 +1,29 @@
+package dialect
+
+import (
+	"fmt"
+	"time"
+)
+
+// Hint
+// postgres has no hint of statement timeout, limit in transaction is set by Session before statement,
+// and default limit of each connection is set by ConnectionSession.
+func (dialect *Dialect) Hint(query []byte, _ time.Duration) []byte {
+	return query
+}
+
+// Session
+// SET LOCAL statement_timeout = n, it is valid until end of transaction.
+// SET LOCAL statement_timeout TO DEFAULT when timeout is zero.
+func (dialect *Dialect) Session(timeout time.Duration) []byte {
+	if timeout <= 0 {
+		return []byte("SET LOCAL statement_timeout TO DEFAULT")
+	}
+	return []byte(fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds()))
+}
+
+// ConnectionSession
+// SET statement_timeout = n, it is executed when connection is opened, so it is the default limit of the connection.
+func (dialect *Dialect) ConnectionSession(timeout time.Duration) []byte {
+	return []byte(fmt.Sprintf("SET statement_timeout = %d", timeout.Milliseconds()))
+}
@@ -73,34 +73,42 @@ func (generic *ViewGeneric) Render(ctx specifications.Context, w io.Writer, cond
 	method = specifications.QueryMethod
 	fields = generic.fields
 
+	arguments, err = specifications.RenderWith(ctx, w, generic.spec)
+	if err != nil {
+		return
+	}
+
 	_, _ = w.Write(generic.content)
 
 	if cond.Exist() {
 		_, _ = w.Write(specifications.SPACE)
 		_, _ = w.Write(specifications.WHERE)
 		_, _ = w.Write(specifications.SPACE)
-		arguments, err = cond.Render(ctx, w)
-		if err != nil {
+		condArguments, condErr := cond.Render(ctx, w)
+		if condErr != nil {
+			err = condErr
 			return
 		}
+		arguments = append(arguments, condArguments...)
 	}
 
-	if len(orders) > 0 {
+	if groupBy.Exist() {
 		_, _ = w.Write(specifications.SPACE)
-		_, orderErr := orders.Render(ctx, w)
-		if orderErr != nil {
-			err = orderErr
+		_, groupByErr := groupBy.Render(specifications.SwitchKey(ctx, generic.spec.Instance()), w)
+		if groupByErr != nil {
+			err = groupByErr
 			return
 		}
 	}
 
-	if groupBy.Exist() {
+	if len(orders) > 0 {
 		_, _ = w.Write(specifications.SPACE)
-		_, groupByErr := groupBy.Render(specifications.SwitchKey(ctx, generic.spec.Instance()), w)
-		if groupByErr != nil {
-			err = groupByErr
+		orderArguments, orderErr := orders.Render(ctx, w)
+		if orderErr != nil {
+			err = orderErr
 			return
 		}
+		arguments = append(arguments, orderArguments...)
 	}
 
 	if length > 0 {
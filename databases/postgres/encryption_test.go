@@ -0,0 +1,102 @@
+package postgres_test
+
+import (
+	"bytes"
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"strings"
+	"testing"
+)
+
+type Customer struct {
+	Id     string `column:"ID,pk"`
+	Mobile string `column:"MOBILE,enc,MOBILE_IDX"`
+}
+
+func (customer Customer) TableInfo() dac.TableInfo {
+	return dac.Info("CUSTOMER")
+}
+
+func registerTestKeyProvider() {
+	dac.RegisterKeyProvider(dac.StaticKeyProvider("k1", map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+	}, []byte("blind")))
+}
+
+func TestEncryption_Insert(t *testing.T) {
+	registerTestKeyProvider()
+	ctx := sqltest.Context(dialect.Name)
+	_, query, arguments, _, err := specifications.BuildInsert[Customer](ctx, []Customer{{Id: "1", Mobile: "13800000000"}})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `INSERT INTO "CUSTOMER" ("ID", "MOBILE", "MOBILE_IDX") VALUES ($1, $2, $3)`)
+	if len(arguments) != 3 {
+		t.Errorf("arguments must be 3, got %d", len(arguments))
+		return
+	}
+	mobile, decryptErr := specifications.Decrypt(arguments[1].(string))
+	if decryptErr != nil {
+		t.Errorf("%+v", decryptErr)
+		return
+	}
+	if mobile != "13800000000" {
+		t.Errorf("mobile must be encrypted, got %s", mobile)
+	}
+	index, _ := specifications.BlindIndex("13800000000")
+	sqltest.AssertArguments(t, arguments, "1", sqltest.AnyArg(), index)
+}
+
+func TestEncryption_ArgumentByField(t *testing.T) {
+	registerTestKeyProvider()
+	ctx := sqltest.Context(dialect.Name)
+	spec, err := specifications.GetSpecification(ctx, Customer{})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	argument, argumentErr := spec.ArgumentByField(Customer{Id: "1", Mobile: "13800000000"}, "Mobile")
+	if argumentErr != nil {
+		t.Errorf("%+v", argumentErr)
+		return
+	}
+	if argument != "13800000000" {
+		t.Errorf("argument by field must be plain value, got %v", argument)
+	}
+}
+
+func TestEncryption_Query(t *testing.T) {
+	registerTestKeyProvider()
+	ctx := sqltest.Context(dialect.Name)
+	index, indexErr := specifications.BlindIndex("13800000000")
+	if indexErr != nil {
+		t.Errorf("%+v", indexErr)
+		return
+	}
+	encrypted, encryptErr := specifications.Encrypt("13800000000")
+	if encryptErr != nil {
+		t.Errorf("%+v", encryptErr)
+		return
+	}
+	db := sqltest.New()
+	db.ExpectQuery(`WHERE "MOBILE_IDX" = \$1`).WithArgs(index).
+		WillReturnRows(sqltest.NewRows("ID", "MOBILE", "MOBILE_IDX").AddRow("1", encrypted, index))
+	if err := sqltest.Use(ctx, db); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	entries, err := dac.ALL[Customer](ctx, dac.Conditions(dac.Eq("Mobile", "13800000000")), dac.NoCache())
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if len(entries) != 1 || entries[0].Mobile != "13800000000" {
+		t.Errorf("mobile must be decrypted, got %+v", entries)
+	}
+	if strings.Contains(db.Statements()[0].Query, "13800000000") {
+		t.Errorf("plain value must not be in query")
+	}
+}
@@ -82,7 +82,7 @@ func (writer *TransactionWriter) HandleBefore(ctx context.Context, params []stri
 
 	stmt.Tab().Token("if err = postgres.Begin(ctx")
 	if readonly {
-		stmt.Token(", postgres.Readonly()")
+		stmt.Token(", postgres.ReadOnly()")
 	}
 	if isolation != sql.LevelDefault {
 		stmt.Token(", postgres.WithIsolation(")
@@ -3,6 +3,7 @@ module github.com/aacfactory/fns-contrib/databases/postgres
 go 1.22.1
 
 require (
+	github.com/aacfactory/avro v1.2.12
 	github.com/aacfactory/errors v1.13.12
 	github.com/aacfactory/fns v1.3.0
 	github.com/aacfactory/fns-contrib/databases/sql v1.3.0
@@ -14,7 +15,6 @@ require (
 
 require (
 	github.com/aacfactory/afssl v1.12.0 // indirect
-	github.com/aacfactory/avro v1.2.12 // indirect
 	github.com/aacfactory/cases v1.1.0 // indirect
 	github.com/aacfactory/configures v1.13.0 // indirect
 	github.com/aacfactory/copier v1.4.0 // indirect
@@ -0,0 +1,80 @@
+package postgres_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type Account struct {
+	Id      string `column:"ID,pk"`
+	Balance int64  `column:"BALANCE"`
+	Version int64  `column:"VERSION,aol"`
+}
+
+func (account Account) TableInfo() dac.TableInfo {
+	return dac.Info("ACCOUNT", dac.Schema("FNS"), dac.KeepHistory())
+}
+
+func TestHistory_Snapshot(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	query, arguments, has, err := specifications.BuildHistorySnapshotByEntry[Account](ctx, specifications.HistoryUpdateOp, Account{Id: "1", Version: 2})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if !has {
+		t.Errorf("account keeps history")
+		return
+	}
+	sqltest.AssertQuery(t, query, `INSERT INTO "FNS"."ACCOUNT_HISTORY" ("ID", "BALANCE", "VERSION", "HISTORY_OP", "HISTORY_BY", "HISTORY_AT") SELECT "ID", "BALANCE", "VERSION", $1, NULL, CURRENT_TIMESTAMP FROM "FNS"."ACCOUNT" WHERE "ID" = $2 AND "VERSION" = $3`)
+	sqltest.AssertArguments(t, arguments, specifications.HistoryUpdateOp, "1", int64(2))
+}
+
+func TestHistory_Table(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	queries, err := specifications.BuildHistoryTable[Account](ctx)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	expected := []string{
+		`CREATE TABLE "FNS"."ACCOUNT_HISTORY" AS SELECT "ID", "BALANCE", "VERSION" FROM "FNS"."ACCOUNT" WHERE 1 = 0`,
+		`ALTER TABLE "FNS"."ACCOUNT_HISTORY" ADD COLUMN "HISTORY_OP" VARCHAR(8)`,
+		`ALTER TABLE "FNS"."ACCOUNT_HISTORY" ADD COLUMN "HISTORY_BY" VARCHAR(63)`,
+		`ALTER TABLE "FNS"."ACCOUNT_HISTORY" ADD COLUMN "HISTORY_AT" TIMESTAMP`,
+	}
+	if len(queries) != len(expected) {
+		t.Errorf("queries must be %d, got %d", len(expected), len(queries))
+		return
+	}
+	for i, query := range queries {
+		sqltest.AssertQuery(t, query, expected[i])
+	}
+}
+
+func TestHistory_Update(t *testing.T) {
+	db := sqltest.New()
+	// snapshot is written in transaction of ctx without nested begin
+	db.ExpectExecute(`INSERT INTO "FNS"."ACCOUNT_HISTORY"`).WithArgs(specifications.HistoryUpdateOp, "1", int64(1)).WillReturnResult(0, 1)
+	db.ExpectExecute(`UPDATE "FNS"."ACCOUNT"`).WillReturnResult(0, 1)
+	ctx := sqltest.Context(dialect.Name)
+	if err := sqltest.Use(ctx, db); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	_, ok, err := dac.Update[Account](ctx, Account{Id: "1", Balance: 10, Version: 1})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if !ok {
+		t.Errorf("update must be succeeded")
+		return
+	}
+	if err = db.ExpectationsWereMet(); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
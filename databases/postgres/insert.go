@@ -20,9 +20,35 @@ func InsertMulti[T Table](ctx context.Context, entries []T) (affected int64, err
 	return
 }
 
-func InsertOrUpdate[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
+type UpsertOption dac.UpsertOption
+
+func OnConflict(fields ...string) UpsertOption {
+	return UpsertOption(dac.OnConflict(fields...))
+}
+
+func OnConstraint(name string) UpsertOption {
+	return UpsertOption(dac.OnConstraint(name))
+}
+
+func DoUpdate(fields ...string) UpsertOption {
+	return UpsertOption(dac.DoUpdate(fields...))
+}
+
+func DoIncr(fields ...string) UpsertOption {
+	return UpsertOption(dac.DoIncr(fields...))
+}
+
+func DoUpdateWhen(cond conditions.Condition) UpsertOption {
+	return UpsertOption(dac.DoUpdateWhen(cond))
+}
+
+func InsertOrUpdate[T Table](ctx context.Context, entry T, options ...UpsertOption) (v T, ok bool, err error) {
 	sql.ForceDialect(ctx, dialect.Name)
-	v, ok, err = dac.InsertOrUpdate[T](ctx, entry)
+	opts := make([]dac.UpsertOption, 0, len(options))
+	for _, option := range options {
+		opts = append(opts, dac.UpsertOption(option))
+	}
+	v, ok, err = dac.InsertOrUpdate[T](ctx, entry, opts...)
 	return
 }
 
@@ -0,0 +1,112 @@
+package postgres_test
+
+import (
+	"github.com/aacfactory/avro"
+	"github.com/aacfactory/fns-contrib/databases/postgres"
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"github.com/aacfactory/json"
+	"strings"
+	"testing"
+)
+
+type Contact struct {
+	Id       string `column:"ID,pk"`
+	Mobile   string `column:"MOBILE,mask:partial"`
+	Password string `column:"PASSWORD,mask:hidden"`
+}
+
+func (contact Contact) TableInfo() dac.TableInfo {
+	return dac.Info("CONTACT")
+}
+
+func TestPage_Masked(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	db := sqltest.New()
+	if err := sqltest.Use(ctx, db); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	db.ExpectQuery(`SELECT COUNT\(1\)`).WillReturnRows(sqltest.NewRows("_COUNT_").AddRow(int64(1)))
+	db.ExpectQuery(`SELECT "ID", "MOBILE", "PASSWORD" FROM "CONTACT"`).
+		WillReturnRows(sqltest.NewRows("ID", "MOBILE", "PASSWORD").AddRow("1", "13812345678", "secret"))
+	page, err := postgres.Page[Contact](ctx, 1, 10, postgres.NoCache())
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	masked, maskErr := page.Masked(ctx)
+	if maskErr != nil {
+		t.Errorf("%+v", maskErr)
+		return
+	}
+	p, encodeErr := json.Marshal(masked)
+	if encodeErr != nil {
+		t.Errorf("%+v", encodeErr)
+		return
+	}
+	if !strings.Contains(string(p), `"Mobile":"13*******78"`) || strings.Contains(string(p), "secret") {
+		t.Errorf("encoded entries must be masked, %s", p)
+	}
+	// entries are masked only when encoded
+	if len(page.Entries) != 1 || page.Entries[0].Mobile != "13812345678" || page.Entries[0].Password != "secret" {
+		t.Errorf("entries must not be changed, %+v", page.Entries)
+	}
+	if entries := masked.Entries.Entries(); entries[0].Mobile != "13812345678" || entries[0].Password != "secret" {
+		t.Errorf("entries of masked pager must not be changed, %+v", entries)
+	}
+	decoded := dac.MaskedPager[Contact]{}
+	if err = json.Unmarshal(p, &decoded); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if entries := decoded.Entries.Entries(); len(entries) != 1 || entries[0].Mobile != "13*******78" || entries[0].Password != "" {
+		t.Errorf("decoded entries must be masked, %+v", entries)
+	}
+	if err = db.ExpectationsWereMet(); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
+
+func TestMaskEntries_Avro(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	entries := []Contact{{Id: "1", Mobile: "13812345678", Password: "secret"}}
+	masked, err := dac.MaskEntries[Contact](ctx, entries)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	p, encodeErr := avro.Marshal(masked)
+	if encodeErr != nil {
+		t.Errorf("%+v", encodeErr)
+		return
+	}
+	decoded := dac.MaskedEntries[Contact]{}
+	if err = avro.Unmarshal(p, &decoded); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if values := decoded.Entries(); len(values) != 1 || values[0].Mobile != "13*******78" || values[0].Password != "" {
+		t.Errorf("decoded entries must be masked, %+v", values)
+	}
+	if entries[0].Mobile != "13812345678" || entries[0].Password != "secret" {
+		t.Errorf("entries must not be changed, %+v", entries[0])
+	}
+}
+
+func TestMask_Copy(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	entries := []Contact{{Id: "1", Mobile: "13812345678", Password: "secret"}}
+	masked, err := dac.Mask[Contact](ctx, entries)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if masked[0].Mobile != "13*******78" || masked[0].Password != "" {
+		t.Errorf("copy must be masked, %+v", masked[0])
+	}
+	if entries[0].Mobile != "13812345678" || entries[0].Password != "secret" {
+		t.Errorf("entries in param must not be changed, %+v", entries[0])
+	}
+}
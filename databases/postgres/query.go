@@ -37,6 +37,26 @@ func GroupBy(by groups.GroupBy) QueryOption {
 	return QueryOption(dac.GroupBy(by))
 }
 
+func NoCache() QueryOption {
+	return QueryOption(dac.NoCache())
+}
+
+func ForUpdate() QueryOption {
+	return QueryOption(dac.ForUpdate())
+}
+
+func ForShare() QueryOption {
+	return QueryOption(dac.ForShare())
+}
+
+func SkipLocked() QueryOption {
+	return QueryOption(dac.SkipLocked())
+}
+
+func NoWait() QueryOption {
+	return QueryOption(dac.NoWait())
+}
+
 var (
 	queryOptionsPool = sync.Pool{New: func() any {
 		return make([]dac.QueryOption, 0, 3)
@@ -83,3 +103,14 @@ func ALL[T Table](ctx context.Context, options ...QueryOption) (entries []T, err
 	releaseQueryOptions(opts)
 	return
 }
+
+func ClaimBatch[T Table](ctx context.Context, size int, options ...QueryOption) (entries []T, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	opts := acquireQueryOptions()
+	for _, option := range options {
+		opts = append(opts, dac.QueryOption(option))
+	}
+	entries, err = dac.ClaimBatch[T](ctx, size, opts...)
+	releaseQueryOptions(opts)
+	return
+}
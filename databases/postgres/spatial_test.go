@@ -0,0 +1,56 @@
+package postgres_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type Shop struct {
+	Id       string       `column:"ID,pk"`
+	Location sql.Geometry `column:"LOCATION,geography"`
+	Area     sql.Geometry `column:"AREA,geometry"`
+}
+
+func (shop Shop) TableInfo() dac.TableInfo {
+	return dac.Info("SHOP")
+}
+
+func TestSpatial_Geography(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	point := sql.NewPoint(120.15, 30.28)
+	cond := specifications.Condition{Condition: dac.DistanceWithin("Location", point, 1000)}
+	orders := specifications.Orders(dac.Nearest("Location", point))
+	_, query, arguments, _, err := specifications.BuildQuery[Shop](ctx, cond, orders, 0, 10)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `SELECT "ID", "LOCATION", "AREA" FROM "SHOP" WHERE ST_DWithin("LOCATION", $1::geography, $2) ORDER BY "LOCATION" <-> $3::geography OFFSET $4 LIMIT $5`)
+	sqltest.AssertArguments(t, arguments, sqltest.AnyArg(), float64(1000), sqltest.AnyArg(), 0, 10)
+}
+
+func TestSpatial_Geometry(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	point := sql.NewPoint(120.15, 30.28)
+	cond := specifications.Condition{Condition: dac.Contains("Area", point)}
+	_, query, _, _, err := specifications.BuildQuery[Shop](ctx, cond, nil, 0, 0)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `SELECT "ID", "LOCATION", "AREA" FROM "SHOP" WHERE ST_Contains("AREA", $1::geometry)`)
+}
+
+func TestSpatial_GeographyContains(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := specifications.Condition{Condition: dac.Contains("Location", sql.NewPoint(120.15, 30.28))}
+	_, _, _, _, err := specifications.BuildQuery[Shop](ctx, cond, nil, 0, 0)
+	if err == nil {
+		t.Errorf("contains on geography must be failed")
+		return
+	}
+}
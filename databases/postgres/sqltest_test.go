@@ -0,0 +1,58 @@
+package postgres_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type Member struct {
+	Id   string `column:"ID,pk"`
+	Name string `column:"NAME"`
+}
+
+func (member Member) TableInfo() dac.TableInfo {
+	return dac.Info("MEMBER", dac.Schema("FNS"))
+}
+
+func TestSqltest_Build(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := specifications.Condition{Condition: dac.Eq("Name", "foo")}
+	_, query, arguments, _, err := specifications.BuildQuery[Member](ctx, cond, specifications.Orders(dac.Asc("Name")), 0, 10)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `SELECT "ID", "NAME" FROM "FNS"."MEMBER" WHERE "NAME" = $1 ORDER BY "NAME" OFFSET $2 LIMIT $3`)
+	sqltest.AssertArguments(t, arguments, "foo", 0, 10)
+}
+
+func TestSqltest_Use(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := specifications.Condition{Condition: dac.Eq("Name", "foo")}
+	_, query, _, _, err := specifications.BuildQuery[Member](ctx, cond, nil, 0, 0)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	db := sqltest.New(sqltest.ExactQuery())
+	db.ExpectQuery(string(query)).WithArgs("foo").
+		WillReturnRows(sqltest.NewRows("ID", "NAME").AddRow("1", "foo").AddRow("2", "foo"))
+	if err = sqltest.Use(ctx, db); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	entries, queryErr := dac.ALL[Member](ctx, dac.Conditions(dac.Eq("Name", "foo")), dac.NoCache())
+	if queryErr != nil {
+		t.Errorf("%+v", queryErr)
+		return
+	}
+	if len(entries) != 2 || entries[1].Id != "2" {
+		t.Errorf("entries are not matched, %+v", entries)
+	}
+	if err = db.ExpectationsWereMet(); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
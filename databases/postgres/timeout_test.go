@@ -0,0 +1,51 @@
+package postgres_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"github.com/aacfactory/fns/context"
+	"testing"
+	"time"
+)
+
+func TestStatementTimeout(t *testing.T) {
+	db := sqltest.New()
+	db.ExpectExecute(`^SET LOCAL statement_timeout = 2000$`).WillReturnResult(0, 0)
+	db.ExpectQuery(`SELECT .+ FROM "FNS"."MEMBER"`).WillReturnRows(sqltest.NewRows("ID", "NAME").AddRow("1", "foo"))
+	// timeout of call is not current, so it is reset before next statement
+	db.ExpectExecute(`^SET LOCAL statement_timeout TO DEFAULT$`).WillReturnResult(0, 0)
+	db.ExpectQuery(`SELECT .+ FROM "FNS"."MEMBER"`).WillReturnRows(sqltest.NewRows("ID", "NAME").AddRow("1", "foo"))
+	db.ExpectQuery(`SELECT .+ FROM "FNS"."MEMBER"`).WillReturnRows(sqltest.NewRows("ID", "NAME").AddRow("1", "foo"))
+
+	ctx := sqltest.Context(dialect.Name)
+	if err := sqltest.Use(ctx, db); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	err := sql.WithTimeout(ctx, 2*time.Second, func(ctx context.Context) (err error) {
+		_, err = dac.ALL[Member](ctx, dac.NoCache())
+		return
+	})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := dac.ALL[Member](ctx, dac.NoCache()); err != nil {
+			t.Errorf("%+v", err)
+			return
+		}
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
+
+func TestConnectionStatementTimeout(t *testing.T) {
+	session := dialect.NewDialect().ConnectionSession(2 * time.Second)
+	if string(session) != "SET statement_timeout = 2000" {
+		t.Errorf("session is not matched, got %s", session)
+	}
+}
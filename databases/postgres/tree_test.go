@@ -0,0 +1,49 @@
+package postgres_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type Org struct {
+	Id       string `column:"ID,pk" tree:"ParentId+Children"`
+	ParentId string `column:"PARENT_ID"`
+	Name     string `column:"NAME"`
+	Children []Org  `json:"children"`
+}
+
+func (org Org) TableInfo() dac.TableInfo {
+	return dac.Info("ORG")
+}
+
+func TestTree_Subtree(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	_, query, arguments, _, err := specifications.BuildSubtree[Org](ctx, "root", 2, specifications.Condition{}, specifications.Orders(dac.Asc("Name")))
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `WITH RECURSIVE "__TREE" ("ID", "__DEPTH") AS (SELECT "ID", 0 FROM "ORG" WHERE "ID" = $1 UNION SELECT "__NODE"."ID", "__TREE"."__DEPTH" + 1 FROM "ORG" AS "__NODE" INNER JOIN "__TREE" ON "__NODE"."PARENT_ID" = "__TREE"."ID" WHERE "__TREE"."__DEPTH" < 2) SELECT "ID", "PARENT_ID", "NAME" FROM "ORG" WHERE "ID" IN (SELECT "ID" FROM "__TREE") ORDER BY "NAME"`)
+	sqltest.AssertArguments(t, arguments, "root")
+}
+
+func TestTree_Ancestors(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	_, query, arguments, _, err := specifications.BuildAncestors[Org](ctx, "node", specifications.Condition{})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `WITH RECURSIVE "__TREE" ("ID", "__PARENT") AS (SELECT "ID", "PARENT_ID" FROM "ORG" WHERE "ID" = $1 UNION SELECT "__NODE"."ID", "__NODE"."PARENT_ID" FROM "ORG" AS "__NODE" INNER JOIN "__TREE" ON "__NODE"."ID" = "__TREE"."__PARENT") SELECT "ID", "PARENT_ID", "NAME" FROM "ORG" WHERE "ID" IN (SELECT "ID" FROM "__TREE")`)
+	sqltest.AssertArguments(t, arguments, "node")
+}
+
+func TestTree_AncestorsOrders(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	if _, err := dac.Ancestors[Org](ctx, "node", dac.Orders(dac.Asc("Name"))); err == nil {
+		t.Errorf("orders must be rejected")
+	}
+}
@@ -0,0 +1,50 @@
+package postgres_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"net/http"
+	"testing"
+)
+
+type Wallet struct {
+	Id      string `column:"ID,pk"`
+	Balance int64  `column:"BALANCE"`
+	Version int64  `column:"VERSION,aol"`
+}
+
+func (wallet Wallet) TableInfo() dac.TableInfo {
+	return dac.Info("WALLET")
+}
+
+func TestUpdate_Conflict(t *testing.T) {
+	db := sqltest.New()
+	db.ExpectExecute(`UPDATE "WALLET"`).WithArgs(int64(10), "1", int64(1)).WillReturnResult(0, 0)
+	db.ExpectQuery(`SELECT "ID", "BALANCE", "VERSION" FROM "WALLET"`).WithArgs("1", sqltest.AnyArg(), sqltest.AnyArg()).
+		WillReturnRows(sqltest.NewRows("ID", "BALANCE", "VERSION").AddRow("1", int64(5), int64(3)))
+	ctx := sqltest.Context(dialect.Name)
+	if err := sqltest.Use(ctx, db); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	_, ok, err := dac.Update[Wallet](ctx, Wallet{Id: "1", Balance: 10, Version: 1})
+	if ok || err == nil {
+		t.Errorf("update must be conflicted")
+		return
+	}
+	conflict, is := dac.IsConflict(err)
+	if !is {
+		t.Errorf("error must be conflict error, %+v", err)
+		return
+	}
+	if conflict.Code() != http.StatusConflict {
+		t.Errorf("code of conflict error must be 409, got %d", conflict.Code())
+	}
+	if conflict.Expected != 1 || conflict.Current != 3 {
+		t.Errorf("versions are not matched, expected %d, current %d", conflict.Expected, conflict.Current)
+	}
+	if err = db.ExpectationsWereMet(); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package postgres_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/postgres/dialect"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/groups"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type Order struct {
+	Id     string  `column:"ID,pk"`
+	UserId string  `column:"USER_ID"`
+	Kind   string  `column:"KIND"`
+	Amount float64 `column:"AMOUNT"`
+}
+
+func (o Order) TableInfo() dac.TableInfo {
+	return dac.Info("ORDERS")
+}
+
+type RankedOrder struct {
+	Id      string  `column:"ID"`
+	UserId  string  `column:"USER_ID"`
+	Kind    string  `column:"KIND"`
+	Amount  float64 `column:"AMOUNT"`
+	Rank    int64   `column:"RN,vc,window,ROW_NUMBER(),partition:USER_ID,orders:AMOUNT@desc"`
+	Running float64 `column:"RUNNING,vc,window,SUM(\"AMOUNT\"),partition:USER_ID,orders:ID,frame:ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW"`
+}
+
+func (o RankedOrder) ViewInfo() dac.ViewInfo {
+	return dac.TableView(Order{})
+}
+
+type TopOrder struct {
+	UserId string  `column:"USER_ID"`
+	Amount float64 `column:"AMOUNT"`
+	Rank   int64   `column:"RN"`
+}
+
+func (o TopOrder) ViewInfo() dac.ViewInfo {
+	return dac.CTEView("ranked", dac.With("ranked", RankedOrder{}, dac.Conditions(dac.Eq("Kind", "online"))))
+}
+
+type UserAmount struct {
+	UserId string  `column:"USER_ID"`
+	Amount float64 `column:"AMOUNT,vc,agg,SUM"`
+}
+
+func (o UserAmount) ViewInfo() dac.ViewInfo {
+	return dac.TableView(Order{})
+}
+
+func TestView_Window(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	_, query, arguments, _, err := specifications.BuildView[RankedOrder](ctx, specifications.Condition{}, nil, specifications.GroupBy{}, 0, 0)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `SELECT "ID", "USER_ID", "KIND", "AMOUNT", ROW_NUMBER() OVER (PARTITION BY "USER_ID" ORDER BY "AMOUNT" DESC) AS "RN", SUM("AMOUNT") OVER (PARTITION BY "USER_ID" ORDER BY "ID" ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS "RUNNING" FROM "ORDERS"`)
+	sqltest.AssertArguments(t, arguments)
+}
+
+func TestView_CTE(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := specifications.Condition{Condition: dac.Lte("Rank", 3)}
+	_, query, arguments, _, err := specifications.BuildView[TopOrder](ctx, cond, specifications.Orders(dac.Desc("Amount")), specifications.GroupBy{}, 0, 10)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `WITH "ranked" AS (SELECT "ID", "USER_ID", "KIND", "AMOUNT", ROW_NUMBER() OVER (PARTITION BY "USER_ID" ORDER BY "AMOUNT" DESC) AS "RN", SUM("AMOUNT") OVER (PARTITION BY "USER_ID" ORDER BY "ID" ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS "RUNNING" FROM "ORDERS" WHERE "KIND" = $1) SELECT "USER_ID", "AMOUNT", "RN" FROM "ranked" WHERE "RN" <= $2 ORDER BY "AMOUNT" DESC OFFSET $3 LIMIT $4`)
+	sqltest.AssertArguments(t, arguments, "online", 3, 0, 10)
+}
+
+func TestView_GroupBy(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	_, query, _, _, err := specifications.BuildView[UserAmount](ctx, specifications.Condition{}, specifications.Orders(dac.Desc("Amount")), specifications.GroupBy{GroupBy: groups.Group("UserId")}, 0, 0)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `SELECT "USER_ID", SUM("AMOUNT") AS "AMOUNT_SUM" FROM "ORDERS" GROUP BY "USER_ID" ORDER BY "AMOUNT" DESC`)
+}
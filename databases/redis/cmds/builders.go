@@ -23,4 +23,5 @@ func init() {
 	registerList()
 	registerSet()
 	registerSortedSet()
+	registerStream()
 }
@@ -0,0 +1,382 @@
+package cmds
+
+import (
+	"github.com/redis/rueidis"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	XACK       = "XACK"
+	XADD       = "XADD"
+	XAUTOCLAIM = "XAUTOCLAIM"
+	XCLAIM     = "XCLAIM"
+	XPENDING   = "XPENDING"
+	XREADGROUP = "XREADGROUP"
+	XTRIM      = "XTRIM"
+)
+
+func registerStream() {
+	builders[XACK] = &XACKBuilder{}
+	builders[XADD] = &XADDBuilder{}
+	builders[XAUTOCLAIM] = &XAUTOCLAIMBuilder{}
+	builders[XCLAIM] = &XCLAIMBuilder{}
+	builders[XPENDING] = &XPENDINGBuilder{}
+	builders[XREADGROUP] = &XREADGROUPBuilder{}
+	builders[XTRIM] = &XTRIMBuilder{}
+}
+
+type XACKBuilder struct {
+}
+
+func (b *XACKBuilder) Completed(client rueidis.Client, params []string) (v rueidis.Completed, ok bool) {
+	if len(params) < 3 {
+		return
+	}
+	v = client.B().Xack().Key(params[0]).Group(params[1]).Id(params[2:]...).Build()
+	ok = true
+	return
+}
+
+func (b *XACKBuilder) Cacheable(client rueidis.Client, params []string) (v rueidis.Cacheable, ok bool) {
+	return
+}
+
+type XADDBuilder struct {
+}
+
+func (b *XADDBuilder) Completed(client rueidis.Client, params []string) (v rueidis.Completed, ok bool) {
+
+	rv := reflect.ValueOf(client.B().Xadd().Key(params[0]))
+
+	params = params[1:]
+
+	identified := false
+	for i, param := range params {
+		if param == "NOMKSTREAM" {
+			rv = rv.MethodByName("Nomkstream").Call([]reflect.Value{})[0]
+			continue
+		}
+		if param == "MAXLEN" {
+			rv = rv.MethodByName("Maxlen").Call([]reflect.Value{})[0]
+			continue
+		}
+		if param == "MINID" {
+			rv = rv.MethodByName("Minid").Call([]reflect.Value{})[0]
+			continue
+		}
+		if param == "EXACT" {
+			rv = rv.MethodByName("Exact").Call([]reflect.Value{})[0]
+			continue
+		}
+		if param == "ALMOST" {
+			rv = rv.MethodByName("Almost").Call([]reflect.Value{})[0]
+			continue
+		}
+		if threshold, has := strings.CutPrefix(param, "THRESHOLD:"); has {
+			rv = rv.MethodByName("Threshold").Call([]reflect.Value{reflect.ValueOf(threshold)})[0]
+			continue
+		}
+		if limit, has := strings.CutPrefix(param, "LIMIT:"); has {
+			vv, vvErr := strconv.ParseInt(limit, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Limit").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if id, has := strings.CutPrefix(param, "ID:"); has {
+			rv = rv.MethodByName("Id").Call([]reflect.Value{reflect.ValueOf(id)})[0]
+			params = params[i+1:]
+			identified = true
+			break
+		}
+	}
+	if !identified || len(params) == 0 || len(params)%2 != 0 {
+		return
+	}
+
+	rv = rv.MethodByName("FieldValue").Call([]reflect.Value{})[0]
+	for i := 0; i < len(params); i += 2 {
+		rv = rv.MethodByName("FieldValue").Call([]reflect.Value{reflect.ValueOf(params[i]), reflect.ValueOf(params[i+1])})[0]
+	}
+
+	rv = rv.MethodByName("Build").Call([]reflect.Value{})[0]
+	v = rv.Interface().(rueidis.Completed)
+
+	ok = true
+	return
+}
+
+func (b *XADDBuilder) Cacheable(client rueidis.Client, params []string) (v rueidis.Cacheable, ok bool) {
+	return
+}
+
+type XAUTOCLAIMBuilder struct {
+}
+
+func (b *XAUTOCLAIMBuilder) Completed(client rueidis.Client, params []string) (v rueidis.Completed, ok bool) {
+	if len(params) < 5 {
+		return
+	}
+
+	rv := reflect.ValueOf(client.B().Xautoclaim().Key(params[0]).Group(params[1]).Consumer(params[2]).MinIdleTime(params[3]).Start(params[4]))
+
+	params = params[5:]
+
+	for _, param := range params {
+		if count, has := strings.CutPrefix(param, "COUNT:"); has {
+			vv, vvErr := strconv.ParseInt(count, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Count").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if param == "JUSTID" {
+			rv = rv.MethodByName("Justid").Call([]reflect.Value{})[0]
+			continue
+		}
+	}
+
+	rv = rv.MethodByName("Build").Call([]reflect.Value{})[0]
+	v = rv.Interface().(rueidis.Completed)
+
+	ok = true
+	return
+}
+
+func (b *XAUTOCLAIMBuilder) Cacheable(client rueidis.Client, params []string) (v rueidis.Cacheable, ok bool) {
+	return
+}
+
+type XCLAIMBuilder struct {
+}
+
+func (b *XCLAIMBuilder) Completed(client rueidis.Client, params []string) (v rueidis.Completed, ok bool) {
+	if len(params) < 5 {
+		return
+	}
+
+	rv := reflect.ValueOf(client.B().Xclaim().Key(params[0]).Group(params[1]).Consumer(params[2]).MinIdleTime(params[3]))
+
+	params = params[4:]
+
+	for _, param := range params {
+		if id, has := strings.CutPrefix(param, "ID:"); has {
+			rv = rv.MethodByName("Id").Call([]reflect.Value{reflect.ValueOf(id)})[0]
+			continue
+		}
+		if idle, has := strings.CutPrefix(param, "IDLE:"); has {
+			vv, vvErr := strconv.ParseInt(idle, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Idle").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if t, has := strings.CutPrefix(param, "TIME:"); has {
+			vv, vvErr := strconv.ParseInt(t, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Time").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if count, has := strings.CutPrefix(param, "RETRYCOUNT:"); has {
+			vv, vvErr := strconv.ParseInt(count, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Retrycount").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if param == "FORCE" {
+			rv = rv.MethodByName("Force").Call([]reflect.Value{})[0]
+			continue
+		}
+		if param == "JUSTID" {
+			rv = rv.MethodByName("Justid").Call([]reflect.Value{})[0]
+			continue
+		}
+	}
+
+	rv = rv.MethodByName("Build").Call([]reflect.Value{})[0]
+	v = rv.Interface().(rueidis.Completed)
+
+	ok = true
+	return
+}
+
+func (b *XCLAIMBuilder) Cacheable(client rueidis.Client, params []string) (v rueidis.Cacheable, ok bool) {
+	return
+}
+
+type XPENDINGBuilder struct {
+}
+
+func (b *XPENDINGBuilder) Completed(client rueidis.Client, params []string) (v rueidis.Completed, ok bool) {
+	if len(params) < 2 {
+		return
+	}
+
+	rv := reflect.ValueOf(client.B().Xpending().Key(params[0]).Group(params[1]))
+
+	params = params[2:]
+
+	for _, param := range params {
+		if idle, has := strings.CutPrefix(param, "IDLE:"); has {
+			vv, vvErr := strconv.ParseInt(idle, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Idle").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if start, has := strings.CutPrefix(param, "START:"); has {
+			rv = rv.MethodByName("Start").Call([]reflect.Value{reflect.ValueOf(start)})[0]
+			continue
+		}
+		if end, has := strings.CutPrefix(param, "END:"); has {
+			rv = rv.MethodByName("End").Call([]reflect.Value{reflect.ValueOf(end)})[0]
+			continue
+		}
+		if count, has := strings.CutPrefix(param, "COUNT:"); has {
+			vv, vvErr := strconv.ParseInt(count, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Count").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if consumer, has := strings.CutPrefix(param, "CONSUMER:"); has {
+			rv = rv.MethodByName("Consumer").Call([]reflect.Value{reflect.ValueOf(consumer)})[0]
+			continue
+		}
+	}
+
+	rv = rv.MethodByName("Build").Call([]reflect.Value{})[0]
+	v = rv.Interface().(rueidis.Completed)
+
+	ok = true
+	return
+}
+
+func (b *XPENDINGBuilder) Cacheable(client rueidis.Client, params []string) (v rueidis.Cacheable, ok bool) {
+	return
+}
+
+type XREADGROUPBuilder struct {
+}
+
+func (b *XREADGROUPBuilder) Completed(client rueidis.Client, params []string) (v rueidis.Completed, ok bool) {
+	if len(params) < 2 {
+		return
+	}
+
+	rv := reflect.ValueOf(client.B().Xreadgroup().Group(params[0], params[1]))
+
+	params = params[2:]
+
+	keys := make([]reflect.Value, 0, 1)
+	ids := make([]reflect.Value, 0, 1)
+	for _, param := range params {
+		if count, has := strings.CutPrefix(param, "COUNT:"); has {
+			vv, vvErr := strconv.ParseInt(count, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Count").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if block, has := strings.CutPrefix(param, "BLOCK:"); has {
+			vv, vvErr := strconv.ParseInt(block, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Block").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+		if param == "NOACK" {
+			rv = rv.MethodByName("Noack").Call([]reflect.Value{})[0]
+			continue
+		}
+		if key, has := strings.CutPrefix(param, "KEY:"); has {
+			keys = append(keys, reflect.ValueOf(key))
+			continue
+		}
+		if id, has := strings.CutPrefix(param, "ID:"); has {
+			ids = append(ids, reflect.ValueOf(id))
+			continue
+		}
+	}
+	if len(keys) == 0 || len(keys) != len(ids) {
+		return
+	}
+
+	rv = rv.MethodByName("Streams").Call([]reflect.Value{})[0]
+	rv = rv.MethodByName("Key").Call(keys)[0]
+	rv = rv.MethodByName("Id").Call(ids)[0]
+
+	rv = rv.MethodByName("Build").Call([]reflect.Value{})[0]
+	v = rv.Interface().(rueidis.Completed)
+
+	ok = true
+	return
+}
+
+func (b *XREADGROUPBuilder) Cacheable(client rueidis.Client, params []string) (v rueidis.Cacheable, ok bool) {
+	return
+}
+
+type XTRIMBuilder struct {
+}
+
+func (b *XTRIMBuilder) Completed(client rueidis.Client, params []string) (v rueidis.Completed, ok bool) {
+
+	rv := reflect.ValueOf(client.B().Xtrim().Key(params[0]))
+
+	params = params[1:]
+
+	for _, param := range params {
+		if param == "MAXLEN" {
+			rv = rv.MethodByName("Maxlen").Call([]reflect.Value{})[0]
+			continue
+		}
+		if param == "MINID" {
+			rv = rv.MethodByName("Minid").Call([]reflect.Value{})[0]
+			continue
+		}
+		if param == "EXACT" {
+			rv = rv.MethodByName("Exact").Call([]reflect.Value{})[0]
+			continue
+		}
+		if param == "ALMOST" {
+			rv = rv.MethodByName("Almost").Call([]reflect.Value{})[0]
+			continue
+		}
+		if threshold, has := strings.CutPrefix(param, "THRESHOLD:"); has {
+			rv = rv.MethodByName("Threshold").Call([]reflect.Value{reflect.ValueOf(threshold)})[0]
+			continue
+		}
+		if limit, has := strings.CutPrefix(param, "LIMIT:"); has {
+			vv, vvErr := strconv.ParseInt(limit, 10, 64)
+			if vvErr != nil {
+				return
+			}
+			rv = rv.MethodByName("Limit").Call([]reflect.Value{reflect.ValueOf(vv)})[0]
+			continue
+		}
+	}
+
+	rv = rv.MethodByName("Build").Call([]reflect.Value{})[0]
+	v = rv.Interface().(rueidis.Completed)
+
+	ok = true
+	return
+}
+
+func (b *XTRIMBuilder) Cacheable(client rueidis.Client, params []string) (v rueidis.Cacheable, ok bool) {
+	return
+}
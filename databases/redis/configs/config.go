@@ -64,33 +64,34 @@ type SentinelConfig struct {
 }
 
 type Config struct {
-	InitAddress           []string       `json:"initAddress" yaml:"initAddress"`
-	Addr                  []string       `json:"addr" yaml:"addr"`
-	Username              string         `json:"username" yaml:"username"`
-	Password              string         `json:"password" yaml:"password"`
-	ClientName            string         `json:"clientName" yaml:"clientName"`
-	ClientSetInfo         []string       `json:"clientSetInfo" yaml:"clientSetInfo"`
-	ClientTrackingOptions []string       `json:"clientTrackingOptions" yaml:"clientTrackingOptions"`
-	DB                    int            `json:"db" yaml:"db"`
-	CacheSizeEachConn     int            `json:"cacheSizeEachConn" yaml:"cacheSizeEachConn"`
-	RingScaleEachConn     int            `json:"ringScaleEachConn" yaml:"ringScaleEachConn"`
-	ReadBufferEachConn    int            `json:"readBufferEachConn" yaml:"readBufferEachConn"`
-	WriteBufferEachConn   int            `json:"writeBufferEachConn" yaml:"writeBufferEachConn"`
-	BlockingPoolSize      int            `json:"blockingPoolSize" yaml:"blockingPoolSize"`
-	PipelineMultiplex     int            `json:"pipelineMultiplex" yaml:"pipelineMultiplex"`
-	ConnWriteTimeout      time.Duration  `json:"connWriteTimeout" yaml:"connWriteTimeout"`
-	MaxFlushDelay         time.Duration  `json:"maxFlushDelay" yaml:"maxFlushDelay"`
-	ShuffleInit           bool           `json:"shuffleInit" yaml:"shuffleInit"`
-	ClientNoTouch         bool           `json:"clientNoTouch" yaml:"clientNoTouch"`
-	DisableRetry          bool           `json:"disableRetry" yaml:"disableRetry"`
-	DisableCache          bool           `json:"disableCache" yaml:"disableCache"`
-	AlwaysPipelining      bool           `json:"alwaysPipelining" yaml:"alwaysPipelining"`
-	AlwaysRESP2           bool           `json:"alwaysRESP2" yaml:"alwaysRESP2"`
-	ForceSingleClient     bool           `json:"forceSingleClient" yaml:"forceSingleClient"`
-	ReplicaOnly           bool           `json:"replicaOnly" yaml:"replicaOnly"`
-	ClientNoEvict         bool           `json:"clientNoEvict" yaml:"clientNoEvict"`
-	Sentinel              SentinelConfig `json:"sentinel" yaml:"sentinel"`
-	SSL                   SSLConfig      `json:"ssl" yaml:"ssl"`
+	InitAddress           []string               `json:"initAddress" yaml:"initAddress"`
+	Addr                  []string               `json:"addr" yaml:"addr"`
+	Username              string                 `json:"username" yaml:"username"`
+	Password              string                 `json:"password" yaml:"password"`
+	ClientName            string                 `json:"clientName" yaml:"clientName"`
+	ClientSetInfo         []string               `json:"clientSetInfo" yaml:"clientSetInfo"`
+	ClientTrackingOptions []string               `json:"clientTrackingOptions" yaml:"clientTrackingOptions"`
+	DB                    int                    `json:"db" yaml:"db"`
+	CacheSizeEachConn     int                    `json:"cacheSizeEachConn" yaml:"cacheSizeEachConn"`
+	RingScaleEachConn     int                    `json:"ringScaleEachConn" yaml:"ringScaleEachConn"`
+	ReadBufferEachConn    int                    `json:"readBufferEachConn" yaml:"readBufferEachConn"`
+	WriteBufferEachConn   int                    `json:"writeBufferEachConn" yaml:"writeBufferEachConn"`
+	BlockingPoolSize      int                    `json:"blockingPoolSize" yaml:"blockingPoolSize"`
+	PipelineMultiplex     int                    `json:"pipelineMultiplex" yaml:"pipelineMultiplex"`
+	ConnWriteTimeout      time.Duration          `json:"connWriteTimeout" yaml:"connWriteTimeout"`
+	MaxFlushDelay         time.Duration          `json:"maxFlushDelay" yaml:"maxFlushDelay"`
+	ShuffleInit           bool                   `json:"shuffleInit" yaml:"shuffleInit"`
+	ClientNoTouch         bool                   `json:"clientNoTouch" yaml:"clientNoTouch"`
+	DisableRetry          bool                   `json:"disableRetry" yaml:"disableRetry"`
+	DisableCache          bool                   `json:"disableCache" yaml:"disableCache"`
+	AlwaysPipelining      bool                   `json:"alwaysPipelining" yaml:"alwaysPipelining"`
+	AlwaysRESP2           bool                   `json:"alwaysRESP2" yaml:"alwaysRESP2"`
+	ForceSingleClient     bool                   `json:"forceSingleClient" yaml:"forceSingleClient"`
+	ReplicaOnly           bool                   `json:"replicaOnly" yaml:"replicaOnly"`
+	ClientNoEvict         bool                   `json:"clientNoEvict" yaml:"clientNoEvict"`
+	Sentinel              SentinelConfig         `json:"sentinel" yaml:"sentinel"`
+	SSL                   SSLConfig              `json:"ssl" yaml:"ssl"`
+	Consumers             []StreamConsumerConfig `json:"consumers" yaml:"consumers"`
 }
 
 func (config *Config) AsOption(options Options) (option rueidis.ClientOption, err error) {
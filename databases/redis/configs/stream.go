@@ -0,0 +1,23 @@
+package configs
+
+import (
+	"time"
+)
+
+// StreamConsumerConfig
+// consumer group of stream, each entry is sent to {endpoint}.{fn}, entry is acked when fn succeed.
+// pending entries which are idle over claimMinIdle are claimed by this consumer,
+// entries which are delivered over maxDeliveries are moved into dead letter stream.
+type StreamConsumerConfig struct {
+	Stream        string        `json:"stream" yaml:"stream"`
+	Group         string        `json:"group" yaml:"group"`
+	Consumer      string        `json:"consumer" yaml:"consumer"`
+	Endpoint      string        `json:"endpoint" yaml:"endpoint"`
+	Fn            string        `json:"fn" yaml:"fn"`
+	Count         int64         `json:"count" yaml:"count"`
+	Block         time.Duration `json:"block" yaml:"block"`
+	ClaimMinIdle  time.Duration `json:"claimMinIdle" yaml:"claimMinIdle"`
+	ClaimInterval time.Duration `json:"claimInterval" yaml:"claimInterval"`
+	MaxDeliveries int64         `json:"maxDeliveries" yaml:"maxDeliveries"`
+	DeadLetter    string        `json:"deadLetter" yaml:"deadLetter"`
+}
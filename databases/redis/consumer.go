@@ -0,0 +1,367 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/redis/configs"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/runtime"
+	"github.com/aacfactory/logs"
+	"github.com/redis/rueidis"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DeadLetterStreamField     = "@stream"
+	DeadLetterGroupField      = "@group"
+	DeadLetterIdField         = "@id"
+	DeadLetterDeliveriesField = "@deliveries"
+)
+
+// StreamEntry
+// param of fn which consumes entries of stream.
+// the fn should be idempotent, cause entry is redelivered when fn failed or consumer is dead before ack.
+type StreamEntry struct {
+	Stream      string            `json:"stream" avro:"stream"`
+	Group       string            `json:"group" avro:"group"`
+	Consumer    string            `json:"consumer" avro:"consumer"`
+	Id          string            `json:"id" avro:"id"`
+	FieldValues map[string]string `json:"fieldValues" avro:"fieldValues"`
+}
+
+func newStreamConsumer(id string, log logs.Logger, client rueidis.Client, config configs.StreamConsumerConfig) (consumer *StreamConsumer, err error) {
+	config.Stream = strings.TrimSpace(config.Stream)
+	if config.Stream == "" {
+		err = errors.Warning("redis: new stream consumer failed").WithCause(fmt.Errorf("stream is required"))
+		return
+	}
+	config.Group = strings.TrimSpace(config.Group)
+	if config.Group == "" {
+		err = errors.Warning("redis: new stream consumer failed").WithCause(fmt.Errorf("group is required")).WithMeta("stream", config.Stream)
+		return
+	}
+	config.Endpoint = strings.TrimSpace(config.Endpoint)
+	config.Fn = strings.TrimSpace(config.Fn)
+	if config.Endpoint == "" || config.Fn == "" {
+		err = errors.Warning("redis: new stream consumer failed").WithCause(fmt.Errorf("endpoint and fn are required")).WithMeta("stream", config.Stream).WithMeta("group", config.Group)
+		return
+	}
+	config.Consumer = strings.TrimSpace(config.Consumer)
+	if config.Consumer == "" {
+		config.Consumer = id
+	}
+	if config.Count < 1 {
+		config.Count = 16
+	}
+	if config.Block < 1 {
+		config.Block = 5 * time.Second
+	}
+	if config.ClaimMinIdle < 1 {
+		config.ClaimMinIdle = 1 * time.Minute
+	}
+	if config.ClaimInterval < 1 {
+		config.ClaimInterval = 30 * time.Second
+	}
+	if config.MaxDeliveries < 1 {
+		config.MaxDeliveries = 16
+	}
+	config.DeadLetter = strings.TrimSpace(config.DeadLetter)
+	if config.DeadLetter == "" {
+		config.DeadLetter = fmt.Sprintf("%s:dead", config.Stream)
+	}
+	consumer = &StreamConsumer{
+		log:      log.With("stream", config.Stream).With("group", config.Group),
+		client:   client,
+		config:   config,
+		endpoint: bytex.FromString(config.Endpoint),
+		fn:       bytex.FromString(config.Fn),
+		closeCh:  make(chan struct{}),
+		wg:       new(sync.WaitGroup),
+	}
+	return
+}
+
+// StreamConsumer
+// reads entries of stream by consumer group, and sends them to fn.
+type StreamConsumer struct {
+	log      logs.Logger
+	client   rueidis.Client
+	config   configs.StreamConsumerConfig
+	endpoint []byte
+	fn       []byte
+	closeCh  chan struct{}
+	wg       *sync.WaitGroup
+}
+
+func (consumer *StreamConsumer) Listen(ctx context.Context) (err error) {
+	createErr := consumer.client.Do(ctx, consumer.client.B().XgroupCreate().Key(consumer.config.Stream).Group(consumer.config.Group).Id("$").Mkstream().Build()).Error()
+	if createErr != nil && !rueidis.IsRedisBusyGroup(createErr) {
+		err = errors.Warning("redis: stream consumer listen failed").WithCause(createErr).WithMeta("stream", consumer.config.Stream).WithMeta("group", consumer.config.Group)
+		return
+	}
+	consumer.wg.Add(2)
+	go consumer.read(ctx)
+	go consumer.claim(ctx)
+	return
+}
+
+func (consumer *StreamConsumer) Shutdown(_ context.Context) {
+	close(consumer.closeCh)
+	consumer.wg.Wait()
+}
+
+func (consumer *StreamConsumer) closed(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-consumer.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (consumer *StreamConsumer) pause(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		break
+	case <-consumer.closeCh:
+		break
+	case <-timer.C:
+		break
+	}
+	timer.Stop()
+}
+
+// read
+// reads pending entries of this consumer first (id is 0), cause they were not acked before restart,
+// then reads new entries (id is >).
+func (consumer *StreamConsumer) read(ctx context.Context) {
+	defer consumer.wg.Done()
+	id := "0"
+	for {
+		if consumer.closed(ctx) {
+			return
+		}
+		cmd := consumer.client.B().Xreadgroup().
+			Group(consumer.config.Group, consumer.config.Consumer).
+			Count(consumer.config.Count).
+			Block(consumer.config.Block.Milliseconds()).
+			Streams().Key(consumer.config.Stream).Id(id).
+			Build()
+		streams, readErr := consumer.client.Do(ctx, cmd).AsXRead()
+		if readErr != nil {
+			if rueidis.IsRedisNil(readErr) {
+				continue
+			}
+			if consumer.closed(ctx) {
+				return
+			}
+			if consumer.log.WarnEnabled() {
+				consumer.log.Warn().Cause(readErr).Message("redis: stream consumer read failed")
+			}
+			consumer.pause(ctx, time.Second)
+			continue
+		}
+		entries := streams[consumer.config.Stream]
+		if id != ">" && len(entries) == 0 {
+			id = ">"
+			continue
+		}
+		for _, entry := range entries {
+			if consumer.closed(ctx) {
+				return
+			}
+			consumer.handle(ctx, entry)
+			if id != ">" {
+				// failed pending entry is left to claim, so read after it
+				id = entry.ID
+			}
+		}
+	}
+}
+
+// claim
+// moves entries which are delivered over max deliveries into dead letter stream,
+// then claims entries which are idle over min idle from other (dead) consumers and handles them.
+func (consumer *StreamConsumer) claim(ctx context.Context) {
+	defer consumer.wg.Done()
+	for {
+		consumer.pause(ctx, consumer.config.ClaimInterval)
+		if consumer.closed(ctx) {
+			return
+		}
+		consumer.bury(ctx)
+		start := "0-0"
+		for {
+			if consumer.closed(ctx) {
+				return
+			}
+			cmd := consumer.client.B().Xautoclaim().
+				Key(consumer.config.Stream).
+				Group(consumer.config.Group).
+				Consumer(consumer.config.Consumer).
+				MinIdleTime(strconv.FormatInt(consumer.config.ClaimMinIdle.Milliseconds(), 10)).
+				Start(start).
+				Count(consumer.config.Count).
+				Build()
+			values, claimErr := consumer.client.Do(ctx, cmd).ToArray()
+			if claimErr != nil || len(values) < 2 {
+				if claimErr != nil && consumer.log.WarnEnabled() {
+					consumer.log.Warn().Cause(claimErr).Message("redis: stream consumer claim failed")
+				}
+				break
+			}
+			start, _ = values[0].ToString()
+			entries, _ := values[1].ToArray()
+			for _, value := range entries {
+				if value.IsNil() {
+					// deleted entry, redis before 7.0
+					continue
+				}
+				entry, entryErr := value.AsXRangeEntry()
+				if entryErr != nil {
+					continue
+				}
+				consumer.handle(ctx, entry)
+			}
+			if start == "" || start == "0-0" {
+				break
+			}
+		}
+	}
+}
+
+// bury
+// XPENDING {stream} {group} IDLE {min idle} {start} + {count}, then entries which deliveries are over max are
+// claimed, added into dead letter stream and acked.
+func (consumer *StreamConsumer) bury(ctx context.Context) {
+	start := "-"
+	for {
+		if consumer.closed(ctx) {
+			return
+		}
+		cmd := consumer.client.B().Xpending().
+			Key(consumer.config.Stream).
+			Group(consumer.config.Group).
+			Idle(consumer.config.ClaimMinIdle.Milliseconds()).
+			Start(start).End("+").
+			Count(consumer.config.Count).
+			Build()
+		pending, pendingErr := consumer.client.Do(ctx, cmd).ToArray()
+		if pendingErr != nil {
+			if consumer.log.WarnEnabled() {
+				consumer.log.Warn().Cause(pendingErr).Message("redis: stream consumer read pending failed")
+			}
+			return
+		}
+		deliveries := make(map[string]int64)
+		ids := make([]string, 0, 1)
+		lastId := ""
+		for _, p := range pending {
+			values, _ := p.ToArray()
+			if len(values) != 4 {
+				continue
+			}
+			id, _ := values[0].ToString()
+			delivered, _ := values[3].AsInt64()
+			lastId = id
+			if delivered >= consumer.config.MaxDeliveries {
+				deliveries[id] = delivered
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			consumer.deadLetter(ctx, ids, deliveries)
+		}
+		if int64(len(pending)) < consumer.config.Count || lastId == "" {
+			return
+		}
+		start = fmt.Sprintf("(%s", lastId)
+	}
+}
+
+func (consumer *StreamConsumer) deadLetter(ctx context.Context, ids []string, deliveries map[string]int64) {
+	cmd := consumer.client.B().Xclaim().
+		Key(consumer.config.Stream).
+		Group(consumer.config.Group).
+		Consumer(consumer.config.Consumer).
+		MinIdleTime(strconv.FormatInt(consumer.config.ClaimMinIdle.Milliseconds(), 10)).
+		Id(ids...).
+		Build()
+	values, claimErr := consumer.client.Do(ctx, cmd).ToArray()
+	if claimErr != nil {
+		if consumer.log.WarnEnabled() {
+			consumer.log.Warn().Cause(claimErr).Message("redis: stream consumer claim dead entries failed")
+		}
+		return
+	}
+	buried := make([]string, 0, len(values))
+	for _, value := range values {
+		if value.IsNil() {
+			continue
+		}
+		entry, entryErr := value.AsXRangeEntry()
+		if entryErr != nil {
+			continue
+		}
+		add := consumer.client.B().Xadd().Key(consumer.config.DeadLetter).Id("*").FieldValue().
+			FieldValue(DeadLetterStreamField, consumer.config.Stream).
+			FieldValue(DeadLetterGroupField, consumer.config.Group).
+			FieldValue(DeadLetterIdField, entry.ID).
+			FieldValue(DeadLetterDeliveriesField, strconv.FormatInt(deliveries[entry.ID], 10))
+		for field, fv := range entry.FieldValues {
+			add = add.FieldValue(field, fv)
+		}
+		addErr := consumer.client.Do(ctx, add.Build()).Error()
+		if addErr != nil {
+			if consumer.log.WarnEnabled() {
+				consumer.log.Warn().Cause(addErr).With("id", entry.ID).Message("redis: stream consumer add dead letter failed")
+			}
+			continue
+		}
+		buried = append(buried, entry.ID)
+		if consumer.log.WarnEnabled() {
+			consumer.log.Warn().With("id", entry.ID).Message(fmt.Sprintf("redis: stream entry is moved into %s", consumer.config.DeadLetter))
+		}
+	}
+	if len(buried) == 0 {
+		return
+	}
+	ackErr := consumer.client.Do(ctx, consumer.client.B().Xack().Key(consumer.config.Stream).Group(consumer.config.Group).Id(buried...).Build()).Error()
+	if ackErr != nil && consumer.log.WarnEnabled() {
+		consumer.log.Warn().Cause(ackErr).Message("redis: stream consumer ack dead entries failed")
+	}
+}
+
+// handle
+// entry is acked when fn succeed, otherwise it is kept in pending list, and will be claimed later.
+func (consumer *StreamConsumer) handle(ctx context.Context, entry rueidis.XRangeEntry) {
+	if entry.FieldValues != nil {
+		param := StreamEntry{
+			Stream:      consumer.config.Stream,
+			Group:       consumer.config.Group,
+			Consumer:    consumer.config.Consumer,
+			Id:          entry.ID,
+			FieldValues: entry.FieldValues,
+		}
+		_, handleErr := runtime.Endpoints(ctx).Request(ctx, consumer.endpoint, consumer.fn, param)
+		if handleErr != nil {
+			if consumer.log.WarnEnabled() {
+				consumer.log.Warn().Cause(handleErr).With("id", entry.ID).
+					Message(fmt.Sprintf("redis: stream consumer handle entry failed, %s service handle %s fn failed", consumer.config.Endpoint, consumer.config.Fn))
+			}
+			return
+		}
+	}
+	// entry which field values are nil was deleted, so ack it too
+	ackErr := consumer.client.Do(ctx, consumer.client.B().Xack().Key(consumer.config.Stream).Group(consumer.config.Group).Id(entry.ID).Build()).Error()
+	if ackErr != nil && consumer.log.WarnEnabled() {
+		consumer.log.Warn().Cause(ackErr).With("id", entry.ID).Message("redis: stream consumer ack failed")
+	}
+}
@@ -81,6 +81,7 @@ type Message interface {
 	AsFloatMap() (v map[string]float64, err error)
 	AsXRangeEntry() (entry XRangeEntry, err error)
 	AsXRange() (entries []XRangeEntry, err error)
+	AsXRead() (streams map[string][]XRangeEntry, err error)
 	AsZScore() (v ZScore, err error)
 	AsZScores() (v []ZScore, err error)
 	AsScanEntry() (e ScanEntry, err error)
@@ -814,6 +815,47 @@ func (m message) AsXRange() (entries []XRangeEntry, err error) {
 	return
 }
 
+func (m message) AsXRead() (streams map[string][]XRangeEntry, err error) {
+	if err = m.Error(); err != nil {
+		return
+	}
+	switch m.Type {
+	case typeMap:
+		streams = make(map[string][]XRangeEntry, len(m.Values))
+		for _, value := range m.Values {
+			entries, entriesErr := value.AsXRange()
+			if entriesErr != nil {
+				err = entriesErr
+				return
+			}
+			streams[value.Content] = entries
+		}
+	case typeArray:
+		streams = make(map[string][]XRangeEntry, len(m.Values))
+		for _, value := range m.Values {
+			if len(value.Values) != 2 {
+				err = errors.New("REDIS: VALUE CAN NOT AS XRead")
+				return
+			}
+			key, keyErr := value.Values[0].AsString()
+			if keyErr != nil {
+				err = keyErr
+				return
+			}
+			entries, entriesErr := value.Values[1].AsXRange()
+			if entriesErr != nil {
+				err = entriesErr
+				return
+			}
+			streams[key] = entries
+		}
+	default:
+		err = errors.New("REDIS: VALUE CAN NOT AS XRead")
+		return
+	}
+	return
+}
+
 func (m message) AsZScore() (v ZScore, err error) {
 	if err = m.Error(); err != nil {
 		return
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/runtime"
+	"github.com/aacfactory/fns/services"
+	"github.com/redis/rueidis"
+)
+
+type publishHandler struct {
+	client rueidis.Client
+}
+
+func (handler *publishHandler) Name() string {
+	return string(publishFnName)
+}
+
+func (handler *publishHandler) Internal() bool {
+	return true
+}
+
+func (handler *publishHandler) Readonly() bool {
+	return false
+}
+
+func (handler *publishHandler) Handle(ctx services.Request) (v any, err error) {
+	param, paramErr := services.ValueOfParam[PublishParam](ctx.Param())
+	if paramErr != nil {
+		err = errors.Warning("redis: invalid param").WithCause(paramErr)
+		return
+	}
+	if param.Channel == "" {
+		err = errors.Warning("redis: invalid param").WithCause(fmt.Errorf("channel is required"))
+		return
+	}
+	var cmd rueidis.Completed
+	if param.Sharded {
+		cmd = handler.client.B().Spublish().Channel(param.Channel).Message(bytex.ToString(param.Payload)).Build()
+	} else {
+		cmd = handler.client.B().Publish().Channel(param.Channel).Message(bytex.ToString(param.Payload)).Build()
+	}
+	receivers, publishErr := handler.client.Do(ctx, cmd).AsInt64()
+	if publishErr != nil {
+		err = errors.Warning("redis: publish failed").WithCause(publishErr).WithMeta("channel", param.Channel)
+		return
+	}
+	v = receivers
+	return
+}
+
+type PublishParam struct {
+	Channel string `json:"channel" avro:"channel"`
+	Payload []byte `json:"payload" avro:"payload"`
+	Sharded bool   `json:"sharded" avro:"sharded"`
+}
+
+// Publish
+// returns the number of clients that received the message.
+func Publish(ctx context.Context, channel string, payload []byte) (receivers int64, err error) {
+	receivers, err = publish(ctx, PublishParam{
+		Channel: channel,
+		Payload: payload,
+		Sharded: false,
+	})
+	return
+}
+
+// SPublish
+// publish into sharded channel, redis 7.0 is required.
+func SPublish(ctx context.Context, channel string, payload []byte) (receivers int64, err error) {
+	receivers, err = publish(ctx, PublishParam{
+		Channel: channel,
+		Payload: payload,
+		Sharded: true,
+	})
+	return
+}
+
+func publish(ctx context.Context, param PublishParam) (receivers int64, err error) {
+	ep := used(ctx)
+	if len(ep) == 0 {
+		ep = endpointName
+	}
+	eps := runtime.Endpoints(ctx)
+	response, handleErr := eps.Request(ctx, ep, publishFnName, param)
+	if handleErr != nil {
+		err = handleErr
+		return
+	}
+	receivers, err = services.ValueOfResponse[int64](response)
+	return
+}
@@ -0,0 +1,55 @@
+package redis_test
+
+import (
+	"fmt"
+	"github.com/aacfactory/fns-contrib/databases/redis"
+	"github.com/aacfactory/fns-contrib/databases/redis/configs"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/tests"
+	"testing"
+	"time"
+)
+
+func TestPublish(t *testing.T) {
+	received := make(chan redis.SubscribeMessage, 1)
+	config := tests.Config()
+	config.AddService("redis", configs.Config{
+		InitAddress: []string{"127.0.0.1:16379"},
+	})
+	setupErr := tests.Setup(redis.New(redis.WithSubscriber("publish_test", func(ctx context.Context, message redis.SubscribeMessage) (err error) {
+		received <- message
+		return
+	})), tests.WithConfig(config))
+	if setupErr != nil {
+		t.Error(fmt.Sprintf("%+v", setupErr))
+		return
+	}
+	defer tests.Teardown()
+	ctx := tests.TODO()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		receivers, err := redis.Publish(ctx, "publish_test", []byte("some"))
+		if err != nil {
+			t.Errorf("%+v", err)
+			return
+		}
+		if receivers > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Error("subscriber is not ready")
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	select {
+	case message := <-received:
+		if message.Channel != "publish_test" || string(message.Payload) != "some" {
+			t.Errorf("message is not matched, %+v", message)
+		}
+		break
+	case <-time.After(5 * time.Second):
+		t.Error("message was not received")
+		break
+	}
+}
@@ -33,6 +33,7 @@ type Result interface {
 	AsFloatMap() (v map[string]float64, err error)
 	AsXRangeEntry() (entry XRangeEntry, err error)
 	AsXRange() (entries []XRangeEntry, err error)
+	AsXRead() (streams map[string][]XRangeEntry, err error)
 	AsZScore() (v ZScore, err error)
 	AsZScores() (v []ZScore, err error)
 	AsScanEntry() (e ScanEntry, err error)
@@ -336,6 +337,15 @@ func (r result) AsXRange() (entries []XRangeEntry, err error) {
 	return
 }
 
+func (r result) AsXRead() (streams map[string][]XRangeEntry, err error) {
+	if r.Err != "" {
+		err = errors.New(r.Err)
+		return
+	}
+	streams, err = r.Msg.AsXRead()
+	return
+}
+
 func (r result) AsZScore() (v ZScore, err error) {
 	if r.Err != "" {
 		err = errors.New(r.Err)
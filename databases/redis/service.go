@@ -15,6 +15,8 @@ var (
 	commandFnName          = []byte("command")
 	cacheableFnName        = []byte("cacheable")
 	luaFnName              = []byte("lua")
+	publishFnName          = []byte("publish")
+	subscribeFnName        = []byte("subscribe")
 	exportsFnName          = []byte("export")
 	endpointNameContextKey = []byte("@fns:redis:endpoint:name")
 )
@@ -65,7 +67,8 @@ func WithNewCacheStoreFn(fn rueidis.NewCacheStoreFn) Option {
 }
 
 type Options struct {
-	name string
+	name          string
+	subscriptions []subscription
 	configs.Options
 }
 
@@ -79,15 +82,19 @@ func New(options ...Option) services.Service {
 		option(&opt)
 	}
 	return &service{
-		Abstract: services.NewAbstract(opt.name, true),
-		opt:      opt.Options,
+		Abstract:      services.NewAbstract(opt.name, true),
+		opt:           opt.Options,
+		subscriptions: opt.subscriptions,
 	}
 }
 
 type service struct {
 	services.Abstract
-	opt    configs.Options
-	client rueidis.Client
+	opt           configs.Options
+	client        rueidis.Client
+	consumers     []*StreamConsumer
+	subscriptions []subscription
+	subscribers   []*subscriber
 }
 
 func (svc *service) Construct(options services.Options) (err error) {
@@ -127,10 +134,45 @@ func (svc *service) Construct(options services.Options) (err error) {
 		id:     options.Id,
 		client: svc.client,
 	})
+	svc.AddFunction(&publishHandler{
+		client: svc.client,
+	})
+	for _, consumerConfig := range config.Consumers {
+		consumer, consumerErr := newStreamConsumer(options.Id, svc.Log().With("component", "consumer"), svc.client, consumerConfig)
+		if consumerErr != nil {
+			err = errors.Warning("redis: service construct failed").WithCause(consumerErr).WithMeta("service", svc.Name())
+			return
+		}
+		svc.consumers = append(svc.consumers, consumer)
+	}
+	if len(svc.subscriptions) > 0 {
+		svc.AddFunction(newSubscribeHandler(svc.subscriptions))
+		svc.subscribers = newSubscribers(svc.Log().With("component", "subscriber"), svc.client, []byte(svc.Name()), svc.subscriptions)
+	}
 	return
 }
 
-func (svc *service) Shutdown(_ context.Context) {
+func (svc *service) Listen(ctx context.Context) (err error) {
+	for _, consumer := range svc.consumers {
+		err = consumer.Listen(ctx)
+		if err != nil {
+			err = errors.Warning("redis: service listen failed").WithCause(err).WithMeta("service", svc.Name())
+			return
+		}
+	}
+	for _, subscriber := range svc.subscribers {
+		subscriber.Listen(ctx)
+	}
+	return
+}
+
+func (svc *service) Shutdown(ctx context.Context) {
+	for _, consumer := range svc.consumers {
+		consumer.Shutdown(ctx)
+	}
+	for _, subscriber := range svc.subscribers {
+		subscriber.Shutdown(ctx)
+	}
 	svc.client.Close()
 }
 
@@ -0,0 +1,379 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/aacfactory/fns-contrib/databases/redis/cmds"
+	"strconv"
+	"time"
+)
+
+type streamTrim struct {
+	strategy  string
+	operator  string
+	threshold string
+	limit     int64
+}
+
+func (trim streamTrim) params() (params []string) {
+	if trim.strategy == "" {
+		return
+	}
+	params = append(params, trim.strategy)
+	if trim.operator != "" {
+		params = append(params, trim.operator)
+	}
+	params = append(params, fmt.Sprintf("THRESHOLD:%s", trim.threshold))
+	if trim.limit > 0 {
+		params = append(params, fmt.Sprintf("LIMIT:%d", trim.limit))
+	}
+	return
+}
+
+// XAdd
+// id is '*' when it is auto generated.
+func XAdd(key string, id string) XADDBuilder {
+	return XADDBuilder{
+		key: key,
+		id:  id,
+	}
+}
+
+type XADDBuilder struct {
+	key         string
+	id          string
+	noMkStream  bool
+	trim        streamTrim
+	fieldValues []string
+}
+
+func (builder XADDBuilder) NoMkStream() XADDBuilder {
+	builder.noMkStream = true
+	return builder
+}
+
+func (builder XADDBuilder) MaxLen(threshold int64) XADDBuilder {
+	builder.trim.strategy = "MAXLEN"
+	builder.trim.threshold = strconv.FormatInt(threshold, 10)
+	return builder
+}
+
+func (builder XADDBuilder) MinId(threshold string) XADDBuilder {
+	builder.trim.strategy = "MINID"
+	builder.trim.threshold = threshold
+	return builder
+}
+
+func (builder XADDBuilder) Exact() XADDBuilder {
+	builder.trim.operator = "EXACT"
+	return builder
+}
+
+func (builder XADDBuilder) Almost() XADDBuilder {
+	builder.trim.operator = "ALMOST"
+	return builder
+}
+
+func (builder XADDBuilder) Limit(count int64) XADDBuilder {
+	builder.trim.limit = count
+	return builder
+}
+
+func (builder XADDBuilder) FieldValue(field string, value string) XADDBuilder {
+	builder.fieldValues = append(builder.fieldValues, field, value)
+	return builder
+}
+
+func (builder XADDBuilder) FieldValues(values map[string]string) XADDBuilder {
+	for field, value := range values {
+		builder.fieldValues = append(builder.fieldValues, field, value)
+	}
+	return builder
+}
+
+func (builder XADDBuilder) Build() (cmd Command) {
+	params := []string{builder.key}
+	if builder.noMkStream {
+		params = append(params, "NOMKSTREAM")
+	}
+	params = append(params, builder.trim.params()...)
+	params = append(params, fmt.Sprintf("ID:%s", builder.id))
+	params = append(params, builder.fieldValues...)
+	cmd.Name = cmds.XADD
+	cmd.Params = params
+	return
+}
+
+func XAck(key string, group string, id ...string) XACKBuilder {
+	return XACKBuilder{
+		params: append([]string{key, group}, id...),
+	}
+}
+
+type XACKBuilder struct {
+	params []string
+}
+
+func (builder XACKBuilder) Build() (cmd Command) {
+	cmd.Name = cmds.XACK
+	cmd.Params = builder.params
+	return
+}
+
+// XReadGroup
+// use Stream to add keys and ids, id is '>' when reading new entries.
+func XReadGroup(group string, consumer string) XREADGROUPBuilder {
+	return XREADGROUPBuilder{
+		group:    group,
+		consumer: consumer,
+	}
+}
+
+type XREADGROUPBuilder struct {
+	group    string
+	consumer string
+	count    int64
+	block    time.Duration
+	noAck    bool
+	keys     []string
+	ids      []string
+}
+
+func (builder XREADGROUPBuilder) Count(count int64) XREADGROUPBuilder {
+	builder.count = count
+	return builder
+}
+
+func (builder XREADGROUPBuilder) Block(timeout time.Duration) XREADGROUPBuilder {
+	builder.block = timeout
+	return builder
+}
+
+func (builder XREADGROUPBuilder) NoAck() XREADGROUPBuilder {
+	builder.noAck = true
+	return builder
+}
+
+func (builder XREADGROUPBuilder) Stream(key string, id string) XREADGROUPBuilder {
+	builder.keys = append(builder.keys, key)
+	builder.ids = append(builder.ids, id)
+	return builder
+}
+
+func (builder XREADGROUPBuilder) Build() (cmd Command) {
+	params := []string{builder.group, builder.consumer}
+	if builder.count > 0 {
+		params = append(params, fmt.Sprintf("COUNT:%d", builder.count))
+	}
+	if builder.block > 0 {
+		params = append(params, fmt.Sprintf("BLOCK:%d", builder.block.Milliseconds()))
+	}
+	if builder.noAck {
+		params = append(params, "NOACK")
+	}
+	for _, key := range builder.keys {
+		params = append(params, fmt.Sprintf("KEY:%s", key))
+	}
+	for _, id := range builder.ids {
+		params = append(params, fmt.Sprintf("ID:%s", id))
+	}
+	cmd.Name = cmds.XREADGROUP
+	cmd.Params = params
+	return
+}
+
+func XClaim(key string, group string, consumer string, minIdle time.Duration, id ...string) XCLAIMBuilder {
+	params := []string{key, group, consumer, strconv.FormatInt(minIdle.Milliseconds(), 10)}
+	for _, s := range id {
+		params = append(params, fmt.Sprintf("ID:%s", s))
+	}
+	return XCLAIMBuilder{
+		params: params,
+	}
+}
+
+type XCLAIMBuilder struct {
+	params     []string
+	idle       time.Duration
+	time       time.Time
+	retryCount int64
+	force      bool
+	justId     bool
+}
+
+func (builder XCLAIMBuilder) Idle(idle time.Duration) XCLAIMBuilder {
+	builder.idle = idle
+	return builder
+}
+
+func (builder XCLAIMBuilder) Time(t time.Time) XCLAIMBuilder {
+	builder.time = t
+	return builder
+}
+
+func (builder XCLAIMBuilder) RetryCount(count int64) XCLAIMBuilder {
+	builder.retryCount = count
+	return builder
+}
+
+func (builder XCLAIMBuilder) Force() XCLAIMBuilder {
+	builder.force = true
+	return builder
+}
+
+func (builder XCLAIMBuilder) JustId() XCLAIMBuilder {
+	builder.justId = true
+	return builder
+}
+
+func (builder XCLAIMBuilder) Build() (cmd Command) {
+	params := append([]string{}, builder.params...)
+	if builder.idle > 0 {
+		params = append(params, fmt.Sprintf("IDLE:%d", builder.idle.Milliseconds()))
+	}
+	if !builder.time.IsZero() {
+		params = append(params, fmt.Sprintf("TIME:%d", builder.time.UnixMilli()))
+	}
+	if builder.retryCount > 0 {
+		params = append(params, fmt.Sprintf("RETRYCOUNT:%d", builder.retryCount))
+	}
+	if builder.force {
+		params = append(params, "FORCE")
+	}
+	if builder.justId {
+		params = append(params, "JUSTID")
+	}
+	cmd.Name = cmds.XCLAIM
+	cmd.Params = params
+	return
+}
+
+// XAutoClaim
+// start is '0-0' at first, then use the next start in result.
+func XAutoClaim(key string, group string, consumer string, minIdle time.Duration, start string) XAUTOCLAIMBuilder {
+	return XAUTOCLAIMBuilder{
+		params: []string{key, group, consumer, strconv.FormatInt(minIdle.Milliseconds(), 10), start},
+	}
+}
+
+type XAUTOCLAIMBuilder struct {
+	params []string
+	count  int64
+	justId bool
+}
+
+func (builder XAUTOCLAIMBuilder) Count(count int64) XAUTOCLAIMBuilder {
+	builder.count = count
+	return builder
+}
+
+func (builder XAUTOCLAIMBuilder) JustId() XAUTOCLAIMBuilder {
+	builder.justId = true
+	return builder
+}
+
+func (builder XAUTOCLAIMBuilder) Build() (cmd Command) {
+	params := append([]string{}, builder.params...)
+	if builder.count > 0 {
+		params = append(params, fmt.Sprintf("COUNT:%d", builder.count))
+	}
+	if builder.justId {
+		params = append(params, "JUSTID")
+	}
+	cmd.Name = cmds.XAUTOCLAIM
+	cmd.Params = params
+	return
+}
+
+// XPending
+// summary form when Range is not used.
+func XPending(key string, group string) XPENDINGBuilder {
+	return XPENDINGBuilder{
+		params: []string{key, group},
+	}
+}
+
+type XPENDINGBuilder struct {
+	params   []string
+	idle     time.Duration
+	start    string
+	end      string
+	count    int64
+	consumer string
+}
+
+func (builder XPENDINGBuilder) Idle(idle time.Duration) XPENDINGBuilder {
+	builder.idle = idle
+	return builder
+}
+
+func (builder XPENDINGBuilder) Range(start string, end string, count int64) XPENDINGBuilder {
+	builder.start = start
+	builder.end = end
+	builder.count = count
+	return builder
+}
+
+func (builder XPENDINGBuilder) Consumer(consumer string) XPENDINGBuilder {
+	builder.consumer = consumer
+	return builder
+}
+
+func (builder XPENDINGBuilder) Build() (cmd Command) {
+	params := append([]string{}, builder.params...)
+	if builder.start != "" {
+		if builder.idle > 0 {
+			params = append(params, fmt.Sprintf("IDLE:%d", builder.idle.Milliseconds()))
+		}
+		params = append(params, fmt.Sprintf("START:%s", builder.start), fmt.Sprintf("END:%s", builder.end), fmt.Sprintf("COUNT:%d", builder.count))
+		if builder.consumer != "" {
+			params = append(params, fmt.Sprintf("CONSUMER:%s", builder.consumer))
+		}
+	}
+	cmd.Name = cmds.XPENDING
+	cmd.Params = params
+	return
+}
+
+func XTrim(key string) XTRIMBuilder {
+	return XTRIMBuilder{
+		key: key,
+	}
+}
+
+type XTRIMBuilder struct {
+	key  string
+	trim streamTrim
+}
+
+func (builder XTRIMBuilder) MaxLen(threshold int64) XTRIMBuilder {
+	builder.trim.strategy = "MAXLEN"
+	builder.trim.threshold = strconv.FormatInt(threshold, 10)
+	return builder
+}
+
+func (builder XTRIMBuilder) MinId(threshold string) XTRIMBuilder {
+	builder.trim.strategy = "MINID"
+	builder.trim.threshold = threshold
+	return builder
+}
+
+func (builder XTRIMBuilder) Exact() XTRIMBuilder {
+	builder.trim.operator = "EXACT"
+	return builder
+}
+
+func (builder XTRIMBuilder) Almost() XTRIMBuilder {
+	builder.trim.operator = "ALMOST"
+	return builder
+}
+
+func (builder XTRIMBuilder) Limit(count int64) XTRIMBuilder {
+	builder.trim.limit = count
+	return builder
+}
+
+func (builder XTRIMBuilder) Build() (cmd Command) {
+	cmd.Name = cmds.XTRIM
+	cmd.Params = append([]string{builder.key}, builder.trim.params()...)
+	return
+}
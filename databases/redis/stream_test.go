@@ -0,0 +1,181 @@
+package redis_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"github.com/aacfactory/fns-contrib/databases/redis"
+	"github.com/aacfactory/fns/tests"
+	"github.com/redis/rueidis"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// offlineClient
+// client whose server replies OK to every command, it is used to build commands without redis.
+func offlineClient(t *testing.T) rueidis.Client {
+	t.Helper()
+	client, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:       []string{"offline:6379"},
+		ForceSingleClient: true,
+		AlwaysRESP2:       true,
+		DisableCache:      true,
+		DialFn: func(_ string, _ *net.Dialer, _ *tls.Config) (net.Conn, error) {
+			conn, server := net.Pipe()
+			go func(server net.Conn) {
+				reader := bufio.NewReader(server)
+				for {
+					line, readErr := reader.ReadString('\n')
+					if readErr != nil {
+						return
+					}
+					if !strings.HasPrefix(line, "*") {
+						continue
+					}
+					n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+					for i := 0; i < n*2; i++ {
+						if _, readErr = reader.ReadString('\n'); readErr != nil {
+							return
+						}
+					}
+					if _, writeErr := io.WriteString(server, "+OK\r\n"); writeErr != nil {
+						return
+					}
+				}
+			}(server)
+			return conn, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestStreamCommands(t *testing.T) {
+	client := offlineClient(t)
+	cases := []struct {
+		cmd      redis.IncompleteCommand
+		expected string
+	}{
+		{
+			cmd:      redis.XAdd("orders", "*").NoMkStream().MaxLen(100).Almost().Limit(10).FieldValue("id", "1"),
+			expected: "XADD orders NOMKSTREAM MAXLEN ~ 100 LIMIT 10 * id 1",
+		},
+		{
+			cmd:      redis.XAdd("orders", "1-1").MinId("0-1").Exact().FieldValue("id", "1"),
+			expected: "XADD orders MINID = 0-1 1-1 id 1",
+		},
+		{
+			cmd:      redis.XAck("orders", "billing", "1-1", "1-2"),
+			expected: "XACK orders billing 1-1 1-2",
+		},
+		{
+			cmd:      redis.XReadGroup("billing", "c1").Count(10).Block(time.Second).NoAck().Stream("orders", ">").Stream("refunds", ">"),
+			expected: "XREADGROUP GROUP billing c1 COUNT 10 BLOCK 1000 NOACK STREAMS orders refunds > >",
+		},
+		{
+			cmd:      redis.XClaim("orders", "billing", "c1", time.Minute, "1-1").RetryCount(3).Force().JustId(),
+			expected: "XCLAIM orders billing c1 60000 1-1 RETRYCOUNT 3 FORCE JUSTID",
+		},
+		{
+			cmd:      redis.XAutoClaim("orders", "billing", "c1", time.Minute, "0-0").Count(10).JustId(),
+			expected: "XAUTOCLAIM orders billing c1 60000 0-0 COUNT 10 JUSTID",
+		},
+		{
+			cmd:      redis.XPending("orders", "billing"),
+			expected: "XPENDING orders billing",
+		},
+		{
+			cmd:      redis.XPending("orders", "billing").Idle(time.Second).Range("-", "+", 10).Consumer("c1"),
+			expected: "XPENDING orders billing IDLE 1000 - + 10 c1",
+		},
+		{
+			cmd:      redis.XTrim("orders").MaxLen(100).Exact(),
+			expected: "XTRIM orders MAXLEN = 100",
+		},
+	}
+	for _, c := range cases {
+		cmd := c.cmd.Build()
+		completed, ok := redis.Commands{cmd}.Build(client)
+		if !ok {
+			t.Errorf("%s: build failed, params: %v", cmd.Name, cmd.Params)
+			continue
+		}
+		if got := strings.Join(completed[0].Commands(), " "); got != c.expected {
+			t.Errorf("%s: command is not matched\n expected: %s\n      got: %s", cmd.Name, c.expected, got)
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	setupErr := setup()
+	if setupErr != nil {
+		t.Error(fmt.Sprintf("%+v", setupErr))
+		return
+	}
+	defer tests.Teardown()
+	ctx := tests.TODO()
+	client, exportErr := redis.Export(ctx)
+	if exportErr != nil {
+		t.Errorf("%+v", exportErr)
+		return
+	}
+	stream := fmt.Sprintf("stream_test_%d", time.Now().UnixNano())
+	defer client.Do(ctx, client.B().Del().Key(stream).Build())
+	if err := client.Do(ctx, client.B().XgroupCreate().Key(stream).Group("g").Id("$").Mkstream().Build()).Error(); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	r, addErr := redis.Do(ctx, redis.XAdd(stream, "*").FieldValue("id", "1"))
+	if addErr != nil {
+		t.Errorf("%+v", addErr)
+		return
+	}
+	id, _ := r.AsString()
+	r, readErr := redis.Do(ctx, redis.XReadGroup("g", "c1").Count(10).Stream(stream, ">"))
+	if readErr != nil {
+		t.Errorf("%+v", readErr)
+		return
+	}
+	streams, streamsErr := r.AsXRead()
+	if streamsErr != nil {
+		t.Errorf("%+v", streamsErr)
+		return
+	}
+	entries := streams[stream]
+	if len(entries) != 1 || entries[0].Id != id || entries[0].FieldValues["id"] != "1" {
+		t.Errorf("entries are not matched, %+v", entries)
+		return
+	}
+	r, pendingErr := redis.Do(ctx, redis.XPending(stream, "g"))
+	if pendingErr != nil {
+		t.Errorf("%+v", pendingErr)
+		return
+	}
+	summary, _ := r.AsArray()
+	if pending, _ := summary[0].AsInt(); pending != 1 {
+		t.Errorf("pending must be 1, got %d", pending)
+	}
+	r, ackErr := redis.Do(ctx, redis.XAck(stream, "g", id))
+	if ackErr != nil {
+		t.Errorf("%+v", ackErr)
+		return
+	}
+	if acked, _ := r.AsInt(); acked != 1 {
+		t.Errorf("acked must be 1, got %d", acked)
+	}
+	r, trimErr := redis.Do(ctx, redis.XTrim(stream).MaxLen(0).Exact())
+	if trimErr != nil {
+		t.Errorf("%+v", trimErr)
+		return
+	}
+	if trimmed, _ := r.AsInt(); trimmed != 1 {
+		t.Errorf("trimmed must be 1, got %d", trimmed)
+	}
+}
@@ -0,0 +1,294 @@
+package redis
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/logs"
+	"github.com/aacfactory/fns/runtime"
+	"github.com/aacfactory/fns/services"
+	"github.com/redis/rueidis"
+	"sync"
+	"time"
+)
+
+const (
+	channelSubscription = iota + 1
+	patternSubscription
+	shardedSubscription
+)
+
+// SubscribeMessage
+// pattern is not empty when message is matched by pattern subscriber.
+type SubscribeMessage struct {
+	Channel string `json:"channel" avro:"channel"`
+	Pattern string `json:"pattern" avro:"pattern"`
+	Sharded bool   `json:"sharded" avro:"sharded"`
+	Payload []byte `json:"payload" avro:"payload"`
+}
+
+// SubscribeHandler
+// ctx is the request of subscribe fn, so fns can be called in handler.
+// handlers are called one by one, so long work should be executed in another goroutine.
+type SubscribeHandler func(ctx context.Context, message SubscribeMessage) (err error)
+
+type subscription struct {
+	kind    int
+	name    string
+	handler SubscribeHandler
+}
+
+// WithSubscriber
+// SUBSCRIBE {channel}
+func WithSubscriber(channel string, handler SubscribeHandler) Option {
+	return func(options *Options) {
+		if channel == "" || handler == nil {
+			return
+		}
+		options.subscriptions = append(options.subscriptions, subscription{kind: channelSubscription, name: channel, handler: handler})
+	}
+}
+
+// WithPatternSubscriber
+// PSUBSCRIBE {pattern}
+func WithPatternSubscriber(pattern string, handler SubscribeHandler) Option {
+	return func(options *Options) {
+		if pattern == "" || handler == nil {
+			return
+		}
+		options.subscriptions = append(options.subscriptions, subscription{kind: patternSubscription, name: pattern, handler: handler})
+	}
+}
+
+// WithShardedSubscriber
+// SSUBSCRIBE {channel}, redis 7.0 is required.
+func WithShardedSubscriber(channel string, handler SubscribeHandler) Option {
+	return func(options *Options) {
+		if channel == "" || handler == nil {
+			return
+		}
+		options.subscriptions = append(options.subscriptions, subscription{kind: shardedSubscription, name: channel, handler: handler})
+	}
+}
+
+// newSubscribers
+// channels and patterns share one connection, each sharded channel has its own connection, cause channels may be in different slots.
+func newSubscribers(log logs.Logger, client rueidis.Client, endpoint []byte, subscriptions []subscription) (subscribers []*subscriber) {
+	if len(subscriptions) == 0 {
+		return
+	}
+	var shared *subscriber
+	for _, s := range subscriptions {
+		if s.kind == shardedSubscription {
+			subscribers = append(subscribers, &subscriber{
+				log:      log.With("sharded", s.name),
+				client:   client,
+				endpoint: endpoint,
+				sharded:  []string{s.name},
+				closeCh:  make(chan struct{}),
+				wg:       new(sync.WaitGroup),
+			})
+			continue
+		}
+		if shared == nil {
+			shared = &subscriber{
+				log:      log,
+				client:   client,
+				endpoint: endpoint,
+				closeCh:  make(chan struct{}),
+				wg:       new(sync.WaitGroup),
+			}
+			subscribers = append(subscribers, shared)
+		}
+		if s.kind == patternSubscription {
+			shared.patterns = append(shared.patterns, s.name)
+		} else {
+			shared.channels = append(shared.channels, s.name)
+		}
+	}
+	return
+}
+
+// subscriber
+// subscribes on a dedicated connection, and subscribes again with a new dedicated connection when it is disconnected.
+// messages are sent to subscribe fn of redis service, so handlers are called with the request of fn.
+type subscriber struct {
+	log      logs.Logger
+	client   rueidis.Client
+	endpoint []byte
+	channels []string
+	patterns []string
+	sharded  []string
+	closeCh  chan struct{}
+	wg       *sync.WaitGroup
+}
+
+func (s *subscriber) Listen(ctx context.Context) {
+	s.wg.Add(1)
+	go s.listen(ctx)
+}
+
+func (s *subscriber) Shutdown(_ context.Context) {
+	close(s.closeCh)
+	s.wg.Wait()
+}
+
+func (s *subscriber) closed(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-s.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *subscriber) pause(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		break
+	case <-s.closeCh:
+		break
+	case <-timer.C:
+		break
+	}
+	timer.Stop()
+}
+
+func (s *subscriber) commands(client rueidis.DedicatedClient) (commands rueidis.Commands) {
+	if len(s.channels) > 0 {
+		commands = append(commands, client.B().Subscribe().Channel(s.channels...).Build())
+	}
+	if len(s.patterns) > 0 {
+		commands = append(commands, client.B().Psubscribe().Pattern(s.patterns...).Build())
+	}
+	if len(s.sharded) > 0 {
+		commands = append(commands, client.B().Ssubscribe().Channel(s.sharded...).Build())
+	}
+	return
+}
+
+func (s *subscriber) listen(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		if s.closed(ctx) {
+			return
+		}
+		client, cancel := s.client.Dedicate()
+		wait := client.SetPubSubHooks(rueidis.PubSubHooks{
+			OnMessage: func(m rueidis.PubSubMessage) {
+				s.dispatch(ctx, m)
+			},
+		})
+		subscribed := true
+		for _, r := range client.DoMulti(ctx, s.commands(client)...) {
+			if subscribeErr := r.Error(); subscribeErr != nil {
+				subscribed = false
+				if s.log.WarnEnabled() {
+					s.log.Warn().Cause(subscribeErr).Message("redis: subscribe failed")
+				}
+				break
+			}
+		}
+		if subscribed {
+			select {
+			case <-ctx.Done():
+				break
+			case <-s.closeCh:
+				break
+			case disconnectErr := <-wait:
+				if disconnectErr != nil && s.log.WarnEnabled() {
+					s.log.Warn().Cause(disconnectErr).Message("redis: subscriber is disconnected, and it will subscribe again")
+				}
+				break
+			}
+		}
+		cancel()
+		s.pause(ctx, time.Second)
+	}
+}
+
+func (s *subscriber) dispatch(ctx context.Context, m rueidis.PubSubMessage) {
+	message := SubscribeMessage{
+		Channel: m.Channel,
+		Pattern: m.Pattern,
+		Sharded: len(s.sharded) > 0,
+		Payload: []byte(m.Message),
+	}
+	_, handleErr := runtime.Endpoints(ctx).Request(ctx, s.endpoint, subscribeFnName, message)
+	if handleErr != nil && s.log.WarnEnabled() {
+		s.log.Warn().Cause(handleErr).With("channel", m.Channel).Message(fmt.Sprintf("redis: handle message of %s failed", m.Channel))
+	}
+}
+
+func newSubscribeHandler(subscriptions []subscription) *subscribeHandler {
+	handler := &subscribeHandler{
+		channels: make(map[string]SubscribeHandler),
+		patterns: make(map[string]SubscribeHandler),
+		sharded:  make(map[string]SubscribeHandler),
+	}
+	for _, s := range subscriptions {
+		switch s.kind {
+		case patternSubscription:
+			handler.patterns[s.name] = s.handler
+			break
+		case shardedSubscription:
+			handler.sharded[s.name] = s.handler
+			break
+		default:
+			handler.channels[s.name] = s.handler
+			break
+		}
+	}
+	return handler
+}
+
+// subscribeHandler
+// internal fn which calls the handler of message.
+type subscribeHandler struct {
+	channels map[string]SubscribeHandler
+	patterns map[string]SubscribeHandler
+	sharded  map[string]SubscribeHandler
+}
+
+func (handler *subscribeHandler) Name() string {
+	return string(subscribeFnName)
+}
+
+func (handler *subscribeHandler) Internal() bool {
+	return true
+}
+
+func (handler *subscribeHandler) Readonly() bool {
+	return false
+}
+
+func (handler *subscribeHandler) Handle(ctx services.Request) (v any, err error) {
+	message, paramErr := services.ValueOfParam[SubscribeMessage](ctx.Param())
+	if paramErr != nil {
+		err = errors.Warning("redis: invalid param").WithCause(paramErr)
+		return
+	}
+	fn := handler.lookup(message)
+	if fn == nil {
+		err = errors.Warning("redis: handle message failed").WithCause(fmt.Errorf("handler was not found")).WithMeta("channel", message.Channel)
+		return
+	}
+	err = fn(ctx, message)
+	return
+}
+
+func (handler *subscribeHandler) lookup(message SubscribeMessage) (fn SubscribeHandler) {
+	if message.Pattern != "" {
+		fn = handler.patterns[message.Pattern]
+		return
+	}
+	if message.Sharded {
+		fn = handler.sharded[message.Channel]
+		return
+	}
+	fn = handler.channels[message.Channel]
+	return
+}
@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/logs"
+	"testing"
+)
+
+func TestNewSubscribers(t *testing.T) {
+	handler := func(ctx context.Context, message SubscribeMessage) (err error) {
+		return
+	}
+	subscriptions := []subscription{
+		{kind: channelSubscription, name: "orders", handler: handler},
+		{kind: patternSubscription, name: "orders:*", handler: handler},
+		{kind: shardedSubscription, name: "{orders}:created", handler: handler},
+		{kind: shardedSubscription, name: "{orders}:paid", handler: handler},
+	}
+	log, logErr := logs.New(logs.Config{}, nil)
+	if logErr != nil {
+		t.Fatal(logErr)
+	}
+	subscribers := newSubscribers(log, nil, []byte("redis"), subscriptions)
+	if len(subscribers) != 3 {
+		t.Fatalf("subscribers must be 3, got %d", len(subscribers))
+	}
+	shared := subscribers[0]
+	if len(shared.channels) != 1 || shared.channels[0] != "orders" || len(shared.patterns) != 1 || shared.patterns[0] != "orders:*" || len(shared.sharded) != 0 {
+		t.Errorf("shared subscriber is not matched, %v %v %v", shared.channels, shared.patterns, shared.sharded)
+	}
+	for i, name := range []string{"{orders}:created", "{orders}:paid"} {
+		sharded := subscribers[i+1]
+		if len(sharded.sharded) != 1 || sharded.sharded[0] != name || len(sharded.channels) != 0 || len(sharded.patterns) != 0 {
+			t.Errorf("sharded subscriber of %s is not matched, %v", name, sharded.sharded)
+		}
+		if string(sharded.endpoint) != "redis" {
+			t.Errorf("endpoint must be redis, got %s", sharded.endpoint)
+		}
+	}
+}
+
+func TestSubscribeHandlerLookup(t *testing.T) {
+	hits := ""
+	hit := func(name string) SubscribeHandler {
+		return func(ctx context.Context, message SubscribeMessage) (err error) {
+			hits = name
+			return
+		}
+	}
+	handler := newSubscribeHandler([]subscription{
+		{kind: channelSubscription, name: "orders", handler: hit("channel")},
+		{kind: patternSubscription, name: "orders:*", handler: hit("pattern")},
+		{kind: shardedSubscription, name: "orders", handler: hit("sharded")},
+	})
+	cases := []struct {
+		message  SubscribeMessage
+		expected string
+	}{
+		{message: SubscribeMessage{Channel: "orders"}, expected: "channel"},
+		{message: SubscribeMessage{Channel: "orders:created", Pattern: "orders:*"}, expected: "pattern"},
+		{message: SubscribeMessage{Channel: "orders", Sharded: true}, expected: "sharded"},
+		{message: SubscribeMessage{Channel: "users"}, expected: ""},
+	}
+	for _, c := range cases {
+		hits = ""
+		fn := handler.lookup(c.message)
+		if fn != nil {
+			_ = fn(nil, c.message)
+		}
+		if hits != c.expected {
+			t.Errorf("%+v must be handled by %q, got %q", c.message, c.expected, hits)
+		}
+	}
+}
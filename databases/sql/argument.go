@@ -311,6 +311,19 @@ func NewArgument(v any) (argument Argument, err error) {
 			argument.Nil = true
 		}
 		break
+	case Geometry:
+		argument.Type = "string"
+		gv, gErr := vv.Value()
+		if gErr != nil {
+			err = errors.Warning("sql: new argument failed").WithCause(gErr).WithMeta("type", "geometry")
+			return
+		}
+		if gv == nil {
+			argument.Nil = true
+			break
+		}
+		argument.Value, _ = avro.Marshal(gv)
+		break
 	default:
 		rv := reflect.ValueOf(v)
 		rt := rv.Type()
@@ -11,8 +11,14 @@ type Config struct {
 	Isolation         databases.Isolation `json:"isolation"`
 	TransactionMaxAge int                 `json:"transactionMaxAge"`
 	DebugLog          bool                `json:"debugLog"`
-	SSL               SSLConfig           `json:"ssl"`
-	Options           json.RawMessage     `json:"options"`
+	// StatementTimeout
+	// default timeout of statement in milliseconds, it is also set to server when dialect supports it.
+	StatementTimeout int `json:"statementTimeout"`
+	// Rows
+	// encoding of rows which are returned to other nodes.
+	Rows    RowsConfig      `json:"rows"`
+	SSL     SSLConfig       `json:"ssl"`
+	Options json.RawMessage `json:"options"`
 }
 
 type SSLConfig struct {
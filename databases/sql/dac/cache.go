@@ -0,0 +1,200 @@
+package dac
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/logs"
+	"github.com/aacfactory/fns/runtime"
+	"github.com/aacfactory/json"
+	"hash/fnv"
+	"strconv"
+	"sync"
+)
+
+var (
+	cacheKeyPrefix          = []byte("fns:sql:dac:cache:")
+	cacheVersionsContextKey = []byte("@fns:sql:dac:cache:versions")
+)
+
+// cacheable
+// result of table can be cached when table has cache ttl and ctx is not in transaction.
+func cacheable(ctx context.Context, spec *specifications.Specification) bool {
+	return spec.CacheTTL > 0 && !spec.View && !sql.InTransaction(ctx)
+}
+
+func cacheTableName(spec *specifications.Specification) string {
+	if spec.Schema == "" {
+		return spec.Name
+	}
+	return spec.Schema + "." + spec.Name
+}
+
+func cacheVersionKey(spec *specifications.Specification) []byte {
+	key := make([]byte, 0, len(cacheKeyPrefix)+len(spec.Schema)+len(spec.Name)+16)
+	key = append(key, cacheKeyPrefix...)
+	key = append(key, "version:"...)
+	key = append(key, cacheTableName(spec)...)
+	return key
+}
+
+// cacheTables
+// table and tables of ref, link and links columns, cause cached rows contain them.
+func cacheTables(spec *specifications.Specification, visited map[string]struct{}) (tables []*specifications.Specification) {
+	if _, has := visited[spec.Key]; has {
+		return
+	}
+	visited[spec.Key] = struct{}{}
+	tables = append(tables, spec)
+	for _, column := range spec.Columns {
+		var mapping *specifications.Specification
+		if _, m, ok := column.Reference(); ok {
+			mapping = m
+		} else if _, _, m, ok := column.Link(); ok {
+			mapping = m
+		} else if _, _, m, _, _, ok := column.Links(); ok {
+			mapping = m
+		}
+		if mapping != nil {
+			tables = append(tables, cacheTables(mapping, visited)...)
+		}
+	}
+	return
+}
+
+// cacheVersions
+// versions of tables are cached in context, so they are loaded once per request.
+type cacheVersions struct {
+	locker sync.Mutex
+	values map[string]int64
+}
+
+func loadCacheVersions(ctx context.Context) (versions *cacheVersions) {
+	versions, has := context.LocalValue[*cacheVersions](ctx, cacheVersionsContextKey)
+	if !has {
+		versions = &cacheVersions{
+			values: make(map[string]int64),
+		}
+		ctx.SetLocalValue(cacheVersionsContextKey, versions)
+	}
+	return
+}
+
+func cacheVersion(ctx context.Context, spec *specifications.Specification) (version int64, err error) {
+	versions := loadCacheVersions(ctx)
+	name := cacheTableName(spec)
+	versions.locker.Lock()
+	version, has := versions.values[name]
+	versions.locker.Unlock()
+	if has {
+		return
+	}
+	store := runtime.SharedStore(ctx)
+	version, err = store.Incr(ctx, cacheVersionKey(spec), 0)
+	if err != nil {
+		return
+	}
+	versions.locker.Lock()
+	versions.values[name] = version
+	versions.locker.Unlock()
+	return
+}
+
+// makeCacheKey
+// key is made of table, versions of table and its ref and link tables, and hash of query and arguments.
+// version of table is increased when table is written, so cached results of old version are never hit again.
+func makeCacheKey(ctx context.Context, spec *specifications.Specification, query []byte, arguments []any) (key []byte, err error) {
+	args, encodeErr := json.Marshal(arguments)
+	if encodeErr != nil {
+		err = errors.Warning("sql: make cache key failed").WithCause(encodeErr)
+		return
+	}
+	h := fnv.New128a()
+	_, _ = h.Write(query)
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(args)
+	key = make([]byte, 0, len(cacheKeyPrefix)+64)
+	key = append(key, cacheKeyPrefix...)
+	key = append(key, cacheTableName(spec)...)
+	for _, table := range cacheTables(spec, make(map[string]struct{})) {
+		version, versionErr := cacheVersion(ctx, table)
+		if versionErr != nil {
+			err = errors.Warning("sql: make cache key failed").WithCause(versionErr).WithMeta("table", cacheTableName(table))
+			return
+		}
+		key = append(key, ':')
+		key = strconv.AppendInt(key, version, 10)
+	}
+	key = append(key, ':')
+	key = hex.AppendEncode(key, h.Sum(nil))
+	return
+}
+
+func getCachedRows(ctx context.Context, key []byte) (rows sql.Rows, has bool, err error) {
+	store := runtime.SharedStore(ctx)
+	p, exist, getErr := store.Get(ctx, key)
+	if getErr != nil {
+		err = errors.Warning("sql: get cached rows failed").WithCause(getErr).WithMeta("key", bytex.ToString(key))
+		return
+	}
+	if !exist || len(p) == 0 {
+		return
+	}
+	decodeErr := rows.UnmarshalAvro(p)
+	if decodeErr != nil {
+		err = errors.Warning("sql: get cached rows failed").WithCause(decodeErr).WithMeta("key", bytex.ToString(key))
+		return
+	}
+	has = true
+	return
+}
+
+// setCachedRows
+// rows are consumed, so use returned rows to scan.
+// failure of storing does not fail the query.
+func setCachedRows(ctx context.Context, spec *specifications.Specification, key []byte, rows sql.Rows) (v sql.Rows, err error) {
+	p, encodeErr := rows.MarshalAvro()
+	_ = rows.Close()
+	if encodeErr != nil {
+		err = errors.Warning("sql: set cached rows failed").WithCause(encodeErr).WithMeta("key", bytex.ToString(key))
+		return
+	}
+	decodeErr := v.UnmarshalAvro(p)
+	if decodeErr != nil {
+		err = errors.Warning("sql: set cached rows failed").WithCause(decodeErr).WithMeta("key", bytex.ToString(key))
+		return
+	}
+	store := runtime.SharedStore(ctx)
+	if setErr := store.SetWithTTL(ctx, key, p, spec.CacheTTL); setErr != nil {
+		log := logs.Load(ctx)
+		if log.WarnEnabled() {
+			log.Warn().Cause(setErr).With("table", cacheTableName(spec)).Message(fmt.Sprintf("sql: set cached rows of %s failed", cacheTableName(spec)))
+		}
+	}
+	return
+}
+
+// invalidateCache
+// increase version of table, table may have no cache ttl but be a ref or link table of cached table.
+// when ctx is in transaction, it is deferred until transaction is committed by sql service, and it is skipped when transaction is rolled back.
+// failure of invalidation does not fail the write, cached results are expired by ttl at last.
+func invalidateCache[T Table](ctx context.Context) {
+	spec, specErr := specifications.GetSpecification(ctx, specifications.Instance[T]())
+	if specErr != nil || spec.View {
+		return
+	}
+	versions := loadCacheVersions(ctx)
+	versions.locker.Lock()
+	delete(versions.values, cacheTableName(spec))
+	versions.locker.Unlock()
+	if incrErr := sql.IncrAfterCommitted(ctx, cacheVersionKey(spec)); incrErr != nil {
+		log := logs.Load(ctx)
+		if log.WarnEnabled() {
+			log.Warn().Cause(incrErr).With("table", cacheTableName(spec)).Message(fmt.Sprintf("sql: invalidate cache of %s failed", cacheTableName(spec)))
+		}
+	}
+}
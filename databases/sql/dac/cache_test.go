@@ -0,0 +1,108 @@
+package dac
+
+import (
+	stdctx "context"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/versions"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/runtime"
+	"github.com/aacfactory/fns/shareds"
+	"testing"
+	"time"
+)
+
+type cacheUser struct {
+	Id   string `column:"ID,pk"`
+	Name string `column:"NAME"`
+}
+
+func (row cacheUser) TableInfo() TableInfo {
+	return Info("USER", Schema("FNS"))
+}
+
+type cacheComment struct {
+	Id     string    `column:"ID,pk"`
+	PostId string    `column:"POST_ID"`
+	User   cacheUser `column:"USER_ID,ref,Id"`
+}
+
+func (row cacheComment) TableInfo() TableInfo {
+	return Info("COMMENT", Schema("FNS"))
+}
+
+type cachePost struct {
+	Id       string         `column:"ID,pk"`
+	Author   cacheUser      `column:"AUTHOR,ref,Id"`
+	Comments []cacheComment `column:"COMMENTS,links,Id+PostId"`
+}
+
+func (row cachePost) TableInfo() TableInfo {
+	return Info("POST", Schema("FNS"), Cache(time.Minute))
+}
+
+func newCacheTestContext(t *testing.T, shared shareds.Shared) context.Context {
+	t.Helper()
+	ctx := context.Acquire(stdctx.TODO())
+	runtime.With(ctx, runtime.New("id", "test", versions.Origin(), nil, nil, nil, nil, nil, shared))
+	return ctx
+}
+
+func TestCacheTables(t *testing.T) {
+	ctx := context.Acquire(stdctx.TODO())
+	spec, specErr := specifications.GetSpecification(ctx, cachePost{})
+	if specErr != nil {
+		t.Errorf("%+v", specErr)
+		return
+	}
+	names := make(map[string]bool)
+	for _, table := range cacheTables(spec, make(map[string]struct{})) {
+		names[cacheTableName(table)] = true
+	}
+	if len(names) != 3 || !names["FNS.POST"] || !names["FNS.USER"] || !names["FNS.COMMENT"] {
+		t.Errorf("tables are not matched, %v", names)
+	}
+}
+
+func TestMakeCacheKey(t *testing.T) {
+	shared, sharedErr := shareds.Local(nil, shareds.LocalSharedConfig{})
+	if sharedErr != nil {
+		t.Errorf("%+v", sharedErr)
+		return
+	}
+	defer shared.Close()
+	ctx := newCacheTestContext(t, shared)
+	spec, specErr := specifications.GetSpecification(ctx, cachePost{})
+	if specErr != nil {
+		t.Errorf("%+v", specErr)
+		return
+	}
+	query := []byte(`SELECT * FROM "FNS"."POST"`)
+	key, keyErr := makeCacheKey(ctx, spec, query, nil)
+	if keyErr != nil {
+		t.Errorf("%+v", keyErr)
+		return
+	}
+	// version is loaded once per request
+	userSpec, _ := specifications.GetSpecification(ctx, cacheUser{})
+	_, _ = shared.Store().Incr(ctx, cacheVersionKey(userSpec), 1)
+	same, _ := makeCacheKey(ctx, spec, query, nil)
+	if string(same) != string(key) {
+		t.Errorf("version must be cached in request")
+		return
+	}
+	// write of ref table invalidates cache of post
+	other := newCacheTestContext(t, shared)
+	invalidateCache[cacheUser](other)
+	changed, _ := makeCacheKey(other, spec, query, nil)
+	if string(changed) == string(key) {
+		t.Errorf("write of ref table must change key")
+		return
+	}
+	// own write refreshes version in request
+	invalidateCache[cachePost](other)
+	refreshed, _ := makeCacheKey(other, spec, query, nil)
+	if string(refreshed) == string(changed) {
+		t.Errorf("write of table must change key in same request")
+		return
+	}
+}
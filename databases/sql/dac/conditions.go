@@ -2,6 +2,7 @@ package dac
 
 import (
 	"database/sql"
+	ssql "github.com/aacfactory/fns-contrib/databases/sql"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
 	"time"
 )
@@ -54,6 +55,62 @@ func LikeContains(field string, expression string) conditions.Condition {
 	return conditions.New(conditions.LikeContains(field, expression))
 }
 
+func DistanceWithin(field string, geometry ssql.Geometry, distance float64) conditions.Condition {
+	return conditions.New(conditions.DistanceWithin(field, geometry, distance))
+}
+
+func Intersects(field string, geometry ssql.Geometry) conditions.Condition {
+	return conditions.New(conditions.Intersects(field, geometry))
+}
+
+func Contains(field string, geometry ssql.Geometry) conditions.Condition {
+	return conditions.New(conditions.Contains(field, geometry))
+}
+
+func JsonContains(field string, value any) conditions.Condition {
+	return conditions.New(conditions.JsonContains(field, value))
+}
+
+func JsonHasKey(field string, path string) conditions.Condition {
+	return conditions.New(conditions.JsonHasKey(field, path))
+}
+
+func JsonMemberOf(field string, element any) conditions.Condition {
+	return conditions.New(conditions.JsonMemberOf(field, element))
+}
+
+func JsonContainsAll(field string, elements ...any) conditions.Condition {
+	return conditions.New(conditions.JsonContainsAll(field, elements...))
+}
+
+func JsonContainsAny(field string, elements ...any) conditions.Condition {
+	return conditions.New(conditions.JsonContainsAny(field, elements...))
+}
+
+func JsonPathEq(field string, path string, value any) conditions.Condition {
+	return conditions.New(conditions.JsonPath(field, path, conditions.Equal, value))
+}
+
+func JsonPathNotEq(field string, path string, value any) conditions.Condition {
+	return conditions.New(conditions.JsonPath(field, path, conditions.NotEqual, value))
+}
+
+func JsonPathGt(field string, path string, value any) conditions.Condition {
+	return conditions.New(conditions.JsonPath(field, path, conditions.GreatThan, value))
+}
+
+func JsonPathGte(field string, path string, value any) conditions.Condition {
+	return conditions.New(conditions.JsonPath(field, path, conditions.GreatThanOrEqual, value))
+}
+
+func JsonPathLt(field string, path string, value any) conditions.Condition {
+	return conditions.New(conditions.JsonPath(field, path, conditions.LessThan, value))
+}
+
+func JsonPathLte(field string, path string, value any) conditions.Condition {
+	return conditions.New(conditions.JsonPath(field, path, conditions.LessThanOrEqual, value))
+}
+
 func SubQuery(query any, field string, cond conditions.Condition) conditions.QueryExpr {
 	return conditions.Query(query, field, cond)
 }
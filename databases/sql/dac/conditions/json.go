@@ -0,0 +1,84 @@
+package conditions
+
+const (
+	JSONCONTAINS = Operator("JSON_CONTAINS")
+	JSONHASKEY   = Operator("JSON_HAS_KEY")
+	JSONMEMBEROF = Operator("JSON_MEMBER_OF")
+	JSONOVERLAPS = Operator("JSON_OVERLAPS")
+)
+
+// Json
+// expression of json predicate, which is rendered by dialect.
+// Path is dot separated keys, such as `a.b.0`, and number key means index of array.
+// Value is encoded into json, except json.RawMessage and []byte.
+type Json struct {
+	Path  string
+	Value any
+}
+
+// JsonContains
+// json column contains value, such as object contains sub object, or array contains all elements of array.
+func JsonContains(field string, value any) Predicate {
+	return Predicate{
+		Field:    field,
+		Operator: JSONCONTAINS,
+		Expression: Json{
+			Value: value,
+		},
+	}
+}
+
+// JsonHasKey
+// json column has the path.
+func JsonHasKey(field string, path string) Predicate {
+	return Predicate{
+		Field:    field,
+		Operator: JSONHASKEY,
+		Expression: Json{
+			Path: path,
+		},
+	}
+}
+
+// JsonMemberOf
+// json array column has the element.
+func JsonMemberOf(field string, element any) Predicate {
+	return Predicate{
+		Field:    field,
+		Operator: JSONMEMBEROF,
+		Expression: Json{
+			Value: element,
+		},
+	}
+}
+
+// JsonContainsAll
+// json array column has all elements.
+func JsonContainsAll(field string, elements ...any) Predicate {
+	return JsonContains(field, elements)
+}
+
+// JsonContainsAny
+// json array column has any of elements.
+func JsonContainsAny(field string, elements ...any) Predicate {
+	return Predicate{
+		Field:    field,
+		Operator: JSONOVERLAPS,
+		Expression: Json{
+			Value: elements,
+		},
+	}
+}
+
+// JsonPath
+// compare value of path in json column, operator must be one of Equal, NotEqual, GreatThan, GreatThanOrEqual, LessThan and LessThanOrEqual.
+func JsonPath(field string, path string, operator Operator, value any) Predicate {
+	return Predicate{
+		Field:    field,
+		Operator: operator,
+		Expression: Json{
+			Path:  path,
+			Value: value,
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package conditions
+
+import "github.com/aacfactory/fns-contrib/databases/sql"
+
+const (
+	DWITHIN    = Operator("DWITHIN")
+	INTERSECTS = Operator("INTERSECTS")
+	CONTAINS   = Operator("CONTAINS")
+)
+
+// Spatial
+// expression of spatial predicate, which is rendered by dialect.
+type Spatial struct {
+	Geometry sql.Geometry
+	Distance float64
+}
+
+// DistanceWithin
+// distance unit is meter when column is geography or srid of geometry is geographic, otherwise is unit of srid.
+func DistanceWithin(field string, geometry sql.Geometry, distance float64) Predicate {
+	return Predicate{
+		Field:    field,
+		Operator: DWITHIN,
+		Expression: Spatial{
+			Geometry: geometry,
+			Distance: distance,
+		},
+	}
+}
+
+func Intersects(field string, geometry sql.Geometry) Predicate {
+	return Predicate{
+		Field:    field,
+		Operator: INTERSECTS,
+		Expression: Spatial{
+			Geometry: geometry,
+		},
+	}
+}
+
+// Contains
+// column contains geometry
+func Contains(field string, geometry sql.Geometry) Predicate {
+	return Predicate{
+		Field:    field,
+		Operator: CONTAINS,
+		Expression: Spatial{
+			Geometry: geometry,
+		},
+	}
+}
@@ -9,6 +9,12 @@ import (
 )
 
 func Count[T Table](ctx context.Context, cond conditions.Condition) (count int64, err error) {
+	release, shardingErr := useConditionSharding[T](ctx, cond, sql.ShardingMerge{Sum: true})
+	if shardingErr != nil {
+		err = errors.Warning("sql: count failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	_, query, arguments, buildErr := specifications.BuildCount[T](ctx, specifications.Condition{Condition: cond})
 	if buildErr != nil {
 		err = errors.Warning("sql: count failed").WithCause(buildErr)
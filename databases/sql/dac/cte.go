@@ -0,0 +1,56 @@
+package dac
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/groups"
+	"strings"
+)
+
+// CTE
+// named common table expression, see CTEView.
+type CTE struct {
+	name    string
+	source  any
+	cond    conditions.Condition
+	groupBy groups.GroupBy
+}
+
+func (cte CTE) Name() string {
+	return cte.name
+}
+
+func (cte CTE) Source() any {
+	return cte.source
+}
+
+func (cte CTE) Condition() conditions.Condition {
+	return cte.cond
+}
+
+func (cte CTE) GroupBy() groups.GroupBy {
+	return cte.groupBy
+}
+
+// With
+// cte which is select of view, only Conditions and GroupBy of options are used.
+func With(name string, view View, options ...QueryOption) CTE {
+	opt := QueryOptions{}
+	for _, option := range options {
+		option(&opt)
+	}
+	return CTE{
+		name:    strings.TrimSpace(name),
+		source:  view,
+		cond:    opt.cond,
+		groupBy: opt.groupBy,
+	}
+}
+
+// WithQuery
+// cte which is query, query can not have arguments.
+func WithQuery(name string, query string) CTE {
+	return CTE{
+		name:   strings.TrimSpace(name),
+		source: query,
+	}
+}
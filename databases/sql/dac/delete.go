@@ -5,22 +5,34 @@ import (
 	"github.com/aacfactory/fns-contrib/databases/sql"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
 	"github.com/aacfactory/fns/context"
 )
 
 func Delete[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
 	entries := []T{entry}
+	release, shardingErr := useEntriesSharding[T](ctx, entries)
+	if shardingErr != nil {
+		err = errors.Warning("sql: delete failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	_, query, arguments, buildErr := specifications.BuildDelete[T](ctx, entries)
 	if buildErr != nil {
 		err = errors.Warning("sql: delete failed").WithCause(buildErr)
 		return
 	}
 
-	result, execErr := sql.Execute(ctx, query, arguments...)
+	var result databases.Result
+	execErr := withEntryHistory[T](ctx, specifications.HistoryDeleteOp, entry, func() (err error) {
+		result, err = sql.Execute(ctx, query, arguments...)
+		return
+	})
 	if execErr != nil {
 		err = errors.Warning("sql: delete failed").WithCause(execErr)
 		return
 	}
+	invalidateCache[T](ctx)
 
 	if ok = result.RowsAffected == 1; ok {
 		verErr := specifications.TrySetupAuditVersion[T](ctx, entries)
@@ -34,17 +46,28 @@ func Delete[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
 }
 
 func DeleteByCondition[T Table](ctx context.Context, cond conditions.Condition) (affected int64, err error) {
+	release, shardingErr := useConditionSharding[T](ctx, cond, sql.ShardingMerge{})
+	if shardingErr != nil {
+		err = errors.Warning("sql: delete by condition failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	_, query, arguments, buildErr := specifications.BuildDeleteByCondition[T](ctx, specifications.Condition{Condition: cond})
 	if buildErr != nil {
 		err = errors.Warning("sql: delete by condition failed").WithCause(buildErr)
 		return
 	}
 
-	result, execErr := sql.Execute(ctx, query, arguments...)
+	var result databases.Result
+	execErr := withConditionHistory[T](ctx, specifications.HistoryDeleteOp, cond, func() (err error) {
+		result, err = sql.Execute(ctx, query, arguments...)
+		return
+	})
 	if execErr != nil {
 		err = errors.Warning("sql: delete by condition failed").WithCause(execErr)
 		return
 	}
+	invalidateCache[T](ctx)
 
 	affected = result.RowsAffected
 	return
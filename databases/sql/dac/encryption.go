@@ -0,0 +1,19 @@
+package dac
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+)
+
+type KeyProvider specifications.KeyProvider
+
+// RegisterKeyProvider
+// register key provider of encrypted columns (column:"MOBILE,enc"), it must be registered before encrypted columns are used.
+func RegisterKeyProvider(provider KeyProvider) {
+	specifications.RegisterKeyProvider(provider)
+}
+
+// StaticKeyProvider
+// keys are in memory, current is id of key to encrypt values, others are used to decrypt values which were encrypted before rotation.
+func StaticKeyProvider(current string, keys map[string][]byte, blindIndexKey []byte) KeyProvider {
+	return specifications.StaticKeyProvider(current, keys, blindIndexKey)
+}
@@ -9,6 +9,12 @@ import (
 )
 
 func Exist[T Table](ctx context.Context, cond conditions.Condition) (has bool, err error) {
+	release, shardingErr := useConditionSharding[T](ctx, cond, sql.ShardingMerge{})
+	if shardingErr != nil {
+		err = errors.Warning("sql: exist failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	_, query, arguments, buildErr := specifications.BuildExist[T](ctx, specifications.Condition{Condition: cond})
 	if buildErr != nil {
 		err = errors.Warning("sql: exist failed").WithCause(buildErr)
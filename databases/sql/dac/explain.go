@@ -0,0 +1,166 @@
+package dac
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/logs"
+	"strings"
+	"time"
+)
+
+var (
+	slowQueryContextKey = []byte("@fns:sql:dac:slow")
+)
+
+type ExplainOptions struct {
+	query  []QueryOption
+	offset int
+	length int
+}
+
+type ExplainOption func(options *ExplainOptions)
+
+// ExplainQuery
+// options of Query or Views which is explained.
+func ExplainQuery(query ...QueryOption) ExplainOption {
+	return func(options *ExplainOptions) {
+		options.query = append(options.query, query...)
+	}
+}
+
+// ExplainRange
+// offset and length of Query or Views which is explained.
+func ExplainRange(offset int, length int) ExplainOption {
+	return func(options *ExplainOptions) {
+		options.offset = offset
+		options.length = length
+	}
+}
+
+// ExplainPage
+// page of Page which is explained.
+func ExplainPage(no int, size int) ExplainOption {
+	return func(options *ExplainOptions) {
+		rng := specifications.PG(no, size).Range()
+		options.offset = rng.Offset
+		options.length = rng.Length
+	}
+}
+
+// Explain
+// explain statement which is rendered by Query, Page or Views, T is table or view.
+// note: dialect must support explain, such as postgres and mysql.
+func Explain[T any](ctx context.Context, options ...ExplainOption) (plan specifications.Plan, err error) {
+	opt := ExplainOptions{}
+	for _, option := range options {
+		option(&opt)
+	}
+	qo := QueryOptions{}
+	for _, option := range opt.query {
+		option(&qo)
+	}
+	spec, specErr := specifications.GetSpecification(ctx, specifications.Instance[T]())
+	if specErr != nil {
+		err = errors.Warning("sql: explain failed").WithCause(specErr)
+		return
+	}
+	var query []byte
+	var arguments []any
+	var buildErr error
+	if spec.View {
+		_, query, arguments, _, buildErr = specifications.BuildView[T](
+			ctx,
+			specifications.Condition{Condition: qo.cond},
+			specifications.Orders(qo.orders),
+			specifications.GroupBy{GroupBy: qo.groupBy},
+			opt.offset, opt.length,
+		)
+	} else {
+		_, query, arguments, _, buildErr = specifications.BuildLockingQuery[T](
+			ctx,
+			specifications.Condition{Condition: qo.cond},
+			specifications.Orders(qo.orders),
+			qo.lock,
+			opt.offset, opt.length,
+		)
+	}
+	if buildErr != nil {
+		err = errors.Warning("sql: explain failed").WithCause(buildErr)
+		return
+	}
+	plan, err = explain[T](ctx, query, arguments)
+	if err != nil {
+		err = errors.Warning("sql: explain failed").WithCause(err)
+		return
+	}
+	return
+}
+
+func explain[T any](ctx context.Context, query []byte, arguments []any) (plan specifications.Plan, err error) {
+	explainQuery, buildErr := specifications.BuildExplain[T](ctx, query)
+	if buildErr != nil {
+		err = buildErr
+		return
+	}
+	rows, queryErr := sql.Query(ctx, explainQuery, arguments...)
+	if queryErr != nil {
+		err = queryErr
+		return
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		err = fmt.Errorf("plan was not returned")
+		return
+	}
+	p := make([]byte, 0, 1)
+	scanErr := rows.Scan(&p)
+	if scanErr != nil {
+		err = scanErr
+		return
+	}
+	plan, err = specifications.ParsePlan(ctx, query, p)
+	return
+}
+
+// WithSlowQueryExplain
+// explain statements of Query and Views which cost more than threshold, then log plan as warning.
+// it is used to debug, do not use it in production, because slow statement is executed twice.
+func WithSlowQueryExplain(ctx context.Context, threshold time.Duration) {
+	if threshold <= 0 {
+		return
+	}
+	ctx.SetLocalValue(slowQueryContextKey, threshold)
+}
+
+func RemoveSlowQueryExplain(ctx context.Context) {
+	ctx.RemoveLocalValue(slowQueryContextKey)
+}
+
+// explainSlowQuery
+// failure of explain does not fail the query, it is only logged.
+func explainSlowQuery[T any](ctx context.Context, query []byte, arguments []any, beg time.Time) {
+	threshold, has := context.LocalValue[time.Duration](ctx, slowQueryContextKey)
+	if !has {
+		return
+	}
+	latency := time.Since(beg)
+	if latency < threshold {
+		return
+	}
+	log := logs.Load(ctx)
+	if !log.WarnEnabled() {
+		return
+	}
+	plan, explainErr := explain[T](ctx, query, arguments)
+	if explainErr != nil {
+		log.Warn().Cause(explainErr).With("sql", "explain").
+			Message(fmt.Sprintf("sql: slow query cost %s, but explain failed\n%s", latency, bytex.ToString(query)))
+		return
+	}
+	log.Warn().With("sql", "explain").
+		Message(fmt.Sprintf("sql: slow query cost %s\n%s\nplan: %s\nadvices: %s", latency, bytex.ToString(query), bytex.ToString(plan.Raw), strings.Join(plan.Advices, "; ")))
+}
@@ -0,0 +1,188 @@
+package dac
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/services"
+	"time"
+)
+
+type HistoryEntry[T Table] struct {
+	// Op
+	// UPDATE or DELETE
+	Op string `json:"op"`
+	// By
+	// id of authorization, empty when there is no authorization
+	By string `json:"by"`
+	// At
+	// time of change
+	At time.Time `json:"at"`
+	// Entry
+	// row before change
+	Entry T `json:"entry"`
+}
+
+// withHistory
+// write previous rows into history table and run fn in same transaction when table keeps history.
+// transaction of ctx is used when ctx is in transaction, and new transaction requires request in ctx,
+// otherwise previous rows are written before fn without transaction.
+func withHistory(ctx context.Context, query []byte, arguments []any, has bool, fn func() error) (err error) {
+	if !has {
+		err = fn()
+		return
+	}
+	_, hasRequest := services.TryLoadRequest(ctx)
+	if sql.InTransaction(ctx) || !hasRequest {
+		_, err = sql.Execute(ctx, query, arguments...)
+		if err != nil {
+			return
+		}
+		err = fn()
+		return
+	}
+	err = sql.Begin(ctx)
+	if err != nil {
+		return
+	}
+	_, err = sql.Execute(ctx, query, arguments...)
+	if err == nil {
+		err = fn()
+	}
+	if err != nil {
+		sql.Rollback(ctx)
+		return
+	}
+	err = sql.Commit(ctx)
+	return
+}
+
+func withEntryHistory[T Table](ctx context.Context, op string, entry T, fn func() error) (err error) {
+	query, arguments, has, buildErr := specifications.BuildHistorySnapshotByEntry[T](ctx, op, entry)
+	if buildErr != nil {
+		err = buildErr
+		return
+	}
+	err = withHistory(ctx, query, arguments, has, fn)
+	return
+}
+
+func withConditionHistory[T Table](ctx context.Context, op string, cond conditions.Condition, fn func() error) (err error) {
+	query, arguments, has, buildErr := specifications.BuildHistorySnapshot[T](ctx, op, specifications.Condition{Condition: cond})
+	if buildErr != nil {
+		err = buildErr
+		return
+	}
+	err = withHistory(ctx, query, arguments, has, fn)
+	return
+}
+
+// CreateHistoryTable
+// create {name}_HISTORY table of T, columns are copied from table by CREATE TABLE AS, so call it once, such as in migration.
+// note: constraints and indexes are not copied, and it is failed when history table exists.
+func CreateHistoryTable[T Table](ctx context.Context) (err error) {
+	queries, buildErr := specifications.BuildHistoryTable[T](ctx)
+	if buildErr != nil {
+		err = errors.Warning("sql: create history table failed").WithCause(buildErr)
+		return
+	}
+	for _, query := range queries {
+		_, err = sql.Execute(ctx, query)
+		if err != nil {
+			err = errors.Warning("sql: create history table failed").WithCause(err)
+			return
+		}
+	}
+	return
+}
+
+// History
+// returns changes of row which pk is id, ordered by time of change.
+func History[T Table](ctx context.Context, id any) (entries []HistoryEntry[T], err error) {
+	entries, err = queryHistory[T](ctx, id, time.Time{})
+	if err != nil {
+		err = errors.Warning("sql: query history failed").WithCause(err)
+		return
+	}
+	return
+}
+
+// AsOf
+// returns row which pk is id as of at.
+func AsOf[T Table](ctx context.Context, id any, at time.Time) (entry T, has bool, err error) {
+	if at.IsZero() {
+		err = errors.Warning("sql: query as of failed").WithCause(fmt.Errorf("at is required"))
+		return
+	}
+	spec, specErr := specifications.GetSpecification(ctx, specifications.Instance[T]())
+	if specErr != nil {
+		err = errors.Warning("sql: query as of failed").WithCause(specErr)
+		return
+	}
+	pk, hasPk := spec.Pk()
+	if !hasPk {
+		err = errors.Warning("sql: query as of failed").WithCause(fmt.Errorf("pk is required")).WithMeta("table", spec.Key)
+		return
+	}
+	// previous row of first change after at is the row at that time
+	changes, changesErr := queryHistory[T](ctx, id, at)
+	if changesErr != nil {
+		err = errors.Warning("sql: query as of failed").WithCause(changesErr)
+		return
+	}
+	if len(changes) > 0 {
+		entry = changes[0].Entry
+		has = true
+	} else {
+		entry, has, err = One[T](ctx, Conditions(conditions.New(conditions.Eq(pk.Field, id))), NoCache())
+		if err != nil {
+			err = errors.Warning("sql: query as of failed").WithCause(err)
+			return
+		}
+	}
+	if has {
+		// row was created after at
+		if _, act, hasAc := spec.AuditCreation(); hasAc && act != nil {
+			created, createdErr := spec.ArgumentByField(entry, act.Field)
+			if createdErr == nil {
+				if t, ok := created.(time.Time); ok && t.After(at) {
+					entry = specifications.Instance[T]()
+					has = false
+				}
+			}
+		}
+	}
+	return
+}
+
+func queryHistory[T Table](ctx context.Context, id any, after time.Time) (entries []HistoryEntry[T], err error) {
+	query, arguments, fields, buildErr := specifications.BuildHistory[T](ctx, id, after)
+	if buildErr != nil {
+		err = buildErr
+		return
+	}
+	rows, queryErr := sql.Query(ctx, query, arguments...)
+	if queryErr != nil {
+		err = queryErr
+		return
+	}
+	values, ops, bys, ats, scanErr := specifications.ScanHistoryRows[T](ctx, rows, fields)
+	_ = rows.Close()
+	if scanErr != nil {
+		err = scanErr
+		return
+	}
+	entries = make([]HistoryEntry[T], 0, len(values))
+	for i, value := range values {
+		entries = append(entries, HistoryEntry[T]{
+			Op:    ops[i],
+			By:    bys[i],
+			At:    ats[i],
+			Entry: value,
+		})
+	}
+	return
+}
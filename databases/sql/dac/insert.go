@@ -10,6 +10,12 @@ import (
 
 func Insert[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
 	entries := []T{entry}
+	release, shardingErr := useEntriesSharding[T](ctx, entries)
+	if shardingErr != nil {
+		err = errors.Warning("sql: insert failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	method, query, arguments, returning, buildErr := specifications.BuildInsert[T](ctx, entries)
 	if buildErr != nil {
 		err = errors.Warning("sql: insert failed").WithCause(buildErr)
@@ -21,6 +27,7 @@ func Insert[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
 			err = errors.Warning("sql: insert failed").WithCause(queryErr)
 			return
 		}
+		invalidateCache[T](ctx)
 		affected, wErr := specifications.WriteInsertReturning[T](ctx, rows, returning, entries)
 		_ = rows.Close()
 		if wErr != nil {
@@ -42,7 +49,8 @@ func Insert[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
 			err = errors.Warning("sql: insert failed").WithCause(execErr)
 			return
 		}
-		ok = result.RowsAffected > 0
+		invalidateCache[T](ctx)
+		ok = result.RowsAffected > 0 || specifications.IsAffectedRowsUnreported(ctx)
 		if ok {
 			verErr := specifications.TrySetupAuditVersion[T](ctx, entries)
 			if verErr != nil {
@@ -67,6 +75,12 @@ func InsertMulti[T Table](ctx context.Context, entries []T) (affected int64, err
 	if len(entries) == 0 {
 		return
 	}
+	release, shardingErr := useEntriesSharding[T](ctx, entries)
+	if shardingErr != nil {
+		err = errors.Warning("sql: insert multi failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	method, query, arguments, returning, buildErr := specifications.BuildInsert[T](ctx, entries)
 	if buildErr != nil {
 		err = errors.Warning("sql: insert multi failed").WithCause(buildErr)
@@ -78,6 +92,7 @@ func InsertMulti[T Table](ctx context.Context, entries []T) (affected int64, err
 			err = errors.Warning("sql: insert multi failed").WithCause(queryErr)
 			return
 		}
+		invalidateCache[T](ctx)
 		affected, err = specifications.WriteInsertReturning[T](ctx, rows, returning, entries)
 		_ = rows.Close()
 		if err != nil {
@@ -97,7 +112,11 @@ func InsertMulti[T Table](ctx context.Context, entries []T) (affected int64, err
 			err = errors.Warning("sql: insert multi failed").WithCause(execErr)
 			return
 		}
+		invalidateCache[T](ctx)
 		affected = result.RowsAffected
+		if affected == 0 && specifications.IsAffectedRowsUnreported(ctx) {
+			affected = int64(len(entries))
+		}
 		if affected > 0 {
 			verErr := specifications.TrySetupAuditVersion[T](ctx, entries)
 			if verErr != nil {
@@ -109,9 +128,90 @@ func InsertMulti[T Table](ctx context.Context, entries []T) (affected int64, err
 	return
 }
 
-func InsertOrUpdate[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
+type UpsertOptions struct {
+	conflicts  []string
+	constraint string
+	sets       []specifications.UpsertSet
+	cond       conditions.Condition
+}
+
+type UpsertOption func(options *UpsertOptions)
+
+// OnConflict
+// fields of conflict target, conflicts of table are used when it is not set.
+// it is not supported by mysql, cause any unique key triggers update.
+func OnConflict(fields ...string) UpsertOption {
+	return func(options *UpsertOptions) {
+		options.conflicts = append(options.conflicts, fields...)
+	}
+}
+
+// OnConstraint
+// name of conflict constraint, it is not supported by mysql.
+func OnConstraint(name string) UpsertOption {
+	return func(options *UpsertOptions) {
+		options.constraint = name
+	}
+}
+
+// DoUpdate
+// fields which are set to values of entry on conflict, all non-audit fields are set when no DoUpdate and DoIncr.
+func DoUpdate(fields ...string) UpsertOption {
+	return func(options *UpsertOptions) {
+		for _, field := range fields {
+			options.sets = append(options.sets, specifications.UpsertSet{Field: field, Mode: specifications.UpsertAssign})
+		}
+	}
+}
+
+// DoIncr
+// fields which are increased by values of entry on conflict, such as count = count + EXCLUDED.count.
+func DoIncr(fields ...string) UpsertOption {
+	return func(options *UpsertOptions) {
+		for _, field := range fields {
+			options.sets = append(options.sets, specifications.UpsertSet{Field: field, Mode: specifications.UpsertIncr})
+		}
+	}
+}
+
+// DoUpdateWhen
+// update only when cond is matched, fields in cond are of existing row.
+func DoUpdateWhen(cond conditions.Condition) UpsertOption {
+	return func(options *UpsertOptions) {
+		options.cond = cond
+	}
+}
+
+// InsertOrUpdate
+// conflicts of table are used and all non-audit fields are updated when no options,
+// otherwise dialect must support upsert, such as postgres and mysql.
+func InsertOrUpdate[T Table](ctx context.Context, entry T, options ...UpsertOption) (v T, ok bool, err error) {
 	entries := []T{entry}
-	method, query, arguments, returning, buildErr := specifications.BuildInsertOrUpdate[T](ctx, entries)
+	release, shardingErr := useEntriesSharding[T](ctx, entries)
+	if shardingErr != nil {
+		err = errors.Warning("sql: insert or update failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
+	var method specifications.Method
+	var query []byte
+	var arguments []any
+	var returning []string
+	var buildErr error
+	if len(options) == 0 {
+		method, query, arguments, returning, buildErr = specifications.BuildInsertOrUpdate[T](ctx, entries)
+	} else {
+		opt := UpsertOptions{}
+		for _, option := range options {
+			option(&opt)
+		}
+		method, query, arguments, returning, buildErr = specifications.BuildUpsert[T](ctx, entries, specifications.Upsert{
+			Conflicts:  opt.conflicts,
+			Constraint: opt.constraint,
+			Sets:       opt.sets,
+			Cond:       specifications.Condition{Condition: opt.cond},
+		})
+	}
 	if buildErr != nil {
 		err = errors.Warning("sql: insert or update failed").WithCause(buildErr)
 		return
@@ -122,6 +222,7 @@ func InsertOrUpdate[T Table](ctx context.Context, entry T) (v T, ok bool, err er
 			err = errors.Warning("sql: insert or update failed").WithCause(queryErr)
 			return
 		}
+		invalidateCache[T](ctx)
 
 		affected, wErr := specifications.WriteInsertReturning[T](ctx, rows, returning, entries)
 		_ = rows.Close()
@@ -144,6 +245,7 @@ func InsertOrUpdate[T Table](ctx context.Context, entry T) (v T, ok bool, err er
 			err = errors.Warning("sql: insert or update failed").WithCause(execErr)
 			return
 		}
+		invalidateCache[T](ctx)
 		ok = result.RowsAffected == 1
 		if ok {
 			verErr := specifications.TrySetupAuditVersion[T](ctx, entries)
@@ -167,6 +269,12 @@ func InsertOrUpdate[T Table](ctx context.Context, entry T) (v T, ok bool, err er
 
 func InsertWhenNotExist[T Table](ctx context.Context, entry T, source conditions.QueryExpr) (v T, ok bool, err error) {
 	entries := []T{entry}
+	release, shardingErr := useEntriesSharding[T](ctx, entries)
+	if shardingErr != nil {
+		err = errors.Warning("sql: insert when exist failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	method, query, arguments, returning, buildErr := specifications.BuildInsertWhenNotExist[T](ctx, entries, specifications.QueryExpr{QueryExpr: source})
 	if buildErr != nil {
 		err = errors.Warning("sql: insert when exist failed").WithCause(buildErr)
@@ -178,6 +286,7 @@ func InsertWhenNotExist[T Table](ctx context.Context, entry T, source conditions
 			err = errors.Warning("sql: insert when exist failed").WithCause(queryErr)
 			return
 		}
+		invalidateCache[T](ctx)
 		affected, wErr := specifications.WriteInsertReturning[T](ctx, rows, returning, entries)
 		_ = rows.Close()
 		if wErr != nil {
@@ -199,6 +308,7 @@ func InsertWhenNotExist[T Table](ctx context.Context, entry T, source conditions
 			err = errors.Warning("sql: insert when exist failed").WithCause(execErr)
 			return
 		}
+		invalidateCache[T](ctx)
 		ok = result.RowsAffected == 1
 		if ok {
 			verErr := specifications.TrySetupAuditVersion[T](ctx, entries)
@@ -222,6 +332,12 @@ func InsertWhenNotExist[T Table](ctx context.Context, entry T, source conditions
 
 func InsertWhenExist[T Table](ctx context.Context, entry T, source conditions.QueryExpr) (v T, ok bool, err error) {
 	entries := []T{entry}
+	release, shardingErr := useEntriesSharding[T](ctx, entries)
+	if shardingErr != nil {
+		err = errors.Warning("sql: insert when not exist failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	method, query, arguments, returning, buildErr := specifications.BuildInsertWhenExist[T](ctx, entries, specifications.QueryExpr{QueryExpr: source})
 	if buildErr != nil {
 		err = errors.Warning("sql: insert when not exist failed").WithCause(buildErr)
@@ -233,6 +349,7 @@ func InsertWhenExist[T Table](ctx context.Context, entry T, source conditions.Qu
 			err = errors.Warning("sql: insert when not exist failed").WithCause(queryErr)
 			return
 		}
+		invalidateCache[T](ctx)
 		affected, wErr := specifications.WriteInsertReturning[T](ctx, rows, returning, entries)
 		_ = rows.Close()
 		if wErr != nil {
@@ -254,6 +371,7 @@ func InsertWhenExist[T Table](ctx context.Context, entry T, source conditions.Qu
 			err = errors.Warning("sql: insert when not exist failed").WithCause(execErr)
 			return
 		}
+		invalidateCache[T](ctx)
 		ok = result.RowsAffected == 1
 		if ok {
 			verErr := specifications.TrySetupAuditVersion[T](ctx, entries)
@@ -0,0 +1,97 @@
+package dac
+
+import (
+	"github.com/aacfactory/avro"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/json"
+)
+
+type Masker specifications.Masker
+
+// RegisterMasker
+// register custom masker of mask columns (column:"MOBILE,mask:{name}"), it must be registered before tables are used.
+func RegisterMasker(name string, masker Masker) {
+	specifications.RegisterMasker(name, specifications.Masker(masker))
+}
+
+// Mask
+// returns masked copy of entries, fields in 'unmask' attribute of authorization are not masked.
+func Mask[T any](ctx context.Context, entries []T) (v []T, err error) {
+	if len(entries) == 0 {
+		v = entries
+		return
+	}
+	columns, columnsErr := specifications.LoadMasks(ctx, specifications.Instance[T]())
+	if columnsErr != nil {
+		err = errors.Warning("sql: mask failed").WithCause(columnsErr)
+		return
+	}
+	v, err = specifications.Mask[T](entries, columns)
+	return
+}
+
+// MaskEntries
+// returns entries which are masked when they are encoded, fields in 'unmask' attribute of authorization are not masked.
+func MaskEntries[T any](ctx context.Context, entries []T) (v MaskedEntries[T], err error) {
+	columns, columnsErr := specifications.LoadMasks(ctx, specifications.Instance[T]())
+	if columnsErr != nil {
+		err = errors.Warning("sql: mask entries failed").WithCause(columnsErr)
+		return
+	}
+	v = MaskedEntries[T]{
+		entries: entries,
+		columns: columns,
+	}
+	return
+}
+
+// MaskedEntries
+// entries are masked only when they are encoded by json or avro, so entries are never changed and can be updated.
+// decoded entries are the masked values.
+type MaskedEntries[T any] struct {
+	entries []T
+	columns []*specifications.Column
+}
+
+// Entries
+// entries which are not masked.
+func (masked MaskedEntries[T]) Entries() []T {
+	return masked.entries
+}
+
+func (masked MaskedEntries[T]) MarshalJSON() (p []byte, err error) {
+	entries, maskErr := specifications.Mask[T](masked.entries, masked.columns)
+	if maskErr != nil {
+		err = errors.Warning("sql: encode masked entries failed").WithCause(maskErr)
+		return
+	}
+	p, err = json.Marshal(entries)
+	return
+}
+
+func (masked *MaskedEntries[T]) UnmarshalJSON(p []byte) (err error) {
+	masked.columns = nil
+	err = json.Unmarshal(p, &masked.entries)
+	return
+}
+
+func (masked MaskedEntries[T]) MarshalAvro() (p []byte, err error) {
+	entries, maskErr := specifications.Mask[T](masked.entries, masked.columns)
+	if maskErr != nil {
+		err = errors.Warning("sql: encode masked entries failed").WithCause(maskErr)
+		return
+	}
+	p, err = avro.Marshal(entries)
+	return
+}
+
+func (masked *MaskedEntries[T]) UnmarshalAvro(p []byte) (err error) {
+	masked.columns = nil
+	if len(p) == 0 {
+		return
+	}
+	err = avro.Unmarshal(p, &masked.entries)
+	return
+}
@@ -1,8 +1,14 @@
 package orders
 
+import "github.com/aacfactory/fns-contrib/databases/sql"
+
 type Order struct {
-	Name string
-	Desc bool
+	Name    string
+	Desc    bool
+	Nearest sql.Geometry
+	// JsonPath
+	// dot separated keys of json column, such as `a.b.0`
+	JsonPath string
 }
 
 type Orders []Order
@@ -15,6 +21,24 @@ func (o Orders) Desc(name string) Orders {
 	return append(o, Order{Name: name, Desc: true})
 }
 
+// Nearest
+// k-nearest ordering by distance between column and geometry
+func (o Orders) Nearest(name string, geometry sql.Geometry) Orders {
+	return append(o, Order{Name: name, Desc: false, Nearest: geometry})
+}
+
+// AscJsonPath
+// order by value of path in json column
+func (o Orders) AscJsonPath(name string, path string) Orders {
+	return append(o, Order{Name: name, Desc: false, JsonPath: path})
+}
+
+// DescJsonPath
+// order by value of path in json column
+func (o Orders) DescJsonPath(name string, path string) Orders {
+	return append(o, Order{Name: name, Desc: true, JsonPath: path})
+}
+
 func Asc(name string) Orders {
 	return Orders{{
 		Name: name,
@@ -28,3 +52,33 @@ func Desc(name string) Orders {
 		Desc: true,
 	}}
 }
+
+// Nearest
+// k-nearest ordering by distance between column and geometry
+func Nearest(name string, geometry sql.Geometry) Orders {
+	return Orders{{
+		Name:    name,
+		Desc:    false,
+		Nearest: geometry,
+	}}
+}
+
+// AscJsonPath
+// order by value of path in json column
+func AscJsonPath(name string, path string) Orders {
+	return Orders{{
+		Name:     name,
+		Desc:     false,
+		JsonPath: path,
+	}}
+}
+
+// DescJsonPath
+// order by value of path in json column
+func DescJsonPath(name string, path string) Orders {
+	return Orders{{
+		Name:     name,
+		Desc:     true,
+		JsonPath: path,
+	}}
+}
@@ -62,3 +62,39 @@ func Page[T Table](ctx context.Context, no int, size int, options ...QueryOption
 	}
 	return
 }
+
+// Masked
+// returns pager whose entries are masked when it is encoded, so page is not changed, see MaskEntries.
+func (page Pager[T]) Masked(ctx context.Context) (v MaskedPager[T], err error) {
+	entries, maskErr := MaskEntries[T](ctx, page.Entries)
+	if maskErr != nil {
+		err = errors.Warning("sql: mask page failed").WithCause(maskErr)
+		return
+	}
+	v = MaskedPager[T]{
+		No:      page.No,
+		Pages:   page.Pages,
+		Total:   page.Total,
+		Entries: entries,
+	}
+	return
+}
+
+type MaskedPager[T Table] struct {
+	// No
+	// @title no
+	// @description no of page
+	No int `json:"no"`
+	// Pages
+	// @title pages
+	// @description total pages
+	Pages int64 `json:"pages"`
+	// Total
+	// @title total
+	// @description total entries
+	Total int64 `json:"total"`
+	// Entries
+	// @title entries
+	// @description entries of page, they are masked
+	Entries MaskedEntries[T] `json:"entries"`
+}
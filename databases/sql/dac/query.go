@@ -1,6 +1,7 @@
 package dac
 
 import (
+	"fmt"
 	"github.com/aacfactory/errors"
 	"github.com/aacfactory/fns-contrib/databases/sql"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
@@ -8,12 +9,15 @@ import (
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/orders"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
 	"github.com/aacfactory/fns/context"
+	"time"
 )
 
 type QueryOptions struct {
 	cond    conditions.Condition
 	orders  orders.Orders
 	groupBy groups.GroupBy
+	noCache bool
+	lock    specifications.Lock
 }
 
 type QueryOption func(options *QueryOptions)
@@ -36,6 +40,46 @@ func GroupBy(by groups.GroupBy) QueryOption {
 	}
 }
 
+// NoCache
+// skip result cache of table.
+func NoCache() QueryOption {
+	return func(options *QueryOptions) {
+		options.noCache = true
+	}
+}
+
+// ForUpdate
+// lock selected rows for update, it is valid only in transaction.
+func ForUpdate() QueryOption {
+	return func(options *QueryOptions) {
+		options.lock.Mode = specifications.ForUpdateLock
+	}
+}
+
+// ForShare
+// lock selected rows for share, it is valid only in transaction.
+func ForShare() QueryOption {
+	return func(options *QueryOptions) {
+		options.lock.Mode = specifications.ForShareLock
+	}
+}
+
+// SkipLocked
+// skip rows which are locked by others, lock mode is FOR UPDATE when it is not set.
+func SkipLocked() QueryOption {
+	return func(options *QueryOptions) {
+		options.lock.Wait = specifications.SkipLockedLock
+	}
+}
+
+// NoWait
+// fail at once when rows are locked by others, lock mode is FOR UPDATE when it is not set.
+func NoWait() QueryOption {
+	return func(options *QueryOptions) {
+		options.lock.Wait = specifications.NoWaitLock
+	}
+}
+
 func Asc(name string) orders.Orders {
 	return orders.Asc(name)
 }
@@ -44,35 +88,102 @@ func Desc(name string) orders.Orders {
 	return orders.Desc(name)
 }
 
+func AscJsonPath(name string, path string) orders.Orders {
+	return orders.AscJsonPath(name, path)
+}
+
+func DescJsonPath(name string, path string) orders.Orders {
+	return orders.DescJsonPath(name, path)
+}
+
+func Nearest(name string, geometry sql.Geometry) orders.Orders {
+	return orders.Nearest(name, geometry)
+}
+
 func Query[T Table](ctx context.Context, offset int, length int, options ...QueryOption) (entries []T, err error) {
 	opt := QueryOptions{}
 	for _, option := range options {
 		option(&opt)
 	}
+	if opt.lock.Wait != specifications.WaitLock && !opt.lock.Exist() {
+		opt.lock.Mode = specifications.ForUpdateLock
+	}
+	if opt.lock.Exist() && !sql.InTransaction(ctx) {
+		err = errors.Warning("sql: query failed").WithCause(fmt.Errorf("row locking requires transaction"))
+		return
+	}
 
-	_, query, arguments, fields, buildErr := specifications.BuildQuery[T](
+	shardOffset, shardLength, release, shardingErr := useQuerySharding[T](ctx, opt.cond, opt.orders, false, offset, length)
+	if shardingErr != nil {
+		err = errors.Warning("sql: query failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
+	if shardOffset != offset || shardLength != length {
+		// rows of shards are merged, so range of query is not the range of result
+		opt.noCache = true
+	}
+
+	_, query, arguments, fields, buildErr := specifications.BuildLockingQuery[T](
 		ctx,
 		specifications.Condition{Condition: opt.cond},
 		specifications.Orders(opt.orders),
-		offset, length,
+		opt.lock,
+		shardOffset, shardLength,
 	)
 	if buildErr != nil {
 		err = errors.Warning("sql: query failed").WithCause(buildErr)
 		return
 	}
 
+	var cacheSpec *specifications.Specification
+	var cacheKey []byte
+	if !opt.noCache && !opt.lock.Exist() {
+		spec, specErr := specifications.GetSpecification(ctx, specifications.Instance[T]())
+		if specErr != nil {
+			err = errors.Warning("sql: query failed").WithCause(specErr)
+			return
+		}
+		if cacheable(ctx, spec) {
+			key, keyErr := makeCacheKey(ctx, spec, query, arguments)
+			if keyErr == nil {
+				cached, hasCached, cachedErr := getCachedRows(ctx, key)
+				if cachedErr == nil && hasCached {
+					entries, err = specifications.ScanRows[T](ctx, cached, fields)
+					if err != nil {
+						err = errors.Warning("sql: query failed").WithCause(err)
+						return
+					}
+					return
+				}
+				cacheSpec = spec
+				cacheKey = key
+			}
+		}
+	}
+
+	beg := time.Now()
 	rows, queryErr := sql.Query(ctx, query, arguments...)
 	if queryErr != nil {
 		err = errors.Warning("sql: query failed").WithCause(queryErr)
 		return
 	}
 
+	if cacheSpec != nil {
+		rows, err = setCachedRows(ctx, cacheSpec, cacheKey, rows)
+		if err != nil {
+			err = errors.Warning("sql: query failed").WithCause(err)
+			return
+		}
+	}
+
 	entries, err = specifications.ScanRows[T](ctx, rows, fields)
 	_ = rows.Close()
 	if err != nil {
 		err = errors.Warning("sql: query failed").WithCause(err)
 		return
 	}
+	explainSlowQuery[T](ctx, query, arguments, beg)
 	return
 }
 
@@ -93,3 +204,20 @@ func ALL[T Table](ctx context.Context, options ...QueryOption) (entries []T, err
 	entries, err = Query[T](ctx, 0, 0, options...)
 	return
 }
+
+// ClaimBatch
+// lock at most size rows with FOR UPDATE SKIP LOCKED, so workers in concurrency get different rows.
+// it must be in transaction, and claimed rows are unlocked when transaction is finished.
+func ClaimBatch[T Table](ctx context.Context, size int, options ...QueryOption) (entries []T, err error) {
+	if size < 1 {
+		err = errors.Warning("sql: claim batch failed").WithCause(fmt.Errorf("size must be greater than 0"))
+		return
+	}
+	options = append(options, ForUpdate(), SkipLocked())
+	entries, err = Query[T](ctx, 0, size, options...)
+	if err != nil {
+		err = errors.Warning("sql: claim batch failed").WithCause(err)
+		return
+	}
+	return
+}
@@ -0,0 +1,168 @@
+package dac
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/orders"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/context"
+)
+
+func noopShardingRelease() {}
+
+// useSharding
+// route statements by sharding until release is called,
+// sharding of ctx which is set by sql.WithSharding has priority.
+func useSharding(ctx context.Context, sharding sql.Sharding) (release func()) {
+	if _, has := sql.LoadSharding(ctx); has {
+		release = noopShardingRelease
+		return
+	}
+	sql.WithSharding(ctx, sharding)
+	release = func() {
+		sql.RemoveSharding(ctx)
+	}
+	return
+}
+
+func shardingSpecification(ctx context.Context, e any) (spec *specifications.Specification, keySpec *specifications.Specification, err error) {
+	spec, err = specifications.GetSpecification(ctx, e)
+	if err != nil {
+		return
+	}
+	keySpec = spec
+	if spec.View && spec.ViewBase != nil {
+		keySpec = spec.ViewBase
+	}
+	if keySpec.ShardKey == "" {
+		keySpec = nil
+	}
+	return
+}
+
+// useEntriesSharding
+// entries must be in one shard.
+func useEntriesSharding[T Table](ctx context.Context, entries []T) (release func(), err error) {
+	release = noopShardingRelease
+	_, spec, specErr := shardingSpecification(ctx, specifications.Instance[T]())
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	if spec == nil {
+		return
+	}
+	values := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		values = append(values, entry)
+	}
+	keys, keysErr := spec.ShardKeysByEntries(values...)
+	if keysErr != nil {
+		err = keysErr
+		return
+	}
+	release = useSharding(ctx, sql.Sharding{
+		Keys:   keys,
+		Single: true,
+	})
+	return
+}
+
+// useConditionSharding
+// statement is executed on all shards when cond does not restrict shard key.
+func useConditionSharding[T any](ctx context.Context, cond conditions.Condition, merge sql.ShardingMerge) (release func(), err error) {
+	release = noopShardingRelease
+	_, spec, specErr := shardingSpecification(ctx, specifications.Instance[T]())
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	if spec == nil {
+		return
+	}
+	keys, _ := spec.ShardKeysByCondition(cond)
+	release = useSharding(ctx, sql.Sharding{
+		Keys:  keys,
+		Merge: merge,
+	})
+	return
+}
+
+// useQuerySharding
+// when query may be executed on more than one shard, then each shard returns first offset+length rows,
+// and rows of shards are merged by orders, offset and length.
+// grouped or aggregated rows can not be merged, so they must be in one shard.
+func useQuerySharding[T any](ctx context.Context, cond conditions.Condition, orderBy orders.Orders, grouped bool, offset int, length int) (shardOffset int, shardLength int, release func(), err error) {
+	shardOffset, shardLength = offset, length
+	release = noopShardingRelease
+	spec, keySpec, specErr := shardingSpecification(ctx, specifications.Instance[T]())
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	if keySpec == nil {
+		return
+	}
+	keys, _ := keySpec.ShardKeysByCondition(cond)
+	if len(keys) == 1 {
+		release = useSharding(ctx, sql.Sharding{
+			Keys: keys,
+		})
+		return
+	}
+	if grouped {
+		err = errors.Warning("sql: use sharding failed").WithCause(fmt.Errorf("grouped rows can not be merged across shards, use condition of shard key to route it into one shard"))
+		return
+	}
+	if field, has := shardingUnmergeableField(spec); has {
+		err = errors.Warning("sql: use sharding failed").WithCause(fmt.Errorf("aggregate and window columns can not be merged across shards, use condition of shard key to route it into one shard")).WithMeta("field", field)
+		return
+	}
+	merge := sql.ShardingMerge{
+		Offset: offset,
+		Length: length,
+	}
+	for _, order := range orderBy {
+		if !order.Nearest.IsEmpty() || order.JsonPath != "" {
+			err = errors.Warning("sql: use sharding failed").WithCause(fmt.Errorf("nearest and json path orders can not be merged across shards")).WithMeta("field", order.Name)
+			return
+		}
+		column, has := spec.ColumnByField(order.Name)
+		if !has {
+			err = errors.Warning("sql: use sharding failed").WithCause(fmt.Errorf("field of order was not found")).WithMeta("field", order.Name)
+			return
+		}
+		merge.Orders = append(merge.Orders, sql.ShardingOrder{
+			Column: column.Name,
+			Desc:   order.Desc,
+		})
+	}
+	shardOffset = 0
+	if length > 0 {
+		shardLength = offset + length
+	}
+	release = useSharding(ctx, sql.Sharding{
+		Keys:  keys,
+		Merge: merge,
+	})
+	return
+}
+
+// shardingUnmergeableField
+// returns field of aggregate or window column, values of them are computed in each shard.
+func shardingUnmergeableField(spec *specifications.Specification) (field string, has bool) {
+	for _, column := range spec.Columns {
+		kind, _, ok := column.Virtual()
+		if !ok {
+			continue
+		}
+		if kind == specifications.AggregateVirtualQuery || kind == specifications.WindowVirtualQuery {
+			field = column.Field
+			has = true
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,76 @@
+package dac
+
+import (
+	stdctx "context"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/orders"
+	"github.com/aacfactory/fns/context"
+	"testing"
+)
+
+type shardingOrder struct {
+	Id       string `column:"ID,pk"`
+	TenantId string `column:"TENANT_ID"`
+	Kind     string `column:"KIND"`
+	Amount   int64  `column:"AMOUNT"`
+}
+
+func (row shardingOrder) TableInfo() TableInfo {
+	return Info("ORDER", Schema("FNS"), ShardKey("TenantId"))
+}
+
+type shardingOrderStat struct {
+	Kind  string `column:"KIND"`
+	Count int64  `column:"ID,vc,agg,COUNT"`
+}
+
+func (row shardingOrderStat) ViewInfo() ViewInfo {
+	return TableView(shardingOrder{})
+}
+
+type shardingOrderKind struct {
+	Kind string `column:"KIND"`
+}
+
+func (row shardingOrderKind) ViewInfo() ViewInfo {
+	return TableView(shardingOrder{})
+}
+
+func TestUseQuerySharding(t *testing.T) {
+	ctx := context.Acquire(stdctx.TODO())
+	// merged across shards
+	offset, length, release, err := useQuerySharding[shardingOrder](ctx, In("TenantId", "a", "b"), orders.Desc("Amount"), false, 10, 10)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if offset != 0 || length != 20 {
+		t.Errorf("range of shard must be first offset+length rows, got %d %d", offset, length)
+	}
+	sharding, has := sql.LoadSharding(ctx)
+	if !has || len(sharding.Keys) != 2 || len(sharding.Merge.Orders) != 1 || sharding.Merge.Orders[0].Column != "AMOUNT" {
+		t.Errorf("sharding is not matched, %+v", sharding)
+	}
+	release()
+	if _, has = sql.LoadSharding(ctx); has {
+		t.Errorf("sharding must be removed after release")
+	}
+	// grouped across shards
+	_, _, _, err = useQuerySharding[shardingOrderKind](ctx, conditions.Condition{}, nil, true, 0, 0)
+	if err == nil {
+		t.Errorf("grouped view across shards must be failed")
+	}
+	// aggregated across shards
+	_, _, _, err = useQuerySharding[shardingOrderStat](ctx, In("TenantId", "a", "b"), nil, false, 0, 0)
+	if err == nil {
+		t.Errorf("aggregated view across shards must be failed")
+	}
+	// grouped and aggregated in one shard
+	_, _, release, err = useQuerySharding[shardingOrderStat](ctx, Eq("TenantId", "a"), nil, true, 0, 0)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	release()
+}
@@ -6,6 +6,8 @@ import (
 	"reflect"
 )
 
+// Arguments
+// returns values of fields to write, values of encrypted and blind index columns are encrypted.
 func (spec *Specification) Arguments(instance any, fieldNames []string) (arguments []any, err error) {
 	rv := reflect.Indirect(reflect.ValueOf(instance))
 	for _, fieldName := range fieldNames {
@@ -23,6 +25,15 @@ func (spec *Specification) Arguments(instance any, fieldNames []string) (argumen
 		switch target.Kind {
 		case Normal, Pk, Acb, Act, Amb, Amt, Adb, Adt, Aol:
 			fv := target.ReadValue(rv)
+			if target.Encrypted || target.IndexOf != nil {
+				argument, argumentErr := encryptedArgument(target, fv.Interface())
+				if argumentErr != nil {
+					err = errors.Warning("sql: encrypt field value failed").WithCause(argumentErr).WithMeta("table", rv.Type().String()).WithMeta("field", target.Field)
+					return
+				}
+				arguments = append(arguments, argument)
+				break
+			}
 			arguments = append(arguments, fv.Interface())
 			break
 		case Reference:
@@ -63,6 +74,10 @@ func (spec *Specification) Arguments(instance any, fieldNames []string) (argumen
 			}
 			arguments = append(arguments, json.RawMessage(argument))
 			break
+		case Geo:
+			fv := target.ReadValue(rv)
+			arguments = append(arguments, fv.Convert(geometryType).Interface())
+			break
 		default:
 			err = errors.Warning("sql: field can not as argument").WithMeta("table", rv.Type().String()).WithMeta("field", target.Field)
 			return
@@ -71,6 +86,8 @@ func (spec *Specification) Arguments(instance any, fieldNames []string) (argumen
 	return
 }
 
+// ArgumentByField
+// returns plain value of field, values of encrypted columns are encrypted only by Arguments which writes values.
 func (spec *Specification) ArgumentByField(instance any, field string) (argument any, err error) {
 	rv := reflect.Indirect(reflect.ValueOf(instance))
 	var target *Column
@@ -118,6 +135,10 @@ func (spec *Specification) ArgumentByField(instance any, field string) (argument
 		}
 		argument = json.RawMessage(encode)
 		break
+	case Geo:
+		fv := target.ReadValue(rv)
+		argument = fv.Convert(geometryType).Interface()
+		break
 	default:
 		err = errors.Warning("sql: field can not as argument").WithMeta("table", rv.Type().String()).WithMeta("field", target.Field)
 		return
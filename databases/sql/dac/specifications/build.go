@@ -1,6 +1,7 @@
 package specifications
 
 import (
+	"bytes"
 	stdsql "database/sql"
 	"fmt"
 	"github.com/aacfactory/errors"
@@ -47,6 +48,7 @@ func BuildInsert[T any](ctx context.Context, entries []T) (method Method, query
 		}
 		arguments = append(arguments, args...)
 	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -83,6 +85,10 @@ func BuildInsertOrUpdate[T any](ctx context.Context, entries []T) (method Method
 		return
 	}
 	arguments, err = spec.Arguments(entries[0], fields)
+	if err != nil {
+		return
+	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -119,6 +125,7 @@ func BuildInsertWhenExist[T any](ctx context.Context, entries []T, src QueryExpr
 		return
 	}
 	arguments = append(arguments, srcArguments...)
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -155,6 +162,7 @@ func BuildInsertWhenNotExist[T any](ctx context.Context, entries []T, src QueryE
 		return
 	}
 	arguments = append(arguments, srcArguments...)
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -186,6 +194,10 @@ func BuildUpdate[T any](ctx context.Context, entries []T) (method Method, query
 		return
 	}
 	arguments, err = spec.Arguments(entries[0], fields)
+	if err != nil {
+		return
+	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -316,6 +328,28 @@ func BuildUpdateFields[T any](ctx context.Context, fields []FieldValue, cond Con
 			field.Value = p
 			fields[i] = field
 			break
+		case StringType:
+			if column.Encrypted {
+				encrypted, encryptErr := encryptedArgument(column, field.Value)
+				if encryptErr != nil {
+					err = errors.Warning(fmt.Sprintf("sql: encrypt %s field value failed", field.Name)).WithCause(encryptErr).WithMeta("table", spec.Key)
+					return
+				}
+				if column.BlindIndex != nil {
+					index, indexErr := encryptedArgument(column.BlindIndex, field.Value)
+					if indexErr != nil {
+						err = errors.Warning(fmt.Sprintf("sql: encrypt %s field value failed", field.Name)).WithCause(indexErr).WithMeta("table", spec.Key)
+						return
+					}
+					fields = append(fields, FieldValue{
+						Name:  column.BlindIndex.Field,
+						Value: index,
+					})
+				}
+				field.Value = encrypted
+				fields[i] = field
+			}
+			break
 		case MappingType:
 			if column.Kind != Reference {
 				err = errors.Warning(fmt.Sprintf("sql: kind %s field value type can not be updated", field.Name)).WithMeta("table", spec.Key)
@@ -341,6 +375,7 @@ func BuildUpdateFields[T any](ctx context.Context, fields []FieldValue, cond Con
 	if err != nil {
 		return
 	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -372,6 +407,10 @@ func BuildDelete[T any](ctx context.Context, entries []T) (method Method, query
 		return
 	}
 	arguments, err = spec.Arguments(entries[0], fields)
+	if err != nil {
+		return
+	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -447,6 +486,7 @@ func BuildDeleteAnyByCondition(ctx context.Context, entry any, cond Condition) (
 		}
 		arguments = append(auditArgs, arguments...)
 	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -474,6 +514,7 @@ func BuildCount[T any](ctx context.Context, cond Condition) (method Method, quer
 	if err != nil {
 		return
 	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -493,6 +534,7 @@ func BuildExist[T any](ctx context.Context, cond Condition) (method Method, quer
 	if err != nil {
 		return
 	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
 
@@ -515,6 +557,36 @@ func BuildQuery[T any](ctx context.Context, cond Condition, orders Orders, offse
 	if length > 0 {
 		arguments = append(arguments, offset, length)
 	}
+	err = encodeGeometryArguments(dialect, arguments)
+	return
+}
+
+// BuildLockingQuery
+// build query with row locking clause which is appended at the end of select, so dialect must be LockDialect.
+func BuildLockingQuery[T any](ctx context.Context, cond Condition, orders Orders, lock Lock, offset int, length int) (method Method, query []byte, arguments []any, columns []string, err error) {
+	method, query, arguments, columns, err = BuildQuery[T](ctx, cond, orders, offset, length)
+	if err != nil || !lock.Exist() {
+		return
+	}
+	dialect, dialectErr := LoadDialect(ctx)
+	if dialectErr != nil {
+		err = dialectErr
+		return
+	}
+	lockDialect, ok := dialect.(LockDialect)
+	if !ok {
+		err = errors.Warning("sql: build locking query failed").WithCause(fmt.Errorf("%s dialect does not support row locking", dialect.Name()))
+		return
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(query)+24))
+	_, _ = buf.Write(query)
+	_, _ = buf.Write(SPACE)
+	err = lockDialect.RenderLock(Todo(ctx, Instance[T](), dialect), buf, lock)
+	if err != nil {
+		err = errors.Warning("sql: build locking query failed").WithCause(err).WithMeta("dialect", dialect.Name())
+		return
+	}
+	query = buf.Bytes()
 	return
 }
 
@@ -537,5 +609,56 @@ func BuildView[T any](ctx context.Context, cond Condition, orders Orders, groupB
 	if length > 0 {
 		arguments = append(arguments, offset, length)
 	}
+	err = encodeGeometryArguments(dialect, arguments)
+	return
+}
+
+func BuildSubtree[T any](ctx context.Context, root any, depth int, cond Condition, orders Orders) (method Method, query []byte, arguments []any, columns []string, err error) {
+	dialect, dialectErr := LoadDialect(ctx)
+	if dialectErr != nil {
+		err = dialectErr
+		return
+	}
+	treeDialect, ok := dialect.(TreeDialect)
+	if !ok {
+		err = errors.Warning("sql: build subtree failed").WithCause(fmt.Errorf("%s dialect does not support tree query", dialect.Name()))
+		return
+	}
+	t := Instance[T]()
+	spec, specErr := GetSpecification(ctx, t)
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	method, query, arguments, columns, err = treeDialect.Subtree(Todo(ctx, t, dialect), spec, root, depth, cond, orders)
+	if err != nil {
+		return
+	}
+	err = encodeGeometryArguments(dialect, arguments)
+	return
+}
+
+func BuildAncestors[T any](ctx context.Context, id any, cond Condition) (method Method, query []byte, arguments []any, columns []string, err error) {
+	dialect, dialectErr := LoadDialect(ctx)
+	if dialectErr != nil {
+		err = dialectErr
+		return
+	}
+	treeDialect, ok := dialect.(TreeDialect)
+	if !ok {
+		err = errors.Warning("sql: build ancestors failed").WithCause(fmt.Errorf("%s dialect does not support tree query", dialect.Name()))
+		return
+	}
+	t := Instance[T]()
+	spec, specErr := GetSpecification(ctx, t)
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	method, query, arguments, columns, err = treeDialect.Ancestors(Todo(ctx, t, dialect), spec, id, cond)
+	if err != nil {
+		return
+	}
+	err = encodeGeometryArguments(dialect, arguments)
 	return
 }
@@ -30,6 +30,8 @@ const (
 	referenceColumn = "ref"
 	linkColumn      = "link"
 	linksColumn     = "links"
+	geometryColumn  = "geometry"
+	geographyColumn = "geography"
 )
 
 const (
@@ -38,6 +40,7 @@ const (
 	ObjectVirtualQuery
 	ArrayVirtualQuery
 	AggregateVirtualQuery
+	WindowVirtualQuery
 )
 
 type VirtualQueryKind int
@@ -53,10 +56,11 @@ const (
 	Adt                         // column,adt
 	Aol                         // column,aol
 	Json                        // column,json
-	Virtual                     // ident,vc,basic|object|array|aggregate,query|agg_func
+	Virtual                     // ident,vc,basic|object|array|aggregate|window,query|agg_func|window_func
 	Reference                   // column,ref,target_field
 	Link                        // ident,link,field+target_field
 	Links                       // column,links,field+target_field,orders:field@desc+field,length:10
+	Geo                         // column,geometry|geography
 )
 
 type ColumnKind int
@@ -91,6 +95,8 @@ func (kind ColumnKind) String() string {
 		return "link"
 	case Links:
 		return "links"
+	case Geo:
+		return "geo"
 	}
 	return "???"
 }
@@ -109,6 +115,7 @@ const (
 	JsonType
 	ScanType
 	MappingType
+	GeometryType
 )
 
 type ColumnTypeName int
@@ -148,6 +155,8 @@ func (ct *ColumnType) String() string {
 		return "scan"
 	case MappingType:
 		return fmt.Sprintf("mapping(%s, %s)", ct.Mapping.Key, fmt.Sprintf("%+v", ct.Options))
+	case GeometryType:
+		return fmt.Sprintf("geometry(%s)", ct.Options[0])
 	}
 	return "???"
 }
@@ -162,6 +171,18 @@ type Column struct {
 	Kind        ColumnKind
 	Type        ColumnType
 	ValueWriter ValueWriter
+	// Encrypted
+	// value is encrypted by registered key provider, 'column:"{name},enc{,blind index column}"'
+	Encrypted bool
+	// BlindIndex
+	// companion column of encrypted column, it stores hmac of value, so equality conditions can be used.
+	BlindIndex *Column
+	// IndexOf
+	// encrypted column of blind index column
+	IndexOf *Column
+	// Mask
+	// masker name of column, value is masked in responses unless authorization unmasks it, 'column:"{name},mask:{masker}"'
+	Mask string
 }
 
 func (column *Column) Incr() bool {
@@ -184,6 +205,9 @@ func (column *Column) Virtual() (kind VirtualQueryKind, query string, ok bool) {
 		case "agg", "aggregate":
 			kind = AggregateVirtualQuery
 			break
+		case "window":
+			kind = WindowVirtualQuery
+			break
 		default:
 			kind = UnknownVirtualQueryKind
 			break
@@ -253,6 +277,13 @@ func (column *Column) Links() (hostField string, awayField string, mapping *Spec
 	return
 }
 
+func (column *Column) Geography() (ok bool) {
+	if column.Kind == Geo {
+		ok = column.Type.Options[0] == geographyColumn
+	}
+	return
+}
+
 func (column *Column) Valid() bool {
 	if column.Type.Name == UnknownType {
 		return false
@@ -280,6 +311,9 @@ func (column *Column) Valid() bool {
 			(column.Type.Value.Elem().Kind() == reflect.Struct ||
 				(column.Type.Value.Elem().Kind() == reflect.Ptr && column.Type.Value.Elem().Elem().Kind() == reflect.Struct))
 		break
+	case Geo:
+		ok = column.Type.Value.ConvertibleTo(geometryType)
+		break
 	default:
 		if column.Incr() {
 			ok = column.Type.Name == IntType
@@ -335,6 +369,8 @@ func newColumn(ctx context.Context, rt reflect.StructField, idx []int) (column *
 	}
 	var vw ValueWriter
 	kind := Normal
+	encrypted := false
+	blindIndex := ""
 	typ := ColumnType{
 		Name:    UnknownType,
 		Value:   rt.Type,
@@ -342,6 +378,12 @@ func newColumn(ctx context.Context, rt reflect.StructField, idx []int) (column *
 		Options: make([]string, 0, 1),
 	}
 	items := strings.Split(tag, ",")
+	mask := ""
+	if len(items) > 1 {
+		var options []string
+		options, mask = takeMaskOption(items[1:])
+		items = append(items[0:1], options...)
+	}
 
 	name := strings.TrimSpace(items[0])
 	if len(items) > 1 {
@@ -485,12 +527,17 @@ func newColumn(ctx context.Context, rt reflect.StructField, idx []int) (column *
 			}
 			kind = Virtual
 			vck := strings.ToLower(strings.TrimSpace(items[1]))
-			valid := vck == "basic" || vck == "object" || vck == "array" || vck == "agg" || vck == "aggregate"
+			valid := vck == "basic" || vck == "object" || vck == "array" || vck == "agg" || vck == "aggregate" || vck == "window"
 			if !valid {
 				err = errors.Warning("sql: scan virtual column failed, kind is invalid").WithMeta("field", rt.Name)
 				return
 			}
-			typ.Options = append(typ.Options, vck, strings.TrimSpace(items[2]))
+			if vck == "window" {
+				typ.Options = append(typ.Options, vck)
+				typ.Options = append(typ.Options, windowOptions(items[2:])...)
+			} else {
+				typ.Options = append(typ.Options, vck, strings.TrimSpace(items[2]))
+			}
 			if vck == "object" || vck == "array" {
 				typ.Name = JsonType
 				vw = &JsonValue{
@@ -622,6 +669,24 @@ func newColumn(ctx context.Context, rt reflect.StructField, idx []int) (column *
 				return
 			}
 			break
+		case encColumn:
+			if rt.Type.Kind() != reflect.String {
+				err = errors.Warning("sql: type of enc column failed must be string").WithMeta("field", rt.Name)
+				return
+			}
+			typ.Name = StringType
+			vw = &StringValue{}
+			encrypted = true
+			if len(items) > 1 {
+				blindIndex = strings.TrimSpace(items[1])
+			}
+			break
+		case geometryColumn, geographyColumn:
+			kind = Geo
+			typ.Name = GeometryType
+			typ.Options = append(typ.Options, kv)
+			vw = &ScanValue{}
+			break
 		default:
 			err = errors.Warning("sql: unknown column options").WithMeta("field", rt.Name).WithMeta("tag", tag)
 			return
@@ -659,11 +724,26 @@ func newColumn(ctx context.Context, rt reflect.StructField, idx []int) (column *
 		Kind:        kind,
 		Type:        typ,
 		ValueWriter: vw,
+		Encrypted:   encrypted,
+		Mask:        mask,
+	}
+	if blindIndex != "" {
+		column.BlindIndex = newBlindIndexColumn(column, blindIndex)
 	}
 
 	if !column.Valid() {
 		err = errors.Warning("sql: new column failed").WithMeta("field", rt.Name).WithCause(fmt.Errorf("%v is not supported", typ.Value))
 		return
 	}
+	if mask != "" && mask != MaskHidden {
+		if rt.Type.Kind() != reflect.String {
+			err = errors.Warning("sql: new column failed").WithMeta("field", rt.Name).WithCause(fmt.Errorf("%s mask supports string only", mask))
+			return
+		}
+		if _, has := getMasker(mask); !has {
+			err = errors.Warning("sql: new column failed").WithMeta("field", rt.Name).WithCause(fmt.Errorf("%s masker was not found", mask))
+			return
+		}
+	}
 	return
 }
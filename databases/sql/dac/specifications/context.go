@@ -42,11 +42,23 @@ func SwitchKey(ctx Context, key any) Context {
 	}
 }
 
+// Qualify
+// column names of fields are qualified by qualifier, such as "table"."column", it is used when names are ambiguous.
+func Qualify(ctx Context, qualifier string) Context {
+	rc := ctx.(*renderCtx)
+	return &renderCtx{
+		Context:   ctx,
+		key:       rc.key,
+		qualifier: qualifier,
+	}
+}
+
 type renderCtx struct {
 	context.Context
-	dialect Dialect
-	ph      QueryPlaceholder
-	key     any
+	dialect   Dialect
+	ph        QueryPlaceholder
+	key       any
+	qualifier string
 }
 
 func (ctx *renderCtx) getDialect() Dialect {
@@ -99,6 +111,13 @@ func (ctx *renderCtx) Localization(key any) (content []string, has bool) {
 		for i, c := range content {
 			content[i] = ctx.getDialect().FormatIdent(c)
 		}
+		if ok && ctx.qualifier != "" {
+			qualified := make([]string, len(content))
+			for i, c := range content {
+				qualified[i] = ctx.qualifier + "." + c
+			}
+			content = qualified
+		}
 	}
 	return
 }
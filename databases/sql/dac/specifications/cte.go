@@ -0,0 +1,108 @@
+package specifications
+
+import (
+	"context"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/groups"
+	"io"
+	"strings"
+)
+
+// CTESource
+// named common table expression of view info, source is view value or query.
+type CTESource interface {
+	Name() string
+	Source() any
+	Condition() conditions.Condition
+	GroupBy() groups.GroupBy
+}
+
+// CTE
+// when Spec is nil, then Query is used.
+type CTE struct {
+	Name    string
+	Query   string
+	Spec    *Specification
+	Cond    Condition
+	GroupBy GroupBy
+}
+
+func newCTEs(ctx context.Context, sources []CTESource) (ctes []CTE, err error) {
+	for _, source := range sources {
+		name := strings.TrimSpace(source.Name())
+		if name == "" {
+			err = errors.Warning("sql: new cte failed").WithCause(fmt.Errorf("name is required"))
+			return
+		}
+		cte := CTE{
+			Name:    name,
+			Cond:    Condition{Condition: source.Condition()},
+			GroupBy: GroupBy{GroupBy: source.GroupBy()},
+		}
+		switch src := source.Source().(type) {
+		case string:
+			cte.Query = strings.TrimSpace(src)
+			if cte.Query == "" {
+				err = errors.Warning("sql: new cte failed").WithCause(fmt.Errorf("query is required")).WithMeta("cte", name)
+				return
+			}
+			break
+		default:
+			if src == nil || !MaybeView(src) {
+				err = errors.Warning("sql: new cte failed").WithCause(fmt.Errorf("source must be view or query")).WithMeta("cte", name)
+				return
+			}
+			spec, specErr := GetSpecification(ctx, src)
+			if specErr != nil {
+				err = errors.Warning("sql: new cte failed").WithCause(specErr).WithMeta("cte", name)
+				return
+			}
+			cte.Spec = spec
+			break
+		}
+		ctes = append(ctes, cte)
+	}
+	return
+}
+
+// RenderWith
+// WITH {name} AS ({view or query}), ... , it is rendered before select of view, so arguments of it are first.
+func RenderWith(ctx Context, w io.Writer, spec *Specification) (arguments []any, err error) {
+	if len(spec.CTEs) == 0 {
+		return
+	}
+	rc, ok := ctx.(*renderCtx)
+	if !ok {
+		err = errors.Warning("sql: render with failed").WithCause(fmt.Errorf("invalid context")).WithMeta("view", spec.Key)
+		return
+	}
+	dialect := rc.getDialect()
+	_, _ = w.Write(WITH)
+	_, _ = w.Write(SPACE)
+	for i, cte := range spec.CTEs {
+		if i > 0 {
+			_, _ = w.Write(COMMA)
+		}
+		_, _ = w.Write([]byte(ctx.FormatIdent(cte.Name)))
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(AS)
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(LB)
+		if cte.Spec == nil {
+			_, _ = w.Write([]byte(cte.Query))
+		} else {
+			_, query, args, _, viewErr := dialect.View(SwitchKey(ctx, cte.Spec.Instance()), cte.Spec, cte.Cond, nil, cte.GroupBy, 0, 0)
+			if viewErr != nil {
+				err = errors.Warning("sql: render with failed").WithCause(viewErr).WithMeta("view", spec.Key).WithMeta("cte", cte.Name)
+				return
+			}
+			_, _ = w.Write(query)
+			arguments = append(arguments, args...)
+		}
+		_, _ = w.Write(RB)
+	}
+	_, _ = w.Write(SPACE)
+	return
+}
@@ -81,6 +81,27 @@ type Dialect interface {
 	View(ctx Context, spec *Specification, cond Condition, orders Orders, groupBy GroupBy, offset int, length int) (method Method, query []byte, arguments []any, fields []string, err error)
 }
 
+// AffectedRowsUnreported
+// dialect whose driver does not report affected rows, such as clickhouse,
+// so execution without error is treated as all rows were affected.
+type AffectedRowsUnreported interface {
+	AffectedRowsUnreported() bool
+}
+
+// IsAffectedRowsUnreported
+// check whether dialect of ctx does not report affected rows.
+func IsAffectedRowsUnreported(ctx context.Context) bool {
+	dialect, err := LoadDialect(ctx)
+	if err != nil {
+		return false
+	}
+	unreported, ok := dialect.(AffectedRowsUnreported)
+	if !ok {
+		return false
+	}
+	return unreported.AffectedRowsUnreported()
+}
+
 var (
 	dialects = make([]Dialect, 0, 1)
 )
@@ -92,9 +113,17 @@ func RegisterDialect(dialect Dialect) {
 	name := dialect.Name()
 	if _, has := getDialect(name); has {
 		panic(fmt.Errorf("%+v", errors.Warning(fmt.Sprintf("sql: %s dialect has registered", name))))
-		return
 	}
 	dialects = append(dialects, dialect)
+	sql.RegisterQueryPlaceholder(name, func() sql.QueryPlaceholder {
+		return dialect.QueryPlaceholder()
+	})
+	if timeout, ok := dialect.(sql.StatementTimeout); ok {
+		sql.RegisterStatementTimeout(name, timeout)
+	}
+	if readOnly, ok := dialect.(sql.ReadOnlyTransaction); ok {
+		sql.RegisterReadOnlyTransaction(name, readOnly)
+	}
 }
 
 func getDialect(name string) (dialect Dialect, has bool) {
@@ -0,0 +1,353 @@
+package specifications
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	encColumn = "enc"
+	// BlindIndexFieldSuffix
+	// field of blind index column is {field of encrypted column}#BlindIndex
+	BlindIndexFieldSuffix = "#BlindIndex"
+)
+
+var (
+	ErrKeyProviderNotRegistered = fmt.Errorf("key provider was not registered")
+)
+
+// KeyProvider
+// keys of column encryption, keys are identified by id, so they can be rotated.
+type KeyProvider interface {
+	// Current
+	// key to encrypt values, it must be 16, 24 or 32 bytes.
+	Current() (id string, key []byte, err error)
+	// Get
+	// key to decrypt values which were encrypted by key of id.
+	Get(id string) (key []byte, err error)
+	// BlindIndexKey
+	// key of hmac of blind index, it can not be rotated, otherwise blind indexes must be rebuilt.
+	BlindIndexKey() (key []byte, err error)
+}
+
+var (
+	keyProviderLocker             = sync.RWMutex{}
+	keyProvider       KeyProvider = nil
+	encryptedColumns              = sync.Map{}
+	// encryptionCiphers
+	// key is sha256 of key material, so cipher is not reused when key of id is changed
+	encryptionCiphers = sync.Map{}
+)
+
+// RegisterKeyProvider
+// register key provider of column encryption, it must be registered before encrypted columns are used.
+func RegisterKeyProvider(provider KeyProvider) {
+	keyProviderLocker.Lock()
+	keyProvider = provider
+	keyProviderLocker.Unlock()
+}
+
+func loadKeyProvider() (provider KeyProvider, err error) {
+	keyProviderLocker.RLock()
+	provider = keyProvider
+	keyProviderLocker.RUnlock()
+	if provider == nil {
+		err = ErrKeyProviderNotRegistered
+		return
+	}
+	return
+}
+
+// StaticKeyProvider
+// keys are in memory, current is id of key to encrypt values.
+func StaticKeyProvider(current string, keys map[string][]byte, blindIndexKey []byte) KeyProvider {
+	return &staticKeyProvider{
+		current:       current,
+		keys:          keys,
+		blindIndexKey: blindIndexKey,
+	}
+}
+
+type staticKeyProvider struct {
+	current       string
+	keys          map[string][]byte
+	blindIndexKey []byte
+}
+
+func (provider *staticKeyProvider) Current() (id string, key []byte, err error) {
+	id = provider.current
+	key, err = provider.Get(id)
+	return
+}
+
+func (provider *staticKeyProvider) Get(id string) (key []byte, err error) {
+	has := false
+	key, has = provider.keys[id]
+	if !has {
+		err = fmt.Errorf("key of %s was not found", id)
+		return
+	}
+	return
+}
+
+func (provider *staticKeyProvider) BlindIndexKey() (key []byte, err error) {
+	if len(provider.blindIndexKey) == 0 {
+		err = fmt.Errorf("blind index key is required")
+		return
+	}
+	key = provider.blindIndexKey
+	return
+}
+
+func encryptionCipher(key []byte) (aead cipher.AEAD, err error) {
+	cacheKey := sha256.Sum256(key)
+	cached, has := encryptionCiphers.Load(cacheKey)
+	if has {
+		aead = cached.(cipher.AEAD)
+		return
+	}
+	block, blockErr := aes.NewCipher(key)
+	if blockErr != nil {
+		err = blockErr
+		return
+	}
+	aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return
+	}
+	encryptionCiphers.Store(cacheKey, aead)
+	return
+}
+
+// Encrypt
+// encrypt value by current key, result is {key id}:{base64 of nonce and sealed}, empty value is not encrypted.
+func Encrypt(value string) (encrypted string, err error) {
+	if value == "" {
+		return
+	}
+	provider, providerErr := loadKeyProvider()
+	if providerErr != nil {
+		err = errors.Warning("sql: encrypt failed").WithCause(providerErr)
+		return
+	}
+	id, key, keyErr := provider.Current()
+	if keyErr != nil {
+		err = errors.Warning("sql: encrypt failed").WithCause(keyErr)
+		return
+	}
+	aead, aeadErr := encryptionCipher(key)
+	if aeadErr != nil {
+		err = errors.Warning("sql: encrypt failed").WithCause(aeadErr).WithMeta("key", id)
+		return
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, readErr := io.ReadFull(rand.Reader, nonce); readErr != nil {
+		err = errors.Warning("sql: encrypt failed").WithCause(readErr).WithMeta("key", id)
+		return
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	encrypted = id + ":" + base64.RawStdEncoding.EncodeToString(sealed)
+	return
+}
+
+// Decrypt
+// decrypt value by key of id in value, empty value is not decrypted.
+func Decrypt(encrypted string) (value string, err error) {
+	if encrypted == "" {
+		return
+	}
+	provider, providerErr := loadKeyProvider()
+	if providerErr != nil {
+		err = errors.Warning("sql: decrypt failed").WithCause(providerErr)
+		return
+	}
+	idx := strings.LastIndexByte(encrypted, ':')
+	if idx < 1 {
+		err = errors.Warning("sql: decrypt failed").WithCause(fmt.Errorf("value is not encrypted"))
+		return
+	}
+	id := encrypted[:idx]
+	sealed, decodeErr := base64.RawStdEncoding.DecodeString(encrypted[idx+1:])
+	if decodeErr != nil {
+		err = errors.Warning("sql: decrypt failed").WithCause(decodeErr).WithMeta("key", id)
+		return
+	}
+	key, keyErr := provider.Get(id)
+	if keyErr != nil {
+		err = errors.Warning("sql: decrypt failed").WithCause(keyErr).WithMeta("key", id)
+		return
+	}
+	aead, aeadErr := encryptionCipher(key)
+	if aeadErr != nil {
+		err = errors.Warning("sql: decrypt failed").WithCause(aeadErr).WithMeta("key", id)
+		return
+	}
+	if len(sealed) < aead.NonceSize() {
+		err = errors.Warning("sql: decrypt failed").WithCause(fmt.Errorf("value is too short")).WithMeta("key", id)
+		return
+	}
+	p, openErr := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if openErr != nil {
+		err = errors.Warning("sql: decrypt failed").WithCause(openErr).WithMeta("key", id)
+		return
+	}
+	value = string(p)
+	return
+}
+
+// BlindIndex
+// hex of hmac sha256 of value, empty value is not indexed.
+func BlindIndex(value string) (index string, err error) {
+	if value == "" {
+		return
+	}
+	provider, providerErr := loadKeyProvider()
+	if providerErr != nil {
+		err = errors.Warning("sql: blind index failed").WithCause(providerErr)
+		return
+	}
+	key, keyErr := provider.BlindIndexKey()
+	if keyErr != nil {
+		err = errors.Warning("sql: blind index failed").WithCause(keyErr)
+		return
+	}
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(value))
+	index = hex.EncodeToString(h.Sum(nil))
+	return
+}
+
+// newBlindIndexColumn
+// companion column of encrypted column, it shares field of encrypted column.
+func newBlindIndexColumn(column *Column, name string) *Column {
+	idx := make([]int, len(column.FieldIdx))
+	copy(idx, column.FieldIdx)
+	return &Column{
+		FieldIdx:  idx,
+		Field:     column.Field + BlindIndexFieldSuffix,
+		Name:      name,
+		JsonIdent: "",
+		Kind:      Normal,
+		Type: ColumnType{
+			Name:    StringType,
+			Value:   stringType,
+			Options: make([]string, 0, 1),
+		},
+		ValueWriter: &StringValue{},
+		IndexOf:     column,
+	}
+}
+
+// encryptedArgument
+// encrypt value of encrypted column, or make blind index for blind index column.
+func encryptedArgument(column *Column, value any) (argument any, err error) {
+	s, ok := value.(string)
+	if !ok {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.String {
+			err = errors.Warning("sql: value of encrypted column must be string").WithMeta("column", column.Name)
+			return
+		}
+		s = rv.String()
+	}
+	if column.IndexOf != nil {
+		argument, err = BlindIndex(s)
+		return
+	}
+	argument, err = Encrypt(s)
+	return
+}
+
+func decryptGenericValue(value any) (v any, err error) {
+	switch encrypted := value.(type) {
+	case string:
+		v, err = Decrypt(encrypted)
+		break
+	case []byte:
+		v, err = Decrypt(string(encrypted))
+		break
+	default:
+		err = fmt.Errorf("value of encrypted column must be string")
+		break
+	}
+	return
+}
+
+func encryptedColumnKey(rt reflect.Type, field string) string {
+	return fmt.Sprintf("%s.%s:%s", rt.PkgPath(), rt.Name(), field)
+}
+
+// encryptedColumnOfContext
+// get encrypted column by field of table which is key of render context.
+func encryptedColumnOfContext(ctx Context, field string) (column *Column, has bool) {
+	rc, ok := ctx.(*renderCtx)
+	if !ok || rc.key == nil {
+		return
+	}
+	rt := reflect.Indirect(reflect.ValueOf(rc.key)).Type()
+	if rt.Kind() != reflect.Struct {
+		return
+	}
+	v, exist := encryptedColumns.Load(encryptedColumnKey(rt, field))
+	if !exist {
+		return
+	}
+	column, has = v.(*Column)
+	return
+}
+
+// blindIndexPredicate
+// convert predicate of encrypted field to predicate of blind index column, only equality operators are supported.
+func blindIndexPredicate(ctx Context, p Predicate, column *Column) (name string, v Predicate, err error) {
+	if column.BlindIndex == nil {
+		err = errors.Warning("sql: encrypted field can not be used in condition without blind index").WithMeta("field", p.Field)
+		return
+	}
+	switch p.Operator {
+	case conditions.Equal, conditions.NotEqual, conditions.IN, conditions.NOTIN:
+		break
+	default:
+		err = errors.Warning("sql: only equality operators are supported by encrypted field").WithMeta("field", p.Field).WithMeta("operator", p.Operator.String())
+		return
+	}
+	v = p
+	switch expr := p.Expression.(type) {
+	case conditions.Literal, conditions.QueryExpr:
+		err = errors.Warning("sql: only values are supported by encrypted field").WithMeta("field", p.Field)
+		return
+	case []any:
+		values := make([]any, 0, len(expr))
+		for _, e := range expr {
+			index, indexErr := encryptedArgument(column.BlindIndex, e)
+			if indexErr != nil {
+				err = indexErr
+				return
+			}
+			values = append(values, index)
+		}
+		v.Expression = values
+		break
+	default:
+		index, indexErr := encryptedArgument(column.BlindIndex, expr)
+		if indexErr != nil {
+			err = indexErr
+			return
+		}
+		v.Expression = index
+		break
+	}
+	name = ctx.FormatIdent(column.BlindIndex.Name)
+	return
+}
@@ -0,0 +1,86 @@
+package specifications
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEncryption(t *testing.T) {
+	RegisterKeyProvider(StaticKeyProvider("k1", map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+	}, []byte("blind")))
+	encrypted, err := Encrypt("13800000000")
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if !strings.HasPrefix(encrypted, "k1:") {
+		t.Errorf("encrypted value must have key id, got %s", encrypted)
+		return
+	}
+	value, decryptErr := Decrypt(encrypted)
+	if decryptErr != nil {
+		t.Errorf("%+v", decryptErr)
+		return
+	}
+	if value != "13800000000" {
+		t.Errorf("decrypted value is not matched, got %s", value)
+		return
+	}
+	// same id but different key material, cipher of previous key must not be reused
+	RegisterKeyProvider(StaticKeyProvider("k1", map[string][]byte{
+		"k1": bytes.Repeat([]byte{2}, 32),
+	}, []byte("blind")))
+	if _, decryptErr = Decrypt(encrypted); decryptErr == nil {
+		t.Errorf("value which was encrypted by previous key must not be decrypted by new key")
+		return
+	}
+	reEncrypted, reErr := Encrypt("13800000000")
+	if reErr != nil {
+		t.Errorf("%+v", reErr)
+		return
+	}
+	if value, decryptErr = Decrypt(reEncrypted); decryptErr != nil || value != "13800000000" {
+		t.Errorf("decrypt by new key failed, %v", decryptErr)
+		return
+	}
+}
+
+func TestEncryption_Concurrent(t *testing.T) {
+	provider := StaticKeyProvider("k1", map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 16),
+	}, []byte("blind"))
+	RegisterKeyProvider(provider)
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterKeyProvider(provider)
+		}()
+		go func() {
+			defer wg.Done()
+			encrypted, err := Encrypt("foo")
+			if err != nil {
+				t.Errorf("%+v", err)
+				return
+			}
+			if _, err = Decrypt(encrypted); err != nil {
+				t.Errorf("%+v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEncryption_NotRegistered(t *testing.T) {
+	RegisterKeyProvider(nil)
+	if _, err := Encrypt("foo"); err == nil {
+		t.Errorf("encrypt without key provider must be failed")
+	}
+	if _, err := BlindIndex("foo"); err == nil {
+		t.Errorf("blind index without key provider must be failed")
+	}
+}
@@ -0,0 +1,144 @@
+package specifications
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/json"
+	"io"
+)
+
+var (
+	EXPLAIN = []byte("EXPLAIN")
+)
+
+// PlanNode
+// node of query plan.
+type PlanNode struct {
+	// Type
+	// node type of postgres, such as Seq Scan, or operation and access type of mysql, such as ALL, ref
+	Type string `json:"type"`
+	// Relation
+	// table of node
+	Relation string `json:"relation"`
+	// Index
+	// used index of node
+	Index string `json:"index"`
+	// Filter
+	// condition which is applied to rows of node
+	Filter string `json:"filter"`
+	// Rows
+	// estimated rows
+	Rows float64 `json:"rows"`
+	// Cost
+	// estimated cost
+	Cost float64 `json:"cost"`
+	// SequentialScan
+	// full table scan
+	SequentialScan bool       `json:"sequentialScan"`
+	Children       []PlanNode `json:"children"`
+}
+
+// Plan
+// parsed query plan.
+type Plan struct {
+	Query string          `json:"query"`
+	Root  PlanNode        `json:"root"`
+	Raw   json.RawMessage `json:"raw"`
+	// SequentialScans
+	// nodes which scan whole table
+	SequentialScans []PlanNode `json:"sequentialScans"`
+	// Advices
+	// index advices of sequential scans
+	Advices []string `json:"advices"`
+}
+
+func (plan Plan) HasSequentialScan() bool {
+	return len(plan.SequentialScans) > 0
+}
+
+// ExplainDialect
+// dialect which supports query plan in json format.
+type ExplainDialect interface {
+	// RenderExplain
+	// render explain clause which is before query, such as EXPLAIN (FORMAT JSON).
+	RenderExplain(ctx Context, w io.Writer) (err error)
+	// ParsePlan
+	// parse json of explain into plan node.
+	ParsePlan(p []byte) (root PlanNode, err error)
+}
+
+func loadExplainDialect(ctx context.Context) (dialect Dialect, explain ExplainDialect, err error) {
+	dialect, err = LoadDialect(ctx)
+	if err != nil {
+		return
+	}
+	ok := false
+	explain, ok = dialect.(ExplainDialect)
+	if !ok {
+		err = fmt.Errorf("%s dialect does not support explain", dialect.Name())
+		return
+	}
+	return
+}
+
+// BuildExplain
+// build explain of query, arguments of query are arguments of explain.
+func BuildExplain[T any](ctx context.Context, query []byte) (explain []byte, err error) {
+	dialect, explainDialect, dialectErr := loadExplainDialect(ctx)
+	if dialectErr != nil {
+		err = errors.Warning("sql: build explain failed").WithCause(dialectErr)
+		return
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(query)+24))
+	err = explainDialect.RenderExplain(Todo(ctx, Instance[T](), dialect), buf)
+	if err != nil {
+		err = errors.Warning("sql: build explain failed").WithCause(err)
+		return
+	}
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(query)
+	explain = buf.Bytes()
+	return
+}
+
+// ParsePlan
+// parse json of explain, then flag sequential scans.
+func ParsePlan(ctx context.Context, query []byte, p []byte) (plan Plan, err error) {
+	_, explainDialect, dialectErr := loadExplainDialect(ctx)
+	if dialectErr != nil {
+		err = errors.Warning("sql: parse plan failed").WithCause(dialectErr)
+		return
+	}
+	root, parseErr := explainDialect.ParsePlan(p)
+	if parseErr != nil {
+		err = errors.Warning("sql: parse plan failed").WithCause(parseErr)
+		return
+	}
+	plan = Plan{
+		Query:           string(query),
+		Root:            root,
+		Raw:             p,
+		SequentialScans: make([]PlanNode, 0, 1),
+		Advices:         make([]string, 0, 1),
+	}
+	plan.collect(root)
+	return
+}
+
+func (plan *Plan) collect(node PlanNode) {
+	if node.SequentialScan {
+		scan := node
+		scan.Children = nil
+		plan.SequentialScans = append(plan.SequentialScans, scan)
+		if scan.Filter != "" {
+			plan.Advices = append(plan.Advices, fmt.Sprintf("sequential scan on %s filters %s, consider an index on the filtered columns", scan.Relation, scan.Filter))
+		} else {
+			plan.Advices = append(plan.Advices, fmt.Sprintf("sequential scan on %s without filter, consider a condition or an order by indexed columns", scan.Relation))
+		}
+	}
+	for _, child := range node.Children {
+		plan.collect(child)
+	}
+}
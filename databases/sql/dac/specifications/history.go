@@ -0,0 +1,420 @@
+package specifications
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/services/authorizations"
+	"github.com/valyala/bytebufferpool"
+	"time"
+)
+
+const (
+	HistoryTableSuffix = "_HISTORY"
+	HistoryOpColumn    = "HISTORY_OP"
+	HistoryByColumn    = "HISTORY_BY"
+	HistoryAtColumn    = "HISTORY_AT"
+)
+
+const (
+	HistoryUpdateOp = "UPDATE"
+	HistoryDeleteOp = "DELETE"
+)
+
+var (
+	ASC   = []byte("ASC")
+	GT    = []byte(">")
+	NULL  = []byte("NULL")
+	CURAT = []byte("CURRENT_TIMESTAMP")
+)
+
+// HistoryColumns
+// columns which are stored in table, virtual, reference, link and links columns are not kept in history.
+func (spec *Specification) HistoryColumns() (columns []*Column) {
+	for _, column := range spec.Columns {
+		switch column.Kind {
+		case Normal, Pk, Acb, Act, Amb, Amt, Adb, Adt, Aol, Json, Geo:
+			columns = append(columns, column)
+			break
+		default:
+			break
+		}
+	}
+	return
+}
+
+func (spec *Specification) historyTableName(ctx Context) string {
+	name := ctx.FormatIdent(spec.Name + HistoryTableSuffix)
+	if spec.Schema != "" {
+		name = ctx.FormatIdent(spec.Schema) + "." + name
+	}
+	return name
+}
+
+func (spec *Specification) tableName(ctx Context) string {
+	name := ctx.FormatIdent(spec.Name)
+	if spec.Schema != "" {
+		name = ctx.FormatIdent(spec.Schema) + "." + name
+	}
+	return name
+}
+
+// BuildHistorySnapshot
+// INSERT INTO {table}_HISTORY ({columns}, HISTORY_OP, HISTORY_BY, HISTORY_AT) SELECT {columns}, {op}, {by}, CURRENT_TIMESTAMP FROM {table} WHERE {cond}
+// has is false when table does not keep history.
+func BuildHistorySnapshot[T any](ctx context.Context, op string, cond Condition) (query []byte, arguments []any, has bool, err error) {
+	dialect, dialectErr := LoadDialect(ctx)
+	if dialectErr != nil {
+		err = dialectErr
+		return
+	}
+	instance := Instance[T]()
+	spec, specErr := GetSpecification(ctx, instance)
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	if !spec.History || spec.View {
+		return
+	}
+	has = true
+	rc := Todo(ctx, instance, dialect)
+
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	columns := spec.HistoryColumns()
+	names := bytebufferpool.Get()
+	defer bytebufferpool.Put(names)
+	for i, column := range columns {
+		if i > 0 {
+			_, _ = names.Write(COMMA)
+		}
+		_, _ = names.WriteString(rc.FormatIdent(column.Name))
+	}
+
+	_, _ = buf.Write(INSERT)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(INTO)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.WriteString(spec.historyTableName(rc))
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(LB)
+	_, _ = buf.Write(names.Bytes())
+	_, _ = buf.Write(COMMA)
+	_, _ = buf.WriteString(rc.FormatIdent(HistoryOpColumn))
+	_, _ = buf.Write(COMMA)
+	_, _ = buf.WriteString(rc.FormatIdent(HistoryByColumn))
+	_, _ = buf.Write(COMMA)
+	_, _ = buf.WriteString(rc.FormatIdent(HistoryAtColumn))
+	_, _ = buf.Write(RB)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(SELECT)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(names.Bytes())
+	_, _ = buf.Write(COMMA)
+	_, _ = buf.WriteString(rc.NextQueryPlaceholder())
+	arguments = append(arguments, op)
+	_, _ = buf.Write(COMMA)
+	auth, hasAuth, _ := authorizations.Load(ctx)
+	if hasAuth && auth.Exist() {
+		_, _ = buf.WriteString(rc.NextQueryPlaceholder())
+		arguments = append(arguments, auth.Id.String())
+	} else {
+		_, _ = buf.Write(NULL)
+	}
+	_, _ = buf.Write(COMMA)
+	_, _ = buf.Write(CURAT)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(FROM)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.WriteString(spec.tableName(rc))
+	if cond.Exist() {
+		_, _ = buf.Write(SPACE)
+		_, _ = buf.Write(WHERE)
+		_, _ = buf.Write(SPACE)
+		condArguments, condErr := cond.Render(rc, buf)
+		if condErr != nil {
+			err = errors.Warning("sql: build history snapshot failed").WithCause(condErr).WithMeta("table", spec.Key)
+			return
+		}
+		arguments = append(arguments, condArguments...)
+	}
+	query = []byte(buf.String())
+	err = encodeGeometryArguments(dialect, arguments)
+	return
+}
+
+// BuildHistoryTable
+// CREATE TABLE {table}_HISTORY AS SELECT {columns} FROM {table} WHERE 1 = 0
+// ALTER TABLE {table}_HISTORY ADD COLUMN HISTORY_OP VARCHAR(8)
+// ALTER TABLE {table}_HISTORY ADD COLUMN HISTORY_BY VARCHAR(63)
+// ALTER TABLE {table}_HISTORY ADD COLUMN HISTORY_AT TIMESTAMP
+// columns are added one by one, cause sqlite does not support more than one in an ALTER TABLE.
+func BuildHistoryTable[T any](ctx context.Context) (queries [][]byte, err error) {
+	dialect, dialectErr := LoadDialect(ctx)
+	if dialectErr != nil {
+		err = dialectErr
+		return
+	}
+	instance := Instance[T]()
+	spec, specErr := GetSpecification(ctx, instance)
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	if !spec.History || spec.View {
+		err = errors.Warning("sql: build history table failed").WithCause(fmt.Errorf("table does not keep history")).WithMeta("table", spec.Key)
+		return
+	}
+	rc := Todo(ctx, instance, dialect)
+	historyTableName := spec.historyTableName(rc)
+
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+	_, _ = buf.WriteString("CREATE TABLE ")
+	_, _ = buf.WriteString(historyTableName)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(AS)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(SELECT)
+	_, _ = buf.Write(SPACE)
+	for i, column := range spec.HistoryColumns() {
+		if i > 0 {
+			_, _ = buf.Write(COMMA)
+		}
+		_, _ = buf.WriteString(rc.FormatIdent(column.Name))
+	}
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(FROM)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.WriteString(spec.tableName(rc))
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(WHERE)
+	_, _ = buf.WriteString(" 1 = 0")
+	queries = append(queries, []byte(buf.String()))
+
+	historyColumns := [][2]string{
+		{HistoryOpColumn, "VARCHAR(8)"},
+		{HistoryByColumn, "VARCHAR(63)"},
+		{HistoryAtColumn, "TIMESTAMP"},
+	}
+	for _, column := range historyColumns {
+		queries = append(queries, []byte(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", historyTableName, rc.FormatIdent(column[0]), column[1])))
+	}
+	return
+}
+
+// BuildHistorySnapshotByEntry
+// snapshot row of entry by pk and version.
+func BuildHistorySnapshotByEntry[T any](ctx context.Context, op string, entry T) (query []byte, arguments []any, has bool, err error) {
+	spec, specErr := GetSpecification(ctx, entry)
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	if !spec.History || spec.View {
+		return
+	}
+	cond, condErr := spec.entryCondition(entry)
+	if condErr != nil {
+		err = errors.Warning("sql: build history snapshot failed").WithCause(condErr).WithMeta("table", spec.Key)
+		return
+	}
+	query, arguments, has, err = BuildHistorySnapshot[T](ctx, op, Condition{Condition: cond})
+	return
+}
+
+func (spec *Specification) entryCondition(entry any) (cond conditions.Condition, err error) {
+	pk, hasPk := spec.Pk()
+	if !hasPk {
+		err = fmt.Errorf("pk is required")
+		return
+	}
+	id, idErr := spec.ArgumentByField(entry, pk.Field)
+	if idErr != nil {
+		err = idErr
+		return
+	}
+	cond = conditions.New(conditions.Eq(pk.Field, id))
+	if aol, hasAol := spec.AuditVersion(); hasAol {
+		version, versionErr := spec.ArgumentByField(entry, aol.Field)
+		if versionErr != nil {
+			err = versionErr
+			return
+		}
+		cond = cond.And(conditions.Eq(aol.Field, version))
+	}
+	return
+}
+
+// BuildHistory
+// SELECT {columns}, HISTORY_OP, HISTORY_BY, HISTORY_AT FROM {table}_HISTORY WHERE {pk} = ? [AND HISTORY_AT > ?] ORDER BY HISTORY_AT [, {aol}] [LIMIT 1]
+// when after is not zero, then returns the first history after it.
+func BuildHistory[T any](ctx context.Context, id any, after time.Time) (query []byte, arguments []any, fields []string, err error) {
+	dialect, dialectErr := LoadDialect(ctx)
+	if dialectErr != nil {
+		err = dialectErr
+		return
+	}
+	instance := Instance[T]()
+	spec, specErr := GetSpecification(ctx, instance)
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	if !spec.History || spec.View {
+		err = errors.Warning("sql: build history query failed").WithCause(fmt.Errorf("table does not keep history")).WithMeta("table", spec.Key)
+		return
+	}
+	pk, hasPk := spec.Pk()
+	if !hasPk {
+		err = errors.Warning("sql: build history query failed").WithCause(fmt.Errorf("pk is required")).WithMeta("table", spec.Key)
+		return
+	}
+	rc := Todo(ctx, instance, dialect)
+
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	_, _ = buf.Write(SELECT)
+	_, _ = buf.Write(SPACE)
+	for i, column := range spec.HistoryColumns() {
+		if i > 0 {
+			_, _ = buf.Write(COMMA)
+		}
+		_, _ = buf.WriteString(rc.FormatIdent(column.Name))
+		fields = append(fields, column.Field)
+	}
+	_, _ = buf.Write(COMMA)
+	_, _ = buf.WriteString(rc.FormatIdent(HistoryOpColumn))
+	_, _ = buf.Write(COMMA)
+	_, _ = buf.WriteString(rc.FormatIdent(HistoryByColumn))
+	_, _ = buf.Write(COMMA)
+	_, _ = buf.WriteString(rc.FormatIdent(HistoryAtColumn))
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(FROM)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.WriteString(spec.historyTableName(rc))
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(WHERE)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.WriteString(rc.FormatIdent(pk.Name))
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(EQ)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.WriteString(rc.NextQueryPlaceholder())
+	arguments = append(arguments, id)
+	if !after.IsZero() {
+		_, _ = buf.Write(SPACE)
+		_, _ = buf.Write(AND)
+		_, _ = buf.Write(SPACE)
+		_, _ = buf.WriteString(rc.FormatIdent(HistoryAtColumn))
+		_, _ = buf.Write(SPACE)
+		_, _ = buf.Write(GT)
+		_, _ = buf.Write(SPACE)
+		_, _ = buf.WriteString(rc.NextQueryPlaceholder())
+		arguments = append(arguments, after)
+	}
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(ORDER)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(BY)
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.WriteString(rc.FormatIdent(HistoryAtColumn))
+	_, _ = buf.Write(SPACE)
+	_, _ = buf.Write(ASC)
+	if aol, hasAol := spec.AuditVersion(); hasAol {
+		// rows in one transaction may have same history at
+		_, _ = buf.Write(COMMA)
+		_, _ = buf.WriteString(rc.FormatIdent(aol.Name))
+		_, _ = buf.Write(SPACE)
+		_, _ = buf.Write(ASC)
+	}
+	if !after.IsZero() {
+		_, _ = buf.Write(SPACE)
+		_, _ = buf.Write(LIMIT)
+		_, _ = buf.Write(SPACE)
+		_, _ = buf.WriteString("1")
+	}
+	query = bytex.FromString(buf.String())
+	return
+}
+
+// ScanHistoryRows
+// columns of rows are fields of entry and HISTORY_OP, HISTORY_BY, HISTORY_AT.
+func ScanHistoryRows[T any](ctx context.Context, rows sql.Rows, fields []string) (entries []T, ops []string, bys []string, ats []time.Time, err error) {
+	spec, specErr := GetSpecification(ctx, Instance[T]())
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	n := len(fields)
+	for rows.Next() {
+		generics := acquireGenerics(n + 3)
+		scanErr := rows.Scan(generics...)
+		if scanErr != nil {
+			releaseGenerics(generics)
+			err = scanErr
+			return
+		}
+		entry := Instance[T]()
+		writeErr := generics[:n].WriteTo(spec, fields, &entry)
+		if writeErr != nil {
+			releaseGenerics(generics)
+			err = writeErr
+			return
+		}
+		op := generics[n].(*Generic)
+		by := generics[n+1].(*Generic)
+		at := generics[n+2].(*Generic)
+		entries = append(entries, entry)
+		ops = append(ops, historyString(op))
+		bys = append(bys, historyString(by))
+		ats = append(ats, historyTime(at))
+		releaseGenerics(generics)
+	}
+	return
+}
+
+func historyString(g *Generic) string {
+	if !g.Valid {
+		return ""
+	}
+	switch v := g.Value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func historyTime(g *Generic) time.Time {
+	if !g.Valid {
+		return time.Time{}
+	}
+	switch v := g.Value.(type) {
+	case time.Time:
+		return v
+	case string:
+		return parseHistoryTime(v)
+	case []byte:
+		return parseHistoryTime(string(v))
+	default:
+		return time.Time{}
+	}
+}
+
+func parseHistoryTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,86 @@
+package specifications
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/json"
+	"io"
+	"strings"
+)
+
+// JsonDialect
+// dialect which supports json predicates and ordering by path of json column.
+type JsonDialect interface {
+	RenderJsonPredicate(ctx Context, w io.Writer, column string, operator conditions.Operator, expr conditions.Json) (arguments []any, err error)
+	RenderJsonPathOrder(ctx Context, w io.Writer, column string, path string) (arguments []any, err error)
+}
+
+func loadJsonDialect(ctx Context) (dialect JsonDialect, err error) {
+	rc, ok := ctx.(*renderCtx)
+	if !ok {
+		err = errors.Warning("sql: load json dialect failed").WithCause(fmt.Errorf("invalid context"))
+		return
+	}
+	dialect, ok = rc.getDialect().(JsonDialect)
+	if !ok {
+		err = errors.Warning("sql: load json dialect failed").WithCause(fmt.Errorf("%s dialect does not support json", rc.getDialect().Name()))
+		return
+	}
+	return
+}
+
+// JsonPathKeys
+// split dot separated path into keys, empty keys are ignored.
+func JsonPathKeys(path string) (keys []string) {
+	items := strings.Split(path, ".")
+	keys = make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		keys = append(keys, item)
+	}
+	return
+}
+
+// IsJsonPathIndex
+// key is index of array when it is made of digits
+func IsJsonPathIndex(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, c := range key {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeJsonValue
+// encode value of json expression into json text, json.RawMessage and []byte are used directly.
+func EncodeJsonValue(value any) (v string, err error) {
+	switch x := value.(type) {
+	case json.RawMessage:
+		v = string(x)
+		break
+	case []byte:
+		v = string(x)
+		break
+	default:
+		p, encodeErr := json.Marshal(value)
+		if encodeErr != nil {
+			err = errors.Warning("sql: encode json value failed").WithCause(encodeErr)
+			return
+		}
+		v = string(p)
+		break
+	}
+	if !json.ValidateString(v) {
+		err = errors.Warning("sql: encode json value failed").WithCause(fmt.Errorf("invalid json"))
+		return
+	}
+	return
+}
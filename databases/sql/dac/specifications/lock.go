@@ -0,0 +1,95 @@
+package specifications
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"io"
+)
+
+var (
+	FOR    = []byte("FOR")
+	SHARE  = []byte("SHARE")
+	NOWAIT = []byte("NOWAIT")
+	SKIP   = []byte("SKIP")
+	LOCKED = []byte("LOCKED")
+)
+
+type LockMode int
+
+const (
+	NoLock LockMode = iota
+	ForUpdateLock
+	ForShareLock
+)
+
+type LockWait int
+
+const (
+	WaitLock LockWait = iota
+	NoWaitLock
+	SkipLockedLock
+)
+
+// Lock
+// row locking clause of select, such as FOR UPDATE SKIP LOCKED.
+type Lock struct {
+	Mode LockMode
+	Wait LockWait
+}
+
+func (lock Lock) Exist() bool {
+	return lock.Mode != NoLock
+}
+
+// LockDialect
+// dialect which supports row locking clause of select.
+type LockDialect interface {
+	RenderLock(ctx Context, w io.Writer, lock Lock) (err error)
+}
+
+// StandardLock
+// LockDialect of standard row locking clause, dialect embeds it and overrides RenderLock when syntax of dialect is different.
+type StandardLock struct{}
+
+// RenderLock
+// FOR UPDATE|FOR SHARE [NOWAIT|SKIP LOCKED], it is at the end of select.
+func (lock StandardLock) RenderLock(_ Context, w io.Writer, v Lock) (err error) {
+	err = RenderLock(w, v)
+	return
+}
+
+// RenderLock
+// render standard row locking clause, FOR UPDATE|FOR SHARE [NOWAIT|SKIP LOCKED].
+func RenderLock(w io.Writer, lock Lock) (err error) {
+	_, _ = w.Write(FOR)
+	_, _ = w.Write(SPACE)
+	switch lock.Mode {
+	case ForUpdateLock:
+		_, _ = w.Write(UPDATE)
+		break
+	case ForShareLock:
+		_, _ = w.Write(SHARE)
+		break
+	default:
+		err = errors.Warning("sql: render lock failed").WithCause(fmt.Errorf("invalid lock mode"))
+		return
+	}
+	switch lock.Wait {
+	case WaitLock:
+		break
+	case NoWaitLock:
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(NOWAIT)
+		break
+	case SkipLockedLock:
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(SKIP)
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(LOCKED)
+		break
+	default:
+		err = errors.Warning("sql: render lock failed").WithCause(fmt.Errorf("invalid lock wait"))
+		return
+	}
+	return
+}
@@ -0,0 +1,189 @@
+package specifications
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/services/authorizations"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	maskOptionPrefix = "mask:"
+	// MaskHidden
+	// value is set to zero value, it supports all types.
+	MaskHidden = "hidden"
+	// MaskPartial
+	// head and tail are kept, others are replaced by *, it supports string only.
+	MaskPartial = "partial"
+)
+
+var (
+	// UnmaskAttribute
+	// attribute of authorization, value is array of fields which are not masked for the authorization,
+	// item is * (all), {field} or {table}.{field}.
+	UnmaskAttribute = []byte("unmask")
+)
+
+// Masker
+// mask string value.
+type Masker func(value string) string
+
+var (
+	maskers = sync.Map{}
+)
+
+// RegisterMasker
+// register custom masker, then use it by 'column:"{name},mask:{masker name}"'.
+func RegisterMasker(name string, masker Masker) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == MaskHidden || masker == nil {
+		return
+	}
+	maskers.Store(name, masker)
+}
+
+func getMasker(name string) (masker Masker, has bool) {
+	if name == MaskPartial {
+		masker = PartialMask
+		has = true
+		return
+	}
+	v, exist := maskers.Load(name)
+	if !exist {
+		return
+	}
+	masker, has = v.(Masker)
+	return
+}
+
+// PartialMask
+// quarter of head and quarter of tail are kept, such as 13812345678 to 13*******78.
+func PartialMask(value string) string {
+	n := utf8.RuneCountInString(value)
+	if n == 0 {
+		return value
+	}
+	if n < 3 {
+		return strings.Repeat("*", n)
+	}
+	keep := n / 4
+	if keep < 1 {
+		keep = 1
+	}
+	runes := []rune(value)
+	for i := keep; i < n-keep; i++ {
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+// takeMaskOption
+// mask option can be at any position after name, it is removed from items.
+func takeMaskOption(items []string) (remains []string, mask string) {
+	remains = make([]string, 0, len(items))
+	for _, item := range items {
+		trimmed := strings.TrimSpace(item)
+		if strings.HasPrefix(strings.ToLower(trimmed), maskOptionPrefix) {
+			mask = strings.TrimSpace(trimmed[len(maskOptionPrefix):])
+			continue
+		}
+		remains = append(remains, item)
+	}
+	return
+}
+
+// MaskColumns
+// columns which have mask option.
+func (spec *Specification) MaskColumns() (columns []*Column) {
+	for _, column := range spec.Columns {
+		if column.Mask != "" {
+			columns = append(columns, column)
+		}
+	}
+	return
+}
+
+// unmasked
+// check whether field is unmasked by authorization of ctx.
+func unmasked(spec *Specification, column *Column, fields []string) bool {
+	for _, field := range fields {
+		if field == "*" || field == column.Field {
+			return true
+		}
+		if idx := strings.LastIndexByte(field, '.'); idx > 0 && field[idx+1:] == column.Field && field[:idx] == spec.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func unmaskFields(ctx context.Context) (fields []string, err error) {
+	auth, has, loadErr := authorizations.Load(ctx)
+	if loadErr != nil {
+		err = loadErr
+		return
+	}
+	if !has || !auth.Exist() {
+		return
+	}
+	_, err = auth.Attributes.Get(UnmaskAttribute, &fields)
+	return
+}
+
+// LoadMasks
+// mask columns of entry which are not unmasked by authorization of ctx.
+func LoadMasks(ctx context.Context, entry any) (columns []*Column, err error) {
+	spec, specErr := GetSpecification(ctx, entry)
+	if specErr != nil {
+		err = errors.Warning("sql: load masks failed").WithCause(specErr)
+		return
+	}
+	candidates := spec.MaskColumns()
+	if len(candidates) == 0 {
+		return
+	}
+	fields, fieldsErr := unmaskFields(ctx)
+	if fieldsErr != nil {
+		err = errors.Warning("sql: load masks failed").WithCause(fieldsErr)
+		return
+	}
+	for _, column := range candidates {
+		if unmasked(spec, column, fields) {
+			continue
+		}
+		columns = append(columns, column)
+	}
+	return
+}
+
+// Mask
+// returns masked copy of entries by columns, entries are not changed.
+func Mask[T any](entries []T, columns []*Column) (v []T, err error) {
+	if len(entries) == 0 || len(columns) == 0 {
+		v = entries
+		return
+	}
+	v = make([]T, len(entries))
+	copy(v, entries)
+	for i := range v {
+		rv := reflect.ValueOf(&v[i]).Elem()
+		for _, column := range columns {
+			fv := column.ReadValue(rv)
+			if column.Mask == MaskHidden {
+				fv.Set(reflect.Zero(fv.Type()))
+				continue
+			}
+			masker, has := getMasker(column.Mask)
+			if !has {
+				err = errors.Warning("sql: mask failed").WithCause(fmt.Errorf("masker was not found")).WithMeta("masker", column.Mask).WithMeta("field", column.Field)
+				return
+			}
+			fv.SetString(masker(fv.String()))
+		}
+	}
+	return
+}
@@ -29,7 +29,33 @@ func (o Orders) Render(ctx Context, w io.Writer) (argument []any, err error) {
 			err = errors.Warning("sql: render order by failed").WithCause(fmt.Errorf("%s was not found", order.Name))
 			return
 		}
-		_, _ = buf.WriteString(content[0])
+		if !order.Nearest.IsEmpty() {
+			dialect, dialectErr := loadSpatialDialect(ctx)
+			if dialectErr != nil {
+				err = errors.Warning("sql: render order by failed").WithCause(dialectErr)
+				return
+			}
+			nearest, nearestErr := dialect.RenderNearestOrder(ctx, buf, content[0], geographyOfContext(ctx, order.Name), order.Nearest)
+			if nearestErr != nil {
+				err = errors.Warning("sql: render order by failed").WithCause(nearestErr)
+				return
+			}
+			argument = append(argument, nearest...)
+		} else if order.JsonPath != "" {
+			dialect, dialectErr := loadJsonDialect(ctx)
+			if dialectErr != nil {
+				err = errors.Warning("sql: render order by failed").WithCause(dialectErr)
+				return
+			}
+			path, pathErr := dialect.RenderJsonPathOrder(ctx, buf, content[0], order.JsonPath)
+			if pathErr != nil {
+				err = errors.Warning("sql: render order by failed").WithCause(pathErr)
+				return
+			}
+			argument = append(argument, path...)
+		} else {
+			_, _ = buf.WriteString(content[0])
+		}
 		if order.Desc {
 			_, _ = buf.Write(SPACE)
 			_, _ = buf.Write(DESC)
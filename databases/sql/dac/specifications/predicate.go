@@ -22,6 +22,41 @@ func (p Predicate) Render(ctx Context, w io.Writer) (argument []any, err error)
 		err = errors.Warning("sql: predicate render failed").WithCause(fmt.Errorf("%s was not found in localization", p.Field))
 		return
 	}
+	if encrypted, isEncrypted := encryptedColumnOfContext(ctx, p.Field); isEncrypted {
+		name, bp, bpErr := blindIndexPredicate(ctx, p, encrypted)
+		if bpErr != nil {
+			err = errors.Warning("sql: predicate render failed").WithCause(bpErr)
+			return
+		}
+		column = []string{name}
+		p = bp
+	}
+	if spatial, isSpatial := p.Expression.(conditions.Spatial); isSpatial {
+		dialect, dialectErr := loadSpatialDialect(ctx)
+		if dialectErr != nil {
+			err = errors.Warning("sql: predicate render failed").WithCause(dialectErr)
+			return
+		}
+		argument, err = dialect.RenderSpatialPredicate(ctx, w, column[0], geographyOfContext(ctx, p.Field), p.Operator, spatial)
+		if err != nil {
+			err = errors.Warning("sql: predicate render failed").WithCause(err)
+			return
+		}
+		return
+	}
+	if jv, isJson := p.Expression.(conditions.Json); isJson {
+		dialect, dialectErr := loadJsonDialect(ctx)
+		if dialectErr != nil {
+			err = errors.Warning("sql: predicate render failed").WithCause(dialectErr)
+			return
+		}
+		argument, err = dialect.RenderJsonPredicate(ctx, w, column[0], p.Operator, jv)
+		if err != nil {
+			err = errors.Warning("sql: predicate render failed").WithCause(err)
+			return
+		}
+		return
+	}
 	_, _ = w.Write(bytex.FromString(column[0]))
 	_, _ = w.Write(SPACE)
 	_, _ = w.Write(bytex.FromString(p.Operator.String()))
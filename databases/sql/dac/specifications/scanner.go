@@ -50,10 +50,23 @@ func (generics Generics) WriteTo(spec *Specification, fieldNames []string, entry
 				WithMeta("field", fieldName).WithMeta("table", spec.Key)
 			return
 		}
+		if column.IndexOf != nil {
+			// blind index is not value of field
+			continue
+		}
 		fv := column.ReadValue(rv)
 		generic := generics[i].(*Generic)
 		if generic.Valid {
-			err = column.WriteValue(fv, generic.Value)
+			value := generic.Value
+			if column.Encrypted {
+				value, err = decryptGenericValue(value)
+				if err != nil {
+					err = errors.Warning(fmt.Sprintf("sql: write value into %s.%s field failed", spec.Key, fieldName)).WithCause(err).
+						WithMeta("field", fieldName).WithMeta("table", spec.Key)
+					return
+				}
+			}
+			err = column.WriteValue(fv, value)
 			if err != nil {
 				err = errors.Warning(fmt.Sprintf("sql: write value into %s.%s field failed", spec.Key, fieldName)).WithCause(err).
 					WithMeta("field", fieldName).WithMeta("table", spec.Key)
@@ -0,0 +1,109 @@
+package specifications
+
+import (
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+)
+
+// ShardKeysByCondition
+// returns values of shard key which are restricted by cond,
+// has is false when cond does not restrict shard key, such as no predicate of shard key or shard key in OR with others.
+func (spec *Specification) ShardKeysByCondition(cond conditions.Condition) (keys []any, has bool) {
+	if spec.ShardKey == "" || !cond.Exist() {
+		return
+	}
+	keys, has = spec.shardKeysByNode(cond)
+	return
+}
+
+func (spec *Specification) shardKeysByNode(node conditions.Node) (keys []any, has bool) {
+	switch n := node.(type) {
+	case conditions.Predicate:
+		if n.Field != spec.ShardKey {
+			break
+		}
+		switch n.Operator {
+		case conditions.Equal:
+			if !isShardKeyValue(n.Expression) {
+				break
+			}
+			keys = []any{n.Expression}
+			has = true
+			break
+		case conditions.IN:
+			values, ok := n.Expression.([]any)
+			if !ok || len(values) == 0 {
+				break
+			}
+			if len(values) == 1 {
+				if sub, isSub := values[0].([]any); isSub {
+					values = sub
+				}
+			}
+			for _, value := range values {
+				if !isShardKeyValue(value) {
+					keys = nil
+					return
+				}
+			}
+			keys = values
+			has = true
+			break
+		default:
+			break
+		}
+		break
+	case conditions.Condition:
+		if n.Operation == "" {
+			if n.Left != nil {
+				keys, has = spec.shardKeysByNode(n.Left)
+			}
+			break
+		}
+		leftKeys, leftHas := spec.shardKeysByNode(n.Left)
+		rightKeys, rightHas := spec.shardKeysByNode(n.Right)
+		if n.Operation == conditions.AND {
+			if leftHas {
+				keys, has = leftKeys, true
+			} else if rightHas {
+				keys, has = rightKeys, true
+			}
+			break
+		}
+		if leftHas && rightHas {
+			keys = append(append(make([]any, 0, len(leftKeys)+len(rightKeys)), leftKeys...), rightKeys...)
+			has = true
+		}
+		break
+	default:
+		break
+	}
+	return
+}
+
+func isShardKeyValue(v any) bool {
+	switch v.(type) {
+	case nil, conditions.Literal, conditions.QueryExpr, conditions.Node:
+		return false
+	default:
+		return true
+	}
+}
+
+// ShardKeysByEntries
+// returns values of shard key of entries.
+func (spec *Specification) ShardKeysByEntries(entries ...any) (keys []any, err error) {
+	if spec.ShardKey == "" {
+		return
+	}
+	keys = make([]any, 0, len(entries))
+	for _, entry := range entries {
+		key, keyErr := spec.ArgumentByField(entry, spec.ShardKey)
+		if keyErr != nil {
+			err = errors.Warning("sql: get shard key failed").WithCause(keyErr).WithMeta("table", spec.Key)
+			return
+		}
+		keys = append(keys, key)
+	}
+	return
+}
@@ -0,0 +1,76 @@
+package specifications
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"io"
+)
+
+// SpatialDialect
+// dialect which supports geometry column, spatial predicates and k-nearest ordering.
+// geography is true when column is declared as geography.
+type SpatialDialect interface {
+	// EncodeGeometry
+	// convert geometry into the argument value which database accepts
+	EncodeGeometry(geometry sql.Geometry) (v any, err error)
+	RenderSpatialPredicate(ctx Context, w io.Writer, column string, geography bool, operator conditions.Operator, spatial conditions.Spatial) (arguments []any, err error)
+	RenderNearestOrder(ctx Context, w io.Writer, column string, geography bool, geometry sql.Geometry) (arguments []any, err error)
+}
+
+func loadSpatialDialect(ctx Context) (dialect SpatialDialect, err error) {
+	rc, ok := ctx.(*renderCtx)
+	if !ok {
+		err = errors.Warning("sql: load spatial dialect failed").WithCause(fmt.Errorf("invalid context"))
+		return
+	}
+	dialect, ok = rc.getDialect().(SpatialDialect)
+	if !ok {
+		err = errors.Warning("sql: load spatial dialect failed").WithCause(fmt.Errorf("%s dialect does not support spatial", rc.getDialect().Name()))
+		return
+	}
+	return
+}
+
+// geographyOfContext
+// returns true when field of current rendering specification is geography column.
+func geographyOfContext(ctx Context, field string) (ok bool) {
+	rc, isRc := ctx.(*renderCtx)
+	if !isRc || rc.key == nil {
+		return
+	}
+	spec, specErr := GetSpecification(ctx, rc.key)
+	if specErr != nil {
+		return
+	}
+	column, has := spec.ColumnByField(field)
+	if !has {
+		return
+	}
+	ok = column.Geography()
+	return
+}
+
+func encodeGeometryArguments(dialect Dialect, arguments []any) (err error) {
+	spatial, ok := dialect.(SpatialDialect)
+	if !ok {
+		return
+	}
+	for i, argument := range arguments {
+		geometry, isGeometry := argument.(sql.Geometry)
+		if !isGeometry {
+			continue
+		}
+		if geometry.IsEmpty() {
+			arguments[i] = nil
+			continue
+		}
+		arguments[i], err = spatial.EncodeGeometry(geometry)
+		if err != nil {
+			err = errors.Warning("sql: encode geometry argument failed").WithCause(err).WithMeta("dialect", dialect.Name())
+			return
+		}
+	}
+	return
+}
@@ -8,6 +8,7 @@ import (
 	"golang.org/x/sync/singleflight"
 	"reflect"
 	"sync"
+	"time"
 )
 
 type Specification struct {
@@ -19,6 +20,16 @@ type Specification struct {
 	Type      reflect.Type
 	Columns   []*Column
 	Conflicts []string
+	CacheTTL  time.Duration
+	// ShardKey
+	// field of shard key
+	ShardKey string
+	// History
+	// keep history of rows
+	History bool
+	// CTEs
+	// common table expressions of view, view selects from one of them
+	CTEs []CTE
 }
 
 func (spec *Specification) Instance() (v any) {
@@ -239,6 +250,9 @@ func ScanTable(ctx context.Context, table any) (spec *Specification, err error)
 	}
 	schema := info.schema
 	conflicts := info.conflicts
+	cacheTTL := info.cacheTTL
+	shardKey := info.shardKey
+	history := info.history
 
 	columns, columnsErr := scanTableFields(ctx, fmt.Sprintf("%s.%s", rt.PkgPath(), rt.Name()), rt)
 	if columnsErr != nil {
@@ -256,6 +270,18 @@ func ScanTable(ctx context.Context, table any) (spec *Specification, err error)
 		Type:      rt,
 		Columns:   columns,
 		Conflicts: conflicts,
+		CacheTTL:  cacheTTL,
+		ShardKey:  shardKey,
+		History:   history,
+	}
+
+	if shardKey != "" {
+		if _, has := spec.ColumnByField(shardKey); !has {
+			err = errors.Warning("sql: scan table failed").
+				WithCause(fmt.Errorf("shard key field was not found")).
+				WithMeta("struct", rt.String()).WithMeta("field", shardKey)
+			return
+		}
 	}
 
 	tableNames := make([]string, 0, 1)
@@ -310,6 +336,13 @@ func scanTableFields(ctx context.Context, key string, rt reflect.Type) (columns
 		if column != nil {
 			columns = append(columns, column)
 			dict.Set(fmt.Sprintf("%s:%s", key, column.Field), column.Name)
+			if column.Encrypted {
+				encryptedColumns.Store(fmt.Sprintf("%s:%s", key, column.Field), column)
+			}
+			if column.BlindIndex != nil {
+				columns = append(columns, column.BlindIndex)
+				dict.Set(fmt.Sprintf("%s:%s", key, column.BlindIndex.Field), column.BlindIndex.Name)
+			}
 		}
 	}
 
@@ -5,12 +5,16 @@ import (
 	"github.com/aacfactory/errors"
 	"reflect"
 	"strings"
+	"time"
 )
 
 type TableInfo struct {
 	schema    string
 	name      string
 	conflicts []string
+	cacheTTL  time.Duration
+	shardKey  string
+	history   bool
 }
 
 func MaybeTable(e any) (ok bool) {
@@ -75,11 +79,44 @@ func GetTableInfo(e any) (info TableInfo, err error) {
 	for i, conflict := range conflicts {
 		conflicts[i] = strings.TrimSpace(conflict)
 	}
+	// cache ttl, optional
+	var cacheTTL time.Duration
+	if _, hasCacheTTLFunc := result.Type().MethodByName("CacheTTL"); hasCacheTTLFunc {
+		cacheTTLResults := result.MethodByName("CacheTTL").Call(nil)
+		if len(cacheTTLResults) != 1 || cacheTTLResults[0].Type() != reflect.TypeOf(cacheTTL) {
+			err = errors.Warning(fmt.Sprintf("sql: %s.%s has invalid TableInfo func", rt.PkgPath(), rt.Name()))
+			return
+		}
+		cacheTTL = time.Duration(cacheTTLResults[0].Int())
+	}
+	// shard key, optional
+	shardKey := ""
+	if _, hasShardKeyFunc := result.Type().MethodByName("ShardKey"); hasShardKeyFunc {
+		shardKeyResults := result.MethodByName("ShardKey").Call(nil)
+		if len(shardKeyResults) != 1 || shardKeyResults[0].Type().Kind() != reflect.String {
+			err = errors.Warning(fmt.Sprintf("sql: %s.%s has invalid TableInfo func", rt.PkgPath(), rt.Name()))
+			return
+		}
+		shardKey = strings.TrimSpace(shardKeyResults[0].String())
+	}
+	// history, optional
+	history := false
+	if _, hasHistoryFunc := result.Type().MethodByName("KeepHistory"); hasHistoryFunc {
+		historyResults := result.MethodByName("KeepHistory").Call(nil)
+		if len(historyResults) != 1 || historyResults[0].Type().Kind() != reflect.Bool {
+			err = errors.Warning(fmt.Sprintf("sql: %s.%s has invalid TableInfo func", rt.PkgPath(), rt.Name()))
+			return
+		}
+		history = historyResults[0].Bool()
+	}
 	// view
 	info = TableInfo{
 		schema:    strings.TrimSpace(schema),
 		name:      strings.TrimSpace(name),
 		conflicts: conflicts,
+		cacheTTL:  cacheTTL,
+		shardKey:  shardKey,
+		history:   history,
 	}
 	return
 }
@@ -0,0 +1,410 @@
+package specifications
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/valyala/bytebufferpool"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	treeTag = "tree"
+)
+
+var (
+	WITH      = []byte("WITH")
+	RECURSIVE = []byte("RECURSIVE")
+	UNION     = []byte("UNION")
+	INNER     = []byte("INNER")
+	JOIN      = []byte("JOIN")
+	LT        = []byte("<")
+)
+
+const (
+	treeCTE      = "__TREE"
+	treeNode     = "__NODE"
+	treeDepth    = "__DEPTH"
+	treeParentAs = "__PARENT"
+)
+
+// TreeDialect
+// dialect which supports recursive query of tree table.
+type TreeDialect interface {
+	Subtree(ctx Context, spec *Specification, root any, depth int, cond Condition, orders Orders) (method Method, query []byte, arguments []any, fields []string, err error)
+	// Ancestors
+	// entries are ordered from top to node by caller, so orders are not supported.
+	Ancestors(ctx Context, spec *Specification, id any, cond Condition) (method Method, query []byte, arguments []any, fields []string, err error)
+}
+
+// NewRecursiveTree
+// create TreeDialect which renders by RenderSubtree and RenderAncestors with dialect.
+func NewRecursiveTree(dialect Dialect) RecursiveTree {
+	return RecursiveTree{
+		dialect: dialect,
+	}
+}
+
+// RecursiveTree
+// TreeDialect of dialect which supports `WITH RECURSIVE`,
+// dialect embeds it and overrides methods when syntax of dialect is different.
+type RecursiveTree struct {
+	dialect Dialect
+}
+
+func (tree RecursiveTree) Subtree(ctx Context, spec *Specification, root any, depth int, cond Condition, orders Orders) (method Method, query []byte, arguments []any, fields []string, err error) {
+	method, query, arguments, fields, err = RenderSubtree(ctx, tree.dialect, spec, root, depth, cond, orders)
+	if err != nil {
+		err = errors.Warning("sql: dialect generate subtree failed").WithMeta("table", spec.Key).WithCause(err).WithMeta("dialect", tree.dialect.Name())
+		return
+	}
+	return
+}
+
+func (tree RecursiveTree) Ancestors(ctx Context, spec *Specification, id any, cond Condition) (method Method, query []byte, arguments []any, fields []string, err error) {
+	method, query, arguments, fields, err = RenderAncestors(ctx, tree.dialect, spec, id, cond)
+	if err != nil {
+		err = errors.Warning("sql: dialect generate ancestors failed").WithMeta("table", spec.Key).WithCause(err).WithMeta("dialect", tree.dialect.Name())
+		return
+	}
+	return
+}
+
+// TreeColumns
+// ident and parent column of table which has `tree:"{ParentField}+{ChildrenField}"` tag.
+func (spec *Specification) TreeColumns() (ident *Column, parent *Column, err error) {
+	identField, parentField, found := lookupTreeFields(spec.Type)
+	if !found {
+		err = errors.Warning("sql: get tree columns failed").WithCause(fmt.Errorf("tree tag was not found")).WithMeta("table", spec.Key)
+		return
+	}
+	has := false
+	ident, has = spec.ColumnByField(identField)
+	if !has {
+		err = errors.Warning("sql: get tree columns failed").WithCause(fmt.Errorf("column of %s was not found", identField)).WithMeta("table", spec.Key)
+		return
+	}
+	parent, has = spec.ColumnByField(parentField)
+	if !has {
+		err = errors.Warning("sql: get tree columns failed").WithCause(fmt.Errorf("column of %s was not found", parentField)).WithMeta("table", spec.Key)
+		return
+	}
+	return
+}
+
+func lookupTreeFields(rt reflect.Type) (ident string, parent string, found bool) {
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			ident, parent, found = lookupTreeFields(field.Type)
+			if found {
+				return
+			}
+			continue
+		}
+		tag, has := field.Tag.Lookup(treeTag)
+		if !has {
+			continue
+		}
+		idx := strings.Index(tag, "+")
+		if idx < 1 {
+			return
+		}
+		ident = field.Name
+		parent = strings.TrimSpace(tag[0:idx])
+		found = true
+		return
+	}
+	return
+}
+
+// RenderSubtreeCTE
+// render `WITH RECURSIVE` which selects ident of root and descendants of root.
+// when depth is greater than 0, then descendants are limited in depth, 1 means children of root.
+func RenderSubtreeCTE(ctx Context, w io.Writer, spec *Specification, root any, depth int) (arguments []any, err error) {
+	ident, parent, columnsErr := spec.TreeColumns()
+	if columnsErr != nil {
+		err = columnsErr
+		return
+	}
+	tableName := treeTableName(ctx, spec)
+	cte := ctx.FormatIdent(treeCTE)
+	node := ctx.FormatIdent(treeNode)
+	identName := ctx.FormatIdent(ident.Name)
+	parentName := ctx.FormatIdent(parent.Name)
+	depthName := ctx.FormatIdent(treeDepth)
+	limited := depth > 0
+
+	_, _ = w.Write(WITH)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(RECURSIVE)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(cte))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(LB)
+	_, _ = w.Write(bytex.FromString(identName))
+	if limited {
+		_, _ = w.Write(COMMA)
+		_, _ = w.Write(bytex.FromString(depthName))
+	}
+	_, _ = w.Write(RB)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(AS)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(LB)
+	// root
+	_, _ = w.Write(SELECT)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(identName))
+	if limited {
+		_, _ = w.Write(COMMA)
+		_, _ = w.Write([]byte("0"))
+	}
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(FROM)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(tableName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(WHERE)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(identName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(EQ)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+	arguments = append(arguments, root)
+	// descendants
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(UNION)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(SELECT)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(node))
+	_, _ = w.Write(DOT)
+	_, _ = w.Write(bytex.FromString(identName))
+	if limited {
+		_, _ = w.Write(COMMA)
+		_, _ = w.Write(bytex.FromString(cte))
+		_, _ = w.Write(DOT)
+		_, _ = w.Write(bytex.FromString(depthName))
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(PLUS)
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write([]byte("1"))
+	}
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(FROM)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(tableName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(AS)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(node))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(INNER)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(JOIN)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(cte))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(ON)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(node))
+	_, _ = w.Write(DOT)
+	_, _ = w.Write(bytex.FromString(parentName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(EQ)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(cte))
+	_, _ = w.Write(DOT)
+	_, _ = w.Write(bytex.FromString(identName))
+	if limited {
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(WHERE)
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(bytex.FromString(cte))
+		_, _ = w.Write(DOT)
+		_, _ = w.Write(bytex.FromString(depthName))
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(LT)
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(bytex.FromString(strconv.Itoa(depth)))
+	}
+	_, _ = w.Write(RB)
+	_, _ = w.Write(SPACE)
+	return
+}
+
+// RenderAncestorsCTE
+// render `WITH RECURSIVE` which selects ident of node and ancestors of node.
+func RenderAncestorsCTE(ctx Context, w io.Writer, spec *Specification, id any) (arguments []any, err error) {
+	ident, parent, columnsErr := spec.TreeColumns()
+	if columnsErr != nil {
+		err = columnsErr
+		return
+	}
+	tableName := treeTableName(ctx, spec)
+	cte := ctx.FormatIdent(treeCTE)
+	node := ctx.FormatIdent(treeNode)
+	identName := ctx.FormatIdent(ident.Name)
+	parentName := ctx.FormatIdent(parent.Name)
+	parentAs := ctx.FormatIdent(treeParentAs)
+
+	_, _ = w.Write(WITH)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(RECURSIVE)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(cte))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(LB)
+	_, _ = w.Write(bytex.FromString(identName))
+	_, _ = w.Write(COMMA)
+	_, _ = w.Write(bytex.FromString(parentAs))
+	_, _ = w.Write(RB)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(AS)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(LB)
+	// node
+	_, _ = w.Write(SELECT)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(identName))
+	_, _ = w.Write(COMMA)
+	_, _ = w.Write(bytex.FromString(parentName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(FROM)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(tableName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(WHERE)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(identName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(EQ)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(ctx.NextQueryPlaceholder()))
+	arguments = append(arguments, id)
+	// ancestors
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(UNION)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(SELECT)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(node))
+	_, _ = w.Write(DOT)
+	_, _ = w.Write(bytex.FromString(identName))
+	_, _ = w.Write(COMMA)
+	_, _ = w.Write(bytex.FromString(node))
+	_, _ = w.Write(DOT)
+	_, _ = w.Write(bytex.FromString(parentName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(FROM)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(tableName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(AS)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(node))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(INNER)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(JOIN)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(cte))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(ON)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(node))
+	_, _ = w.Write(DOT)
+	_, _ = w.Write(bytex.FromString(identName))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(EQ)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(bytex.FromString(cte))
+	_, _ = w.Write(DOT)
+	_, _ = w.Write(bytex.FromString(parentAs))
+	_, _ = w.Write(RB)
+	_, _ = w.Write(SPACE)
+	return
+}
+
+// RenderSubtree
+// render subtree query of dialect which supports `WITH RECURSIVE`,
+// it is RenderSubtreeCTE followed by query of dialect whose cond is in TreeCondition.
+func RenderSubtree(ctx Context, dialect Dialect, spec *Specification, root any, depth int, cond Condition, orders Orders) (method Method, query []byte, arguments []any, fields []string, err error) {
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+	arguments, err = RenderSubtreeCTE(ctx, buf, spec, root, depth)
+	if err != nil {
+		return
+	}
+	method, arguments, fields, err = renderTreeQuery(ctx, dialect, buf, spec, cond, orders, arguments)
+	if err != nil {
+		return
+	}
+	query = bytex.FromString(buf.String())
+	return
+}
+
+// RenderAncestors
+// render ancestors query of dialect which supports `WITH RECURSIVE`,
+// it is RenderAncestorsCTE followed by query of dialect whose cond is in TreeCondition.
+func RenderAncestors(ctx Context, dialect Dialect, spec *Specification, id any, cond Condition) (method Method, query []byte, arguments []any, fields []string, err error) {
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+	arguments, err = RenderAncestorsCTE(ctx, buf, spec, id)
+	if err != nil {
+		return
+	}
+	method, arguments, fields, err = renderTreeQuery(ctx, dialect, buf, spec, cond, nil, arguments)
+	if err != nil {
+		return
+	}
+	query = bytex.FromString(buf.String())
+	return
+}
+
+func renderTreeQuery(ctx Context, dialect Dialect, w io.Writer, spec *Specification, cond Condition, orders Orders, cteArguments []any) (method Method, arguments []any, fields []string, err error) {
+	cond, err = TreeCondition(ctx, spec, cond)
+	if err != nil {
+		return
+	}
+	var query []byte
+	var queryArguments []any
+	method, query, queryArguments, fields, err = dialect.Query(ctx, spec, cond, orders, 0, 0)
+	if err != nil {
+		return
+	}
+	_, _ = w.Write(query)
+	arguments = append(cteArguments, queryArguments...)
+	return
+}
+
+// TreeCondition
+// append `{ident} IN (SELECT {ident} FROM {cte})` into cond.
+func TreeCondition(ctx Context, spec *Specification, cond Condition) (v Condition, err error) {
+	ident, _, columnsErr := spec.TreeColumns()
+	if columnsErr != nil {
+		err = columnsErr
+		return
+	}
+	sub := fmt.Sprintf("SELECT %s FROM %s", ctx.FormatIdent(ident.Name), ctx.FormatIdent(treeCTE))
+	in := conditions.New(conditions.In(ident.Field, conditions.LitQuery(sub)))
+	if cond.Exist() {
+		in = in.And(cond.Condition)
+	}
+	v = Condition{in}
+	return
+}
+
+func treeTableName(ctx Context, spec *Specification) string {
+	tableName := ctx.FormatIdent(spec.Name)
+	if spec.Schema != "" {
+		tableName = fmt.Sprintf("%s.%s", ctx.FormatIdent(spec.Schema), tableName)
+	}
+	return tableName
+}
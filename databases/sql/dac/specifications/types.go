@@ -32,6 +32,7 @@ var (
 	nullDatetimeType  = reflect.TypeOf(ssql.NullDatetime{})
 	nullTimesDateType = reflect.TypeOf(ssql.NullDate{})
 	nullTimesTimeType = reflect.TypeOf(ssql.NullTime{})
+	geometryType      = reflect.TypeOf(ssql.Geometry{})
 	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
 	scannerType       = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
 )
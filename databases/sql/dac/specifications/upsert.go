@@ -0,0 +1,208 @@
+package specifications
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/context"
+)
+
+var (
+	EXCLUDED = []byte("EXCLUDED")
+	IF       = []byte("IF")
+)
+
+type UpsertMode int
+
+const (
+	// UpsertAssign
+	// column = value of insertion
+	UpsertAssign UpsertMode = iota
+	// UpsertIncr
+	// column = column + value of insertion
+	UpsertIncr
+)
+
+// UpsertSet
+// column of update branch of upsert.
+type UpsertSet struct {
+	Field string
+	Mode  UpsertMode
+}
+
+// Upsert
+// options of insert or update, it is rendered by UpsertDialect.
+type Upsert struct {
+	// Conflicts
+	// fields of conflict target, spec.Conflicts is used when it and Constraint are empty.
+	Conflicts []string
+	// Constraint
+	// name of conflict constraint.
+	Constraint string
+	// Sets
+	// columns to update on conflict, all non-audit columns are updated when it is empty.
+	// modification audit columns are always updated, version column is always increased.
+	Sets []UpsertSet
+	// Cond
+	// update only when it is matched.
+	Cond Condition
+}
+
+// UpsertColumn
+// resolved column of update branch.
+type UpsertColumn struct {
+	*Column
+	Mode UpsertMode
+}
+
+// FieldArgument
+// argument which is the value of field of entry, it is replaced by BuildUpsert.
+type FieldArgument string
+
+// UpsertDialect
+// dialect which supports upsert with options.
+// elements of arguments which are FieldArgument are replaced by values of entry.
+type UpsertDialect interface {
+	Upsert(ctx Context, spec *Specification, upsert Upsert) (method Method, query []byte, arguments []any, returning []string, err error)
+}
+
+// UpsertConflicts
+// columns of conflict target.
+func UpsertConflicts(spec *Specification, upsert Upsert) (columns []*Column, err error) {
+	conflicts := upsert.Conflicts
+	if len(conflicts) == 0 {
+		conflicts = spec.Conflicts
+	}
+	for _, conflict := range conflicts {
+		column, has := spec.ColumnByField(conflict)
+		if !has {
+			err = errors.Warning("sql: upsert conflicts failed").WithCause(fmt.Errorf("column was not found by %s field", conflict)).WithMeta("table", spec.Key)
+			return
+		}
+		columns = append(columns, column)
+	}
+	return
+}
+
+// UpsertColumns
+// columns of update branch, modification audit and version columns are at the end.
+func UpsertColumns(spec *Specification, upsert Upsert) (columns []UpsertColumn, err error) {
+	if len(upsert.Sets) == 0 {
+		for _, column := range spec.Columns {
+			if !upsertable(column) {
+				continue
+			}
+			columns = append(columns, UpsertColumn{Column: column, Mode: UpsertAssign})
+		}
+	} else {
+		for _, set := range upsert.Sets {
+			column, has := spec.ColumnByField(set.Field)
+			if !has {
+				err = errors.Warning("sql: upsert columns failed").WithCause(fmt.Errorf("column was not found by %s field", set.Field)).WithMeta("table", spec.Key)
+				return
+			}
+			if !upsertable(column) || column.IndexOf != nil {
+				err = errors.Warning("sql: upsert columns failed").WithCause(fmt.Errorf("%s field can not be updated", set.Field)).WithMeta("table", spec.Key)
+				return
+			}
+			if set.Mode == UpsertIncr && (column.Encrypted || column.Type.Name != IntType && column.Type.Name != FloatType) {
+				err = errors.Warning("sql: upsert columns failed").WithCause(fmt.Errorf("%s field can not be increased", set.Field)).WithMeta("table", spec.Key)
+				return
+			}
+			columns = append(columns, UpsertColumn{Column: column, Mode: set.Mode})
+			if column.BlindIndex != nil {
+				columns = append(columns, UpsertColumn{Column: column.BlindIndex, Mode: UpsertAssign})
+			}
+		}
+	}
+	for _, column := range spec.Columns {
+		if column.Kind == Amb || column.Kind == Amt || column.Kind == Aol {
+			columns = append(columns, UpsertColumn{Column: column, Mode: UpsertAssign})
+		}
+	}
+	if len(columns) == 0 {
+		err = errors.Warning("sql: upsert columns failed").WithCause(fmt.Errorf("no columns to update")).WithMeta("table", spec.Key)
+		return
+	}
+	return
+}
+
+func upsertable(column *Column) bool {
+	switch column.Kind {
+	case Normal, Reference, Json, Geo:
+		return !column.Incr()
+	default:
+		return false
+	}
+}
+
+// RenderUpsertCond
+// render cond of upsert into bytes, so it can be written more than once.
+func RenderUpsertCond(ctx Context, upsert Upsert) (p []byte, arguments []any, err error) {
+	if upsert.Cond.Left == nil {
+		return
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, 64))
+	arguments, err = upsert.Cond.Render(ctx, buf)
+	if err != nil {
+		return
+	}
+	p = buf.Bytes()
+	return
+}
+
+// BuildUpsert
+// dialect must be UpsertDialect.
+func BuildUpsert[T any](ctx context.Context, entries []T, upsert Upsert) (method Method, query []byte, arguments []any, returning []string, err error) {
+	dialect, dialectErr := LoadDialect(ctx)
+	if dialectErr != nil {
+		err = dialectErr
+		return
+	}
+	upsertDialect, ok := dialect.(UpsertDialect)
+	if !ok {
+		err = errors.Warning("sql: build upsert failed").WithCause(fmt.Errorf("%s dialect does not support upsert", dialect.Name()))
+		return
+	}
+	spec, specErr := GetSpecification(ctx, entries[0])
+	if specErr != nil {
+		err = specErr
+		return
+	}
+	if spec.View {
+		err = errors.Warning(fmt.Sprintf("sql: %s is view", spec.Key))
+		return
+	}
+	var templates []any
+	method, query, templates, returning, err = upsertDialect.Upsert(Todo(ctx, entries[0], dialect), spec, upsert)
+	if err != nil {
+		return
+	}
+	// audit
+	auditErr := TrySetupAuditCreation[T](ctx, spec, entries)
+	if auditErr != nil {
+		err = auditErr
+		return
+	}
+	auditErr = TrySetupAuditModification[T](ctx, spec, entries)
+	if auditErr != nil {
+		err = auditErr
+		return
+	}
+	arguments = make([]any, 0, len(templates))
+	for _, template := range templates {
+		field, isField := template.(FieldArgument)
+		if !isField {
+			arguments = append(arguments, template)
+			continue
+		}
+		args, argsErr := spec.Arguments(entries[0], []string{string(field)})
+		if argsErr != nil {
+			err = argsErr
+			return
+		}
+		arguments = append(arguments, args...)
+	}
+	err = encodeGeometryArguments(dialect, arguments)
+	return
+}
@@ -31,7 +31,7 @@ func ScanRows[T any](ctx context.Context, rows sql.Rows, fields []string) (entri
 		writeErr := generics.WriteTo(spec, fields, &entry)
 		releaseGenerics(generics)
 		if writeErr != nil {
-			err = scanErr
+			err = writeErr
 			return
 		}
 		entries = append(entries, entry)
@@ -13,6 +13,7 @@ type ViewInfo struct {
 	name   string
 	schema string
 	base   any
+	ctes   []CTESource
 }
 
 func MaybeView(e any) (ok bool) {
@@ -59,6 +60,23 @@ func GetViewInfo(e any) (info ViewInfo, err error) {
 			schema: strings.TrimSpace(schema),
 			base:   nil,
 		}
+		// ctes
+		if _, hasCTEsFunc := result.Type().MethodByName("CTEs"); hasCTEsFunc {
+			ctesResults := result.MethodByName("CTEs").Call(nil)
+			if len(ctesResults) != 1 || ctesResults[0].Kind() != reflect.Slice {
+				err = errors.Warning(fmt.Sprintf("sql: %s.%s has invalid ViewInfo func", rt.PkgPath(), rt.Name()))
+				return
+			}
+			ctes := ctesResults[0]
+			for i := 0; i < ctes.Len(); i++ {
+				cte, isCTE := ctes.Index(i).Interface().(CTESource)
+				if !isCTE {
+					err = errors.Warning(fmt.Sprintf("sql: %s.%s has invalid ViewInfo func", rt.PkgPath(), rt.Name()))
+					return
+				}
+				info.ctes = append(info.ctes, cte)
+			}
+		}
 		return
 	}
 	// base
@@ -109,6 +127,19 @@ func ScanView(ctx context.Context, view any) (spec *Specification, err error) {
 				WithMeta("struct", reflect.TypeOf(view).String())
 			return
 		}
+		ctes, ctesErr := newCTEs(ctx, info.ctes)
+		if ctesErr != nil {
+			err = errors.Warning("sql: scan view failed").
+				WithCause(ctesErr).
+				WithMeta("struct", reflect.TypeOf(view).String())
+			return
+		}
+		if len(ctes) > 0 && schema != "" {
+			err = errors.Warning("sql: scan view failed").
+				WithCause(fmt.Errorf("schema is not supported when view selects from cte")).
+				WithMeta("struct", reflect.TypeOf(view).String())
+			return
+		}
 		spec = &Specification{
 			Key:     key,
 			Schema:  schema,
@@ -116,6 +147,7 @@ func ScanView(ctx context.Context, view any) (spec *Specification, err error) {
 			View:    true,
 			Type:    rt,
 			Columns: columns,
+			CTEs:    ctes,
 		}
 		tableNames := make([]string, 0, 1)
 		if schema != "" {
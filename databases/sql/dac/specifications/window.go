@@ -0,0 +1,149 @@
+package specifications
+
+import (
+	"io"
+	"strings"
+)
+
+var (
+	OVER      = []byte("OVER")
+	PARTITION = []byte("PARTITION")
+)
+
+const (
+	windowPartitionPrefix = "partition:"
+	windowOrdersPrefix    = "orders:"
+	windowFramePrefix     = "frame:"
+)
+
+// WindowOrder
+// order of window, name is column name.
+type WindowOrder struct {
+	Name string
+	Desc bool
+}
+
+// Window
+// window function column, 'column:"{name},vc,window,{func},partition:{column}+{column},orders:{column}@desc+{column},frame:{frame}"'.
+// columns of partition and orders are column names of source, such as
+// 'column:"RN,vc,window,ROW_NUMBER(),partition:USER_ID,orders:CREATE_AT@desc"'.
+type Window struct {
+	Function  string
+	Partition []string
+	Orders    []WindowOrder
+	Frame     string
+}
+
+// windowOptions
+// function may contain comma, such as LAG(AMOUNT, 1), so items before options are joined.
+// returns [function, partition, orders, frame].
+func windowOptions(items []string) (options []string) {
+	fn := make([]string, 0, 1)
+	partition, orders, frame := "", "", ""
+	for _, item := range items {
+		trimmed := strings.TrimSpace(item)
+		lower := strings.ToLower(trimmed)
+		if strings.HasPrefix(lower, windowPartitionPrefix) {
+			partition = strings.TrimSpace(trimmed[len(windowPartitionPrefix):])
+			continue
+		}
+		if strings.HasPrefix(lower, windowOrdersPrefix) {
+			orders = strings.TrimSpace(trimmed[len(windowOrdersPrefix):])
+			continue
+		}
+		if strings.HasPrefix(lower, windowFramePrefix) {
+			frame = strings.TrimSpace(trimmed[len(windowFramePrefix):])
+			continue
+		}
+		fn = append(fn, trimmed)
+	}
+	options = []string{strings.Join(fn, ", "), partition, orders, frame}
+	return
+}
+
+// Window
+// returns window when column is window virtual column.
+func (column *Column) Window() (window Window, ok bool) {
+	kind, _, isVirtual := column.Virtual()
+	if !isVirtual || kind != WindowVirtualQuery || len(column.Type.Options) < 5 {
+		return
+	}
+	window.Function = column.Type.Options[1]
+	if partition := column.Type.Options[2]; partition != "" {
+		for _, name := range strings.Split(partition, "+") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			window.Partition = append(window.Partition, name)
+		}
+	}
+	if orders := column.Type.Options[3]; orders != "" {
+		for _, order := range strings.Split(orders, "+") {
+			order = strings.TrimSpace(order)
+			if order == "" {
+				continue
+			}
+			desc := false
+			if idx := strings.IndexByte(order, '@'); idx > 0 {
+				desc = strings.ToLower(strings.TrimSpace(order[idx+1:])) == "desc"
+				order = strings.TrimSpace(order[:idx])
+			}
+			window.Orders = append(window.Orders, WindowOrder{Name: order, Desc: desc})
+		}
+	}
+	window.Frame = column.Type.Options[4]
+	ok = true
+	return
+}
+
+// RenderWindow
+// {func} OVER (PARTITION BY {column}, ... ORDER BY {column} DESC, ... {frame})
+func RenderWindow(ctx Context, w io.Writer, window Window) {
+	_, _ = w.Write([]byte(window.Function))
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(OVER)
+	_, _ = w.Write(SPACE)
+	_, _ = w.Write(LB)
+	n := 0
+	if len(window.Partition) > 0 {
+		_, _ = w.Write(PARTITION)
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(BY)
+		_, _ = w.Write(SPACE)
+		for i, name := range window.Partition {
+			if i > 0 {
+				_, _ = w.Write(COMMA)
+			}
+			_, _ = w.Write([]byte(ctx.FormatIdent(name)))
+		}
+		n++
+	}
+	if len(window.Orders) > 0 {
+		if n > 0 {
+			_, _ = w.Write(SPACE)
+		}
+		_, _ = w.Write(ORDER)
+		_, _ = w.Write(SPACE)
+		_, _ = w.Write(BY)
+		_, _ = w.Write(SPACE)
+		for i, order := range window.Orders {
+			if i > 0 {
+				_, _ = w.Write(COMMA)
+			}
+			_, _ = w.Write([]byte(ctx.FormatIdent(order.Name)))
+			if order.Desc {
+				_, _ = w.Write(SPACE)
+				_, _ = w.Write(DESC)
+			}
+		}
+		n++
+	}
+	if window.Frame != "" {
+		if n > 0 {
+			_, _ = w.Write(SPACE)
+		}
+		_, _ = w.Write([]byte(window.Frame))
+	}
+	_, _ = w.Write(RB)
+}
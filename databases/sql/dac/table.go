@@ -2,11 +2,15 @@ package dac
 
 import (
 	"strings"
+	"time"
 )
 
 type TableInfoOptions struct {
 	schema    string
 	conflicts []string
+	cacheTTL  time.Duration
+	shardKey  string
+	history   bool
 }
 
 type TableInfoOption func(options *TableInfoOptions)
@@ -31,6 +35,34 @@ func Conflicts(conflicts ...string) TableInfoOption {
 	}
 }
 
+// Cache
+// enable result cache of query, cached results are invalidated when table is written by dac.
+func Cache(ttl time.Duration) TableInfoOption {
+	return func(options *TableInfoOptions) {
+		if ttl < 0 {
+			ttl = 0
+		}
+		options.cacheTTL = ttl
+	}
+}
+
+// ShardKey
+// param is field not column, statements are routed by value of it when database is sharding.
+func ShardKey(field string) TableInfoOption {
+	return func(options *TableInfoOptions) {
+		options.shardKey = strings.TrimSpace(field)
+	}
+}
+
+// KeepHistory
+// previous row is written into history table ({name}_HISTORY) when row is updated or deleted by dac,
+// use History and AsOf to read it.
+func KeepHistory() TableInfoOption {
+	return func(options *TableInfoOptions) {
+		options.history = true
+	}
+}
+
 func Info(name string, options ...TableInfoOption) TableInfo {
 	opt := TableInfoOptions{}
 	for _, option := range options {
@@ -40,6 +72,9 @@ func Info(name string, options ...TableInfoOption) TableInfo {
 		name:      strings.TrimSpace(name),
 		schema:    opt.schema,
 		conflicts: opt.conflicts,
+		cacheTTL:  opt.cacheTTL,
+		shardKey:  opt.shardKey,
+		history:   opt.history,
 	}
 }
 
@@ -47,6 +82,9 @@ type TableInfo struct {
 	name      string
 	schema    string
 	conflicts []string
+	cacheTTL  time.Duration
+	shardKey  string
+	history   bool
 }
 
 func (info TableInfo) Schema() string {
@@ -61,6 +99,18 @@ func (info TableInfo) Conflicts() []string {
 	return info.conflicts
 }
 
+func (info TableInfo) CacheTTL() time.Duration {
+	return info.cacheTTL
+}
+
+func (info TableInfo) ShardKey() string {
+	return info.shardKey
+}
+
+func (info TableInfo) KeepHistory() bool {
+	return info.history
+}
+
 // Table
 // the recv of TableInfo method must be value, can not be ptr
 type Table interface {
@@ -72,6 +122,7 @@ type ViewInfo struct {
 	name   string
 	schema string
 	base   Table
+	ctes   []CTE
 }
 
 func (info ViewInfo) Pure() (string, string, bool) {
@@ -82,6 +133,10 @@ func (info ViewInfo) Base() Table {
 	return info.base
 }
 
+func (info ViewInfo) CTEs() []CTE {
+	return info.ctes
+}
+
 func TableView(table Table) ViewInfo {
 	return ViewInfo{
 		pure:   false,
@@ -104,6 +159,18 @@ func PureView(name string, schema ...string) ViewInfo {
 	}
 }
 
+// CTEView
+// view selects from cte which is named from, such as WITH "ranked" AS (...) SELECT ... FROM "ranked".
+func CTEView(from string, ctes ...CTE) ViewInfo {
+	return ViewInfo{
+		pure:   true,
+		name:   strings.TrimSpace(from),
+		schema: "",
+		base:   nil,
+		ctes:   ctes,
+	}
+}
+
 type View interface {
 	ViewInfo() ViewInfo
 }
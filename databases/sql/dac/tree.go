@@ -1,9 +1,13 @@
 package dac
 
 import (
+	"fmt"
 	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
 	"github.com/aacfactory/fns/commons/container/trees"
 	"github.com/aacfactory/fns/context"
+	"reflect"
 )
 
 func Tree[T Table](ctx context.Context, options ...QueryOption) (entry T, err error) {
@@ -31,3 +35,113 @@ func Trees[T Table](ctx context.Context, options ...QueryOption) (entries []T, e
 	}
 	return
 }
+
+// Subtree
+// query root and descendants of root by recursive query, then convert them to tree.
+// when depth is greater than 0, then descendants are limited in depth, 1 means children of root.
+// table must have `tree:"{ParentField}+{ChildrenField}"` tag on ident field.
+func Subtree[T Table](ctx context.Context, root any, depth int, options ...QueryOption) (entry T, has bool, err error) {
+	opt := QueryOptions{}
+	for _, option := range options {
+		option(&opt)
+	}
+	_, query, arguments, fields, buildErr := specifications.BuildSubtree[T](
+		ctx,
+		root, depth,
+		specifications.Condition{Condition: opt.cond},
+		specifications.Orders(opt.orders),
+	)
+	if buildErr != nil {
+		err = errors.Warning("sql: subtree failed").WithCause(buildErr)
+		return
+	}
+	entries, queryErr := queryTreeEntries[T](ctx, query, arguments, fields)
+	if queryErr != nil {
+		err = errors.Warning("sql: subtree failed").WithCause(queryErr)
+		return
+	}
+	entries, err = trees.ConvertListToTree[T](entries)
+	if err != nil {
+		err = errors.Warning("sql: subtree failed").WithCause(err)
+		return
+	}
+	if has = len(entries) > 0; has {
+		entry = entries[0]
+	}
+	return
+}
+
+// Ancestors
+// query node and ancestors of node by recursive query, entries are ordered from top to node, so Orders option is not supported.
+// table must have `tree:"{ParentField}+{ChildrenField}"` tag on ident field.
+func Ancestors[T Table](ctx context.Context, id any, options ...QueryOption) (entries []T, err error) {
+	opt := QueryOptions{}
+	for _, option := range options {
+		option(&opt)
+	}
+	if len(opt.orders) > 0 {
+		err = errors.Warning("sql: ancestors failed").WithCause(fmt.Errorf("orders is not supported, entries are ordered from top to node"))
+		return
+	}
+	_, query, arguments, fields, buildErr := specifications.BuildAncestors[T](
+		ctx,
+		id,
+		specifications.Condition{Condition: opt.cond},
+	)
+	if buildErr != nil {
+		err = errors.Warning("sql: ancestors failed").WithCause(buildErr)
+		return
+	}
+	items, queryErr := queryTreeEntries[T](ctx, query, arguments, fields)
+	if queryErr != nil {
+		err = errors.Warning("sql: ancestors failed").WithCause(queryErr)
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+	spec, specErr := specifications.GetSpecification(ctx, specifications.Instance[T]())
+	if specErr != nil {
+		err = errors.Warning("sql: ancestors failed").WithCause(specErr)
+		return
+	}
+	identColumn, parentColumn, columnsErr := spec.TreeColumns()
+	if columnsErr != nil {
+		err = errors.Warning("sql: ancestors failed").WithCause(columnsErr)
+		return
+	}
+	nodes := make(map[any]int, len(items))
+	for i, item := range items {
+		nodes[identColumn.ReadValue(reflect.ValueOf(item)).Interface()] = i
+	}
+	// walk from node to top, then reverse
+	entries = make([]T, 0, len(items))
+	idv := reflect.ValueOf(id)
+	if identType := identColumn.ReadValue(reflect.ValueOf(items[0])).Type(); idv.Type() != identType && idv.Type().ConvertibleTo(identType) {
+		idv = idv.Convert(identType)
+	}
+	key := idv.Interface()
+	for len(entries) < len(items) {
+		i, exist := nodes[key]
+		if !exist {
+			break
+		}
+		entries = append(entries, items[i])
+		key = parentColumn.ReadValue(reflect.ValueOf(items[i])).Interface()
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return
+}
+
+func queryTreeEntries[T Table](ctx context.Context, query []byte, arguments []any, fields []string) (entries []T, err error) {
+	rows, queryErr := sql.Query(ctx, query, arguments...)
+	if queryErr != nil {
+		err = queryErr
+		return
+	}
+	entries, err = specifications.ScanRows[T](ctx, rows, fields)
+	_ = rows.Close()
+	return
+}
@@ -1,25 +1,42 @@
 package dac
 
 import (
+	stderrors "errors"
+	"fmt"
 	"github.com/aacfactory/errors"
 	"github.com/aacfactory/fns-contrib/databases/sql"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
 	"github.com/aacfactory/fns/context"
+	"net/http"
+	"reflect"
+	"strconv"
 )
 
 func Update[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
 	entries := []T{entry}
+	release, shardingErr := useEntriesSharding[T](ctx, entries)
+	if shardingErr != nil {
+		err = errors.Warning("sql: update failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	_, query, arguments, buildErr := specifications.BuildUpdate[T](ctx, entries)
 	if buildErr != nil {
 		err = errors.Warning("sql: update failed").WithCause(buildErr)
 		return
 	}
-	result, execErr := sql.Execute(ctx, query, arguments...)
+	var result databases.Result
+	execErr := withEntryHistory[T](ctx, specifications.HistoryUpdateOp, entries[0], func() (err error) {
+		result, err = sql.Execute(ctx, query, arguments...)
+		return
+	})
 	if execErr != nil {
 		err = errors.Warning("sql: update failed").WithCause(execErr)
 		return
 	}
+	invalidateCache[T](ctx)
 	if ok = result.RowsAffected == 1; ok {
 		verErr := specifications.TrySetupAuditVersion[T](ctx, entries)
 		if verErr != nil {
@@ -27,12 +44,183 @@ func Update[T Table](ctx context.Context, entry T) (v T, ok bool, err error) {
 			return
 		}
 		v = entries[0]
+		return
 	}
+	err = checkOptimisticLock[T](ctx, entry)
 	return
 }
 
+// checkOptimisticLock
+// returns ConflictError when row exists but version is not matched.
+func checkOptimisticLock[T Table](ctx context.Context, entry T) (err error) {
+	spec, specErr := specifications.GetSpecification(ctx, entry)
+	if specErr != nil {
+		err = errors.Warning("sql: update failed").WithCause(specErr)
+		return
+	}
+	aol, hasAol := spec.AuditVersion()
+	if !hasAol {
+		return
+	}
+	pk, hasPk := spec.Pk()
+	if !hasPk {
+		return
+	}
+	id, idErr := spec.ArgumentByField(entry, pk.Field)
+	if idErr != nil {
+		err = errors.Warning("sql: update failed").WithCause(idErr)
+		return
+	}
+	current, has, currentErr := One[T](ctx, Conditions(conditions.New(conditions.Eq(pk.Field, id))), NoCache())
+	if currentErr != nil {
+		err = errors.Warning("sql: update failed").WithCause(currentErr)
+		return
+	}
+	if !has {
+		return
+	}
+	expected, expectedErr := versionOf(aol.ReadValue(reflect.Indirect(reflect.ValueOf(entry))))
+	if expectedErr != nil {
+		err = errors.Warning("sql: update failed").WithCause(expectedErr).WithMeta("field", aol.Field)
+		return
+	}
+	actual, actualErr := versionOf(aol.ReadValue(reflect.ValueOf(current)))
+	if actualErr != nil {
+		err = errors.Warning("sql: update failed").WithCause(actualErr).WithMeta("field", aol.Field)
+		return
+	}
+	if expected == actual {
+		return
+	}
+	err = &ConflictError{
+		CodeError: errors.New(http.StatusConflict, conflictErrorName, "sql: optimistic lock conflict").
+			WithMeta("table", spec.Key).
+			WithMeta("id", fmt.Sprintf("%v", id)).
+			WithMeta("expected", strconv.FormatInt(expected, 10)).
+			WithMeta("current", strconv.FormatInt(actual, 10)),
+		Table:    spec.Key,
+		Id:       id,
+		Expected: expected,
+		Current:  actual,
+	}
+	return
+}
+
+func versionOf(rv reflect.Value) (version int64, err error) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		version = rv.Int()
+		break
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		version = int64(rv.Uint())
+		break
+	default:
+		err = fmt.Errorf("type of version must be integer, but it is %s", rv.Type())
+		break
+	}
+	return
+}
+
+const (
+	conflictErrorName = "***CONFLICT***"
+)
+
+var (
+	ErrOptimisticLockConflict = errors.Warning("sql: optimistic lock conflict")
+)
+
+// ConflictError
+// version of entry is not matched with version in database.
+// it is a errors.CodeError whose code is 409, so code is kept when it is returned to other endpoint.
+type ConflictError struct {
+	errors.CodeError
+	Table    string
+	Id       any
+	Expected int64
+	Current  int64
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrOptimisticLockConflict
+}
+
+// IsConflict
+// check whether err is ConflictError or not.
+func IsConflict(err error) (conflict *ConflictError, ok bool) {
+	ok = stderrors.As(err, &conflict)
+	return
+}
+
+type UpdateRetryOptions struct {
+	retries int
+}
+
+type UpdateRetryOption func(options *UpdateRetryOptions)
+
+// MaxRetries
+// max times of retry when version is conflicted, default is 3.
+func MaxRetries(n int) UpdateRetryOption {
+	return func(options *UpdateRetryOptions) {
+		if n < 0 {
+			n = 0
+		}
+		options.retries = n
+	}
+}
+
+// UpdateWithRetry
+// load entry by pk, mutate it and update it, when version is conflicted, then reload, mutate and update again.
+func UpdateWithRetry[T Table](ctx context.Context, pk any, mutate func(entry *T) error, options ...UpdateRetryOption) (v T, err error) {
+	opt := UpdateRetryOptions{
+		retries: 3,
+	}
+	for _, option := range options {
+		option(&opt)
+	}
+	spec, specErr := specifications.GetSpecification(ctx, specifications.Instance[T]())
+	if specErr != nil {
+		err = errors.Warning("sql: update with retry failed").WithCause(specErr)
+		return
+	}
+	pkColumn, hasPk := spec.Pk()
+	if !hasPk {
+		err = errors.Warning("sql: update with retry failed").WithCause(fmt.Errorf("pk is required")).WithMeta("table", spec.Key)
+		return
+	}
+	for i := 0; ; i++ {
+		entry, has, loadErr := One[T](ctx, Conditions(conditions.New(conditions.Eq(pkColumn.Field, pk))), NoCache())
+		if loadErr != nil {
+			err = errors.Warning("sql: update with retry failed").WithCause(loadErr)
+			return
+		}
+		if !has {
+			err = errors.Warning("sql: update with retry failed").WithCause(fmt.Errorf("entry was not found")).WithMeta("table", spec.Key)
+			return
+		}
+		mutateErr := mutate(&entry)
+		if mutateErr != nil {
+			err = errors.Warning("sql: update with retry failed").WithCause(mutateErr)
+			return
+		}
+		updated, ok, updateErr := Update[T](ctx, entry)
+		if updateErr != nil {
+			if _, conflicted := IsConflict(updateErr); conflicted && i < opt.retries {
+				continue
+			}
+			err = updateErr
+			return
+		}
+		if !ok {
+			err = errors.Warning("sql: update with retry failed").WithCause(fmt.Errorf("entry was not found")).WithMeta("table", spec.Key)
+			return
+		}
+		v = updated
+		return
+	}
+}
+
 func Field(name string, value any) FieldValues {
-	return FieldValues{{name, value}}
+	return FieldValues{{Name: name, Value: value}}
 }
 
 type FieldValues []specifications.FieldValue
@@ -44,16 +232,27 @@ func (fields FieldValues) Field(name string, value any) FieldValues {
 }
 
 func UpdateFields[T Table](ctx context.Context, fields FieldValues, cond conditions.Condition) (affected int64, err error) {
+	release, shardingErr := useConditionSharding[T](ctx, cond, sql.ShardingMerge{})
+	if shardingErr != nil {
+		err = errors.Warning("sql: update fields failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
 	_, query, arguments, buildErr := specifications.BuildUpdateFields[T](ctx, fields, specifications.Condition{Condition: cond})
 	if buildErr != nil {
 		err = errors.Warning("sql: update fields failed").WithCause(buildErr)
 		return
 	}
-	result, execErr := sql.Execute(ctx, query, arguments...)
+	var result databases.Result
+	execErr := withConditionHistory[T](ctx, specifications.HistoryUpdateOp, cond, func() (err error) {
+		result, err = sql.Execute(ctx, query, arguments...)
+		return
+	})
 	if execErr != nil {
 		err = errors.Warning("sql: update fields failed").WithCause(execErr)
 		return
 	}
+	invalidateCache[T](ctx)
 	affected = result.RowsAffected
 	return
 }
@@ -0,0 +1,23 @@
+package dac
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionOf(t *testing.T) {
+	values := []any{int64(3), int32(3), uint32(3), uint64(3)}
+	for _, value := range values {
+		version, err := versionOf(reflect.ValueOf(value))
+		if err != nil {
+			t.Errorf("%+v", err)
+			return
+		}
+		if version != 3 {
+			t.Errorf("version of %T must be 3, got %d", value, version)
+		}
+	}
+	if _, err := versionOf(reflect.ValueOf("3")); err == nil {
+		t.Errorf("version of string must be failed")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/aacfactory/fns-contrib/databases/sql"
 	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
 	"github.com/aacfactory/fns/context"
+	"time"
 )
 
 func Views[V View](ctx context.Context, offset int, length int, options ...QueryOption) (entries []V, err error) {
@@ -13,18 +14,26 @@ func Views[V View](ctx context.Context, offset int, length int, options ...Query
 		option(&opt)
 	}
 
+	shardOffset, shardLength, release, shardingErr := useQuerySharding[V](ctx, opt.cond, opt.orders, len(opt.groupBy.Bys) > 0, offset, length)
+	if shardingErr != nil {
+		err = errors.Warning("sql: view failed").WithCause(shardingErr)
+		return
+	}
+	defer release()
+
 	_, query, arguments, fields, buildErr := specifications.BuildView[V](
 		ctx,
 		specifications.Condition{Condition: opt.cond},
 		specifications.Orders(opt.orders),
 		specifications.GroupBy{GroupBy: opt.groupBy},
-		offset, length,
+		shardOffset, shardLength,
 	)
 	if buildErr != nil {
 		err = errors.Warning("sql: view failed").WithCause(buildErr)
 		return
 	}
 
+	beg := time.Now()
 	rows, queryErr := sql.Query(ctx, query, arguments...)
 	if queryErr != nil {
 		err = errors.Warning("sql: view failed").WithCause(queryErr)
@@ -37,6 +46,7 @@ func Views[V View](ctx context.Context, offset int, length int, options ...Query
 		err = errors.Warning("sql: view failed").WithCause(err)
 		return
 	}
+	explainSlowQuery[V](ctx, query, arguments, beg)
 	return
 }
 
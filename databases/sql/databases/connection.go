@@ -0,0 +1,302 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/logs"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// connectionDrainInterval
+	// interval of checking in-flight transactions of old pool.
+	connectionDrainInterval = time.Second
+)
+
+type connectionOptions struct {
+	driver      string
+	dsn         string
+	maxIdles    int
+	maxOpens    int
+	maxIdleTime time.Duration
+	maxLifetime time.Duration
+	statements  StatementsConfig
+	// drainTimeout
+	// old pool is closed after drainTimeout when it is replaced, and in-flight transactions on it are waited.
+	drainTimeout time.Duration
+	// sessions
+	// statements which are executed when a connection is opened
+	sessions [][]byte
+}
+
+// connection
+// pool of one dsn, when credentials are changed, it is reopened with new dsn,
+// and old pool is closed after drain timeout and in-flight transactions on it are finished.
+type connection struct {
+	log      logs.Logger
+	options  connectionOptions
+	provider CredentialProvider
+	value    atomic.Pointer[connectionValue]
+	locker   sync.Mutex
+}
+
+type connectionValue struct {
+	dsn        string
+	db         *sql.DB
+	statements *Statements
+	// transactions
+	// number of in-flight transactions
+	transactions atomic.Int64
+}
+
+// drain
+// wait timeout for statements, then wait in-flight transactions, then close.
+func (value *connectionValue) drain(log logs.Logger, timeout time.Duration) (err error) {
+	time.Sleep(timeout)
+	if value.transactions.Load() > 0 {
+		if log.WarnEnabled() {
+			log.Warn().With("sql", "connection").With("transactions", value.transactions.Load()).
+				Message("sql: old connection has in-flight transactions after drain timeout, it will be closed after they are finished")
+		}
+		ticker := time.NewTicker(connectionDrainInterval)
+		for value.transactions.Load() > 0 {
+			<-ticker.C
+		}
+		ticker.Stop()
+	}
+	err = value.close()
+	return
+}
+
+func (value *connectionValue) close() (err error) {
+	if value.statements != nil {
+		value.statements.Close()
+	}
+	err = value.db.Close()
+	return
+}
+
+func newConnection(log logs.Logger, options connectionOptions, provider CredentialProvider) (conn *connection, err error) {
+	conn = &connection{
+		log:      log,
+		options:  options,
+		provider: provider,
+		value:    atomic.Pointer[connectionValue]{},
+		locker:   sync.Mutex{},
+	}
+	dsn, dsnErr := conn.dsn()
+	if dsnErr != nil {
+		err = dsnErr
+		return
+	}
+	value, openErr := conn.open(dsn)
+	if openErr != nil {
+		err = openErr
+		return
+	}
+	conn.value.Store(value)
+	return
+}
+
+func (conn *connection) dsn() (dsn string, err error) {
+	dsn = conn.options.dsn
+	if conn.provider == nil {
+		return
+	}
+	dsn, err = conn.provider.DSN(dsn)
+	if err != nil {
+		err = errors.Warning("sql: get dsn from credential provider failed").WithCause(err).WithMeta("provider", conn.provider.Name())
+		return
+	}
+	return
+}
+
+func (conn *connection) open(dsn string) (value *connectionValue, err error) {
+	db, openErr := sql.Open(conn.options.driver, dsn)
+	if openErr != nil {
+		err = openErr
+		return
+	}
+	if len(conn.options.sessions) > 0 {
+		connector, connectorErr := newSessionConnector(db.Driver(), dsn, conn.options.sessions)
+		_ = db.Close()
+		if connectorErr != nil {
+			err = connectorErr
+			return
+		}
+		db = sql.OpenDB(connector)
+	}
+	if conn.options.maxIdles > 0 {
+		db.SetMaxIdleConns(conn.options.maxIdles)
+	}
+	if conn.options.maxOpens > 0 {
+		db.SetMaxOpenConns(conn.options.maxOpens)
+	}
+	if conn.options.maxIdleTime > 0 {
+		db.SetConnMaxIdleTime(conn.options.maxIdleTime)
+	}
+	if conn.options.maxLifetime > 0 {
+		db.SetConnMaxLifetime(conn.options.maxLifetime)
+	}
+	err = db.Ping()
+	if err != nil {
+		_ = db.Close()
+		return
+	}
+	value = &connectionValue{
+		dsn:        dsn,
+		db:         db,
+		statements: nil,
+	}
+	if conn.options.statements.Enable {
+		cacheSize := conn.options.statements.CacheSize
+		if cacheSize < 1 {
+			cacheSize = 1024
+		}
+		evictTimeoutSeconds := conn.options.statements.EvictTimeoutSeconds
+		if evictTimeoutSeconds < 1 {
+			evictTimeoutSeconds = 10
+		}
+		value.statements, err = NewStatements(conn.log, db, cacheSize, time.Duration(evictTimeoutSeconds)*time.Second)
+		if err != nil {
+			_ = db.Close()
+			return
+		}
+	}
+	return
+}
+
+// reload
+// reopen pool when dsn is changed, prepared statements are rebuilt on new pool.
+func (conn *connection) reload() (err error) {
+	conn.locker.Lock()
+	defer conn.locker.Unlock()
+	dsn, dsnErr := conn.dsn()
+	if dsnErr != nil {
+		err = dsnErr
+		return
+	}
+	if dsn == conn.value.Load().dsn {
+		return
+	}
+	value, openErr := conn.open(dsn)
+	if openErr != nil {
+		err = errors.Warning("sql: reload connection failed").WithCause(openErr)
+		return
+	}
+	old := conn.value.Swap(value)
+	go func(log logs.Logger, old *connectionValue, timeout time.Duration) {
+		if closeErr := old.drain(log, timeout); closeErr != nil {
+			if log.WarnEnabled() {
+				log.Warn().With("sql", "connection").Cause(closeErr).Message("sql: close old connection failed")
+			}
+		}
+	}(conn.log, old, conn.options.drainTimeout)
+	return
+}
+
+func (conn *connection) begin(ctx context.Context, options TransactionOptions) (tx Transaction, err error) {
+	value := conn.value.Load()
+	value.transactions.Add(1)
+	core, begErr := value.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.IsolationLevel(options.Isolation),
+		ReadOnly:  options.Readonly,
+	})
+	if begErr != nil {
+		value.transactions.Add(-1)
+		err = begErr
+		return
+	}
+	tx = &DefaultTransaction{
+		core:       core,
+		prepare:    value.statements != nil,
+		statements: value.statements,
+		readonly:   options.Readonly,
+		done: func() {
+			value.transactions.Add(-1)
+		},
+	}
+	return
+}
+
+func (conn *connection) query(ctx context.Context, query []byte, args []any) (rows Rows, err error) {
+	value := conn.value.Load()
+	var r *sql.Rows
+	if value.statements != nil {
+		stmt, prepareErr := value.statements.Get(query)
+		if prepareErr != nil {
+			err = prepareErr
+			return
+		}
+		r, err = stmt.QueryContext(ctx, args...)
+		if err != nil {
+			if errors.Contains(err, ErrStatementClosed) {
+				rows, err = conn.query(ctx, query, args)
+				return
+			}
+			return
+		}
+	} else {
+		r, err = value.db.QueryContext(ctx, bytex.ToString(query), args...)
+		if err != nil {
+			return
+		}
+	}
+	rows = &DefaultRows{
+		core: r,
+	}
+	return
+}
+
+func (conn *connection) execute(ctx context.Context, query []byte, args []any) (result Result, err error) {
+	value := conn.value.Load()
+	var r sql.Result
+	if value.statements != nil {
+		stmt, prepareErr := value.statements.Get(query)
+		if prepareErr != nil {
+			err = prepareErr
+			return
+		}
+		r, err = stmt.ExecContext(ctx, args...)
+		if err != nil {
+			if errors.Contains(err, ErrStatementClosed) {
+				result, err = conn.execute(ctx, query, args)
+				return
+			}
+			return
+		}
+	} else {
+		r, err = value.db.ExecContext(ctx, bytex.ToString(query), args...)
+		if err != nil {
+			return
+		}
+	}
+
+	rowsAffected, rowsAffectedErr := r.RowsAffected()
+	if rowsAffectedErr != nil {
+		err = rowsAffectedErr
+		return
+	}
+
+	lastInsertId, lastInsertIdErr := r.LastInsertId()
+	if lastInsertIdErr != nil {
+		lastInsertId = -1
+	}
+
+	result = Result{
+		LastInsertId: lastInsertId,
+		RowsAffected: rowsAffected,
+	}
+	return
+}
+
+func (conn *connection) close() (err error) {
+	conn.locker.Lock()
+	err = conn.value.Load().close()
+	conn.locker.Unlock()
+	return
+}
@@ -0,0 +1,134 @@
+package databases
+
+import (
+	"fmt"
+	"github.com/aacfactory/configures"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/json"
+	"github.com/aacfactory/logs"
+	"strings"
+	"time"
+)
+
+type CredentialProviderOptions struct {
+	Log    logs.Logger
+	Config configures.Config
+}
+
+// CredentialProvider
+// provides dsn with current credentials, so credentials can be rotated without restart.
+// when credentials are changed, pools are reopened with new dsn, and old pools are closed after drain timeout and in-flight transactions on them are finished.
+type CredentialProvider interface {
+	Name() string
+	Construct(options CredentialProviderOptions) (err error)
+	// DSN
+	// returns dsn with current credentials, param is dsn in config.
+	DSN(dsn string) (v string, err error)
+	// Changed
+	// receives when credentials are changed.
+	Changed() <-chan struct{}
+	Close()
+}
+
+type CredentialsConfig struct {
+	// Provider
+	// file or registered one, empty means dsn in config is used directly.
+	Provider string          `json:"provider"`
+	Options  json.RawMessage `json:"options"`
+	// DrainTimeoutSeconds
+	// old pool is closed after it when pool is reopened, default is 60.
+	// in-flight transactions on old pool are waited after it, so they are not broken.
+	DrainTimeoutSeconds int `json:"drainTimeoutSeconds"`
+}
+
+func (config CredentialsConfig) DrainTimeout() time.Duration {
+	if config.DrainTimeoutSeconds < 1 {
+		return 60 * time.Second
+	}
+	return time.Duration(config.DrainTimeoutSeconds) * time.Second
+}
+
+var (
+	credentialProviders = map[string]func() CredentialProvider{
+		"file": func() CredentialProvider { return &fileCredentialProvider{} },
+	}
+)
+
+// RegisterCredentialProvider
+// register custom credential provider, it must be called before service constructed.
+func RegisterCredentialProvider(name string, maker func() CredentialProvider) {
+	name = strings.TrimSpace(name)
+	if name == "" || maker == nil {
+		return
+	}
+	credentialProviders[name] = maker
+}
+
+// newCredentialProvider
+// returns nil when provider of config is empty.
+func newCredentialProvider(log logs.Logger, config CredentialsConfig) (provider CredentialProvider, err error) {
+	name := strings.TrimSpace(config.Provider)
+	if name == "" {
+		return
+	}
+	maker, has := credentialProviders[name]
+	if !has {
+		err = errors.Warning("sql: new credential provider failed").WithCause(fmt.Errorf("%s provider was not found", name))
+		return
+	}
+	if len(config.Options) == 0 {
+		config.Options = []byte{'{', '}'}
+	}
+	providerConfig, providerConfigErr := configures.NewJsonConfig(config.Options)
+	if providerConfigErr != nil {
+		err = errors.Warning("sql: new credential provider failed").WithCause(providerConfigErr).WithMeta("provider", name)
+		return
+	}
+	provider = maker()
+	err = provider.Construct(CredentialProviderOptions{
+		Log:    log.With("credentials", name),
+		Config: providerConfig,
+	})
+	if err != nil {
+		provider = nil
+		err = errors.Warning("sql: new credential provider failed").WithCause(err).WithMeta("provider", name)
+		return
+	}
+	return
+}
+
+// watchCredentials
+// reload connections when credentials are changed, returns stop function.
+func watchCredentials(log logs.Logger, provider CredentialProvider, connections []*connection) (stop func()) {
+	if provider == nil {
+		stop = func() {}
+		return
+	}
+	done := make(chan struct{})
+	go func(log logs.Logger, provider CredentialProvider, connections []*connection, done chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case <-provider.Changed():
+				for _, conn := range connections {
+					if reloadErr := conn.reload(); reloadErr != nil {
+						if log.WarnEnabled() {
+							log.Warn().With("credentials", provider.Name()).Cause(reloadErr).Message("sql: reload connection failed")
+						}
+						continue
+					}
+				}
+				if log.DebugEnabled() {
+					log.Debug().With("credentials", provider.Name()).Message("sql: connections were reloaded")
+				}
+				break
+			}
+		}
+	}(log, provider, connections, done)
+	stop = func() {
+		close(done)
+		provider.Close()
+	}
+	return
+}
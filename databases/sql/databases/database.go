@@ -14,6 +14,9 @@ type Result struct {
 type Options struct {
 	Log    logs.Logger
 	Config configures.Config
+	// Sessions
+	// statements which are executed when a connection of pool is opened, such as SET statement_timeout of postgres.
+	Sessions [][]byte
 }
 
 type Database interface {
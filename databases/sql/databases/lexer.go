@@ -0,0 +1,167 @@
+package databases
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type QueryTokenKind int
+
+const (
+	// TextQueryToken
+	// sql text, includes `::` casts and `@@var` system variables of mysql.
+	TextQueryToken QueryTokenKind = iota
+	// QuotedQueryToken
+	// string literal, quoted ident and postgres dollar quoted string.
+	QuotedQueryToken
+	CommentQueryToken
+	// PlaceholderQueryToken
+	// `?`
+	PlaceholderQueryToken
+	// NumberedPlaceholderQueryToken
+	// `$n` and `?n`
+	NumberedPlaceholderQueryToken
+	// NamedQueryToken
+	// `:name` and `@name`
+	NamedQueryToken
+)
+
+type QueryToken struct {
+	Kind  QueryTokenKind
+	Value []byte
+}
+
+// LexQuery
+// split query into tokens, string literals, quoted idents, comments and postgres dollar quoted strings are not split.
+// doubled quote is skipped in quotes, and backslash escaped quote is skipped when backslash is true, such as mysql.
+// postgres E'...' string accepts backslash escapes too.
+func LexQuery(query []byte, backslash bool) (tokens []QueryToken, err error) {
+	size := len(query)
+	text := 0
+	emit := func(begin int, end int, kind QueryTokenKind) {
+		if text < begin {
+			tokens = append(tokens, QueryToken{Kind: TextQueryToken, Value: query[text:begin]})
+		}
+		tokens = append(tokens, QueryToken{Kind: kind, Value: query[begin:end]})
+		text = end
+	}
+	for i := 0; i < size; i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			escaped := c != '`' && (backslash || (c == '\'' && i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') && (i == 1 || !isIdentBody(query[i-2]))))
+			end := closingQuote(query[i+1:], c, escaped)
+			if end < 0 {
+				err = fmt.Errorf("quote at %d is not closed", i)
+				return
+			}
+			emit(i, i+end+2, QuotedQueryToken)
+			i = i + end + 1
+			break
+		case c == '$' && i+1 < size && (query[i+1] == '$' || isIdentHead(query[i+1])) && (i == 0 || !isIdentBody(query[i-1])):
+			// $$...$$ or $tag$...$tag$
+			j := i + 1
+			for j < size && isIdentBody(query[j]) {
+				j++
+			}
+			if j >= size || query[j] != '$' {
+				i = j - 1
+				break
+			}
+			tag := query[i : j+1]
+			end := bytes.Index(query[j+1:], tag)
+			if end < 0 {
+				err = fmt.Errorf("dollar quoted string at %d is not closed", i)
+				return
+			}
+			end = j + 1 + end + len(tag)
+			emit(i, end, QuotedQueryToken)
+			i = end - 1
+			break
+		case c == '@' && i+1 < size && query[i+1] == '@':
+			j := i + 2
+			for j < size && (isIdentBody(query[j]) || query[j] == '.') {
+				j++
+			}
+			i = j - 1
+			break
+		case c == '-' && i+1 < size && query[i+1] == '-':
+			end := bytes.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = size - i
+			}
+			emit(i, i+end, CommentQueryToken)
+			i = i + end - 1
+			break
+		case c == '/' && i+1 < size && query[i+1] == '*':
+			end := bytes.Index(query[i+2:], []byte("*/"))
+			if end < 0 {
+				err = fmt.Errorf("comment at %d is not closed", i)
+				return
+			}
+			emit(i, i+end+4, CommentQueryToken)
+			i = i + end + 3
+			break
+		case c == ':' && i+1 < size && query[i+1] == ':':
+			i++
+			break
+		case (c == '$' || c == '?') && i+1 < size && isDigit(query[i+1]) && (i == 0 || !isIdentBody(query[i-1])):
+			j := i + 1
+			for j < size && isDigit(query[j]) {
+				j++
+			}
+			emit(i, j, NumberedPlaceholderQueryToken)
+			i = j - 1
+			break
+		case c == '?':
+			emit(i, i+1, PlaceholderQueryToken)
+			break
+		case (c == ':' || c == '@') && i+1 < size && isIdentHead(query[i+1]) && (i == 0 || !isIdentBody(query[i-1])):
+			j := i + 1
+			for j < size && isIdentBody(query[j]) {
+				j++
+			}
+			emit(i, j, NamedQueryToken)
+			i = j - 1
+			break
+		default:
+			break
+		}
+	}
+	if text < size {
+		tokens = append(tokens, QueryToken{Kind: TextQueryToken, Value: query[text:]})
+	}
+	return
+}
+
+// closingQuote
+// returns index of closing quote, doubled quote is skipped, and backslash escaped quote is skipped when escaped is true.
+func closingQuote(p []byte, quote byte, escaped bool) int {
+	for i := 0; i < len(p); i++ {
+		if escaped && p[i] == '\\' {
+			i++
+			continue
+		}
+		if p[i] != quote {
+			continue
+		}
+		if i+1 < len(p) && p[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentHead(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentBody(c byte) bool {
+	return isIdentHead(c) || isDigit(c)
+}
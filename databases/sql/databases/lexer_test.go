@@ -0,0 +1,52 @@
+package databases_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"testing"
+)
+
+func TestLexQuery(t *testing.T) {
+	query := `SELECT "A"::text, $$:x$$, @@session.time_zone /* :y */ FROM T WHERE A = 'it''s :z' AND B = :b AND C = $1 AND D = ? -- :w`
+	tokens, err := databases.LexQuery([]byte(query), false)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	kinds := map[databases.QueryTokenKind][]string{}
+	joined := ""
+	for _, token := range tokens {
+		kinds[token.Kind] = append(kinds[token.Kind], string(token.Value))
+		joined += string(token.Value)
+	}
+	if joined != query {
+		t.Errorf("tokens must cover query, got %s", joined)
+	}
+	if named := kinds[databases.NamedQueryToken]; len(named) != 1 || named[0] != ":b" {
+		t.Errorf("named params are not matched, %v", named)
+	}
+	if numbered := kinds[databases.NumberedPlaceholderQueryToken]; len(numbered) != 1 || numbered[0] != "$1" {
+		t.Errorf("numbered placeholders are not matched, %v", numbered)
+	}
+	if placeholders := kinds[databases.PlaceholderQueryToken]; len(placeholders) != 1 {
+		t.Errorf("placeholders are not matched, %v", placeholders)
+	}
+	if comments := kinds[databases.CommentQueryToken]; len(comments) != 2 {
+		t.Errorf("comments are not matched, %v", comments)
+	}
+}
+
+func TestLexQuery_Backslash(t *testing.T) {
+	query := []byte(`SELECT 'it\'s :x' WHERE A = :a`)
+	if _, err := databases.LexQuery(query, false); err == nil {
+		t.Errorf("quote must be not closed in standard sql")
+	}
+	tokens, err := databases.LexQuery(query, true)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	last := tokens[len(tokens)-1]
+	if last.Kind != databases.NamedQueryToken || string(last.Value) != ":a" {
+		t.Errorf("last token must be :a, got %s", last.Value)
+	}
+}
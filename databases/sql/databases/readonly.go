@@ -0,0 +1,99 @@
+package databases
+
+import (
+	"bytes"
+)
+
+var (
+	writeKeywords = [][]byte{
+		[]byte("INSERT"), []byte("UPDATE"), []byte("DELETE"), []byte("MERGE"), []byte("UPSERT"), []byte("REPLACE"),
+		[]byte("CREATE"), []byte("ALTER"), []byte("DROP"), []byte("TRUNCATE"), []byte("RENAME"),
+		[]byte("GRANT"), []byte("REVOKE"),
+	}
+	cteWriteKeywords = [][]byte{
+		[]byte("INSERT"), []byte("UPDATE"), []byte("DELETE"), []byte("MERGE"),
+	}
+)
+
+// IsWriteStatement
+// check whether query writes or not, such as INSERT ... RETURNING and WITH ... (DELETE ... RETURNING) SELECT.
+// keywords in quotes and comments are skipped, SELECT ... FOR UPDATE is not write statement.
+func IsWriteStatement(query []byte) bool {
+	words := statementKeywords(query)
+	if len(words) == 0 {
+		return false
+	}
+	if !bytes.EqualFold(words[0], []byte("WITH")) {
+		return containsKeyword(writeKeywords, words[0])
+	}
+	for i := 1; i < len(words); i++ {
+		if !containsKeyword(cteWriteKeywords, words[i]) {
+			continue
+		}
+		if bytes.EqualFold(words[i], []byte("UPDATE")) {
+			// FOR UPDATE, FOR NO KEY UPDATE and ON UPDATE
+			prev := words[i-1]
+			if bytes.EqualFold(prev, []byte("FOR")) || bytes.EqualFold(prev, []byte("KEY")) || bytes.EqualFold(prev, []byte("ON")) {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func containsKeyword(keywords [][]byte, word []byte) bool {
+	for _, keyword := range keywords {
+		if bytes.EqualFold(keyword, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// statementKeywords
+// returns unquoted words of query.
+func statementKeywords(query []byte) (words [][]byte) {
+	n := len(query)
+	for i := 0; i < n; {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := bytes.IndexByte(query[i+1:], c)
+			if end < 0 {
+				return
+			}
+			i = i + end + 2
+			break
+		case c == '-' && i+1 < n && query[i+1] == '-':
+			end := bytes.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return
+			}
+			i = i + end + 1
+			break
+		case c == '/' && i+1 < n && query[i+1] == '*':
+			end := bytes.Index(query[i+2:], []byte("*/"))
+			if end < 0 {
+				return
+			}
+			i = i + end + 4
+			break
+		case isKeywordByte(c):
+			begin := i
+			for i < n && (isKeywordByte(query[i]) || (query[i] >= '0' && query[i] <= '9') || query[i] == '$') {
+				i++
+			}
+			words = append(words, query[begin:i])
+			break
+		default:
+			i++
+			break
+		}
+	}
+	return
+}
+
+func isKeywordByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
@@ -0,0 +1,34 @@
+package databases_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"testing"
+)
+
+func TestIsWriteStatement(t *testing.T) {
+	cases := map[string]bool{
+		`SELECT * FROM "USER"`:                                                false,
+		`  select 1`:                                                          false,
+		`SELECT * FROM "USER" FOR UPDATE`:                                     false,
+		`SELECT 'INSERT' AS "DELETE" FROM "UPDATE"`:                           false,
+		`/* INSERT */ SELECT 1`:                                               false,
+		"-- DELETE\nSELECT 1":                                                 false,
+		`SET TRANSACTION READ ONLY`:                                           false,
+		`SET LOCAL statement_timeout TO 100`:                                  false,
+		`INSERT INTO "USER" ("NAME") VALUES ($1) RETURNING "ID"`:              true,
+		` update "USER" SET "NAME" = $1 RETURNING "ID"`:                       true,
+		`DELETE FROM "USER" RETURNING "ID"`:                                   true,
+		`REPLACE INTO user (name) VALUES (?)`:                                 true,
+		`/* SELECT */ INSERT INTO t VALUES (1)`:                               true,
+		`WITH "X" AS (SELECT 1) SELECT * FROM "X"`:                            false,
+		`WITH "X" AS (SELECT * FROM "T" FOR NO KEY UPDATE) SELECT * FROM "X"`: false,
+		`WITH "X" AS (DELETE FROM "T" RETURNING *) SELECT * FROM "X"`:         true,
+		`WITH "X" AS (SELECT 1) UPDATE "T" SET "A" = 1 RETURNING "A"`:         true,
+		``: false,
+	}
+	for query, expected := range cases {
+		if got := databases.IsWriteStatement([]byte(query)); got != expected {
+			t.Errorf("%s: expected %v, got %v", query, expected, got)
+		}
+	}
+}
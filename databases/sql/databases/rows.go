@@ -18,6 +18,12 @@ type Rows interface {
 	Close() error
 }
 
+func NewRows(rows *sql.Rows) Rows {
+	return &DefaultRows{
+		core: rows,
+	}
+}
+
 type DefaultRows struct {
 	core *sql.Rows
 }
@@ -0,0 +1,93 @@
+package databases
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/aacfactory/errors"
+)
+
+func newSessionConnector(d driver.Driver, dsn string, sessions [][]byte) (connector driver.Connector, err error) {
+	var core driver.Connector
+	if dc, ok := d.(driver.DriverContext); ok {
+		core, err = dc.OpenConnector(dsn)
+		if err != nil {
+			err = errors.Warning("sql: open connector failed").WithCause(err)
+			return
+		}
+	} else {
+		core = &dsnConnector{
+			dsn:    dsn,
+			driver: d,
+		}
+	}
+	connector = &sessionConnector{
+		core:     core,
+		sessions: sessions,
+	}
+	return
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (connector *dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return connector.driver.Open(connector.dsn)
+}
+
+func (connector *dsnConnector) Driver() driver.Driver {
+	return connector.driver
+}
+
+// sessionConnector
+// executes session statements when a connection is opened, then settings of session are valid in whole life of connection.
+type sessionConnector struct {
+	core     driver.Connector
+	sessions [][]byte
+}
+
+func (connector *sessionConnector) Connect(ctx context.Context) (conn driver.Conn, err error) {
+	conn, err = connector.core.Connect(ctx)
+	if err != nil {
+		return
+	}
+	for _, session := range connector.sessions {
+		if err = executeSession(ctx, conn, string(session)); err != nil {
+			_ = conn.Close()
+			conn = nil
+			err = errors.Warning("sql: execute session statement failed").WithCause(err).WithMeta("query", string(session))
+			return
+		}
+	}
+	return
+}
+
+func (connector *sessionConnector) Driver() driver.Driver {
+	return connector.core.Driver()
+}
+
+func executeSession(ctx context.Context, conn driver.Conn, query string) (err error) {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err = execer.ExecContext(ctx, query, nil)
+		if err != driver.ErrSkip {
+			return
+		}
+	}
+	var stmt driver.Stmt
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = conn.Prepare(query)
+	}
+	if err != nil {
+		return
+	}
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+	} else {
+		_, err = stmt.Exec(nil)
+	}
+	_ = stmt.Close()
+	return
+}
@@ -0,0 +1,61 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type sessionTestDriver struct {
+	locker  *sync.Mutex
+	queries *[]string
+}
+
+func (d sessionTestDriver) Open(_ string) (driver.Conn, error) {
+	return sessionTestConn{driver: d}, nil
+}
+
+type sessionTestConn struct {
+	driver sessionTestDriver
+}
+
+func (conn sessionTestConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	conn.driver.locker.Lock()
+	*conn.driver.queries = append(*conn.driver.queries, query)
+	conn.driver.locker.Unlock()
+	return driver.RowsAffected(0), nil
+}
+
+func (conn sessionTestConn) Prepare(_ string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (conn sessionTestConn) Close() error {
+	return nil
+}
+
+func (conn sessionTestConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestConnection_Sessions(t *testing.T) {
+	queries := make([]string, 0, 1)
+	locker := new(sync.Mutex)
+	sql.Register("session_test", sessionTestDriver{locker: locker, queries: &queries})
+	conn, err := newConnection(nil, connectionOptions{
+		driver:   "session_test",
+		sessions: [][]byte{[]byte("SET statement_timeout = 2000")},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.close()
+	locker.Lock()
+	defer locker.Unlock()
+	if len(queries) != 1 || queries[0] != "SET statement_timeout = 2000" {
+		t.Errorf("session must be executed when connection is opened, got %v", queries)
+	}
+}
@@ -0,0 +1,507 @@
+package databases
+
+import (
+	"context"
+	"fmt"
+	"github.com/aacfactory/configures"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/json"
+	"github.com/aacfactory/logs"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrCrossShardTransaction = errors.Warning("sql: cross shard transaction is not allowed")
+	ErrCrossShardExecute     = errors.Warning("sql: cross shard execute is not allowed outside transaction")
+)
+
+type shardingRouteContextKey struct{}
+
+// ShardingRoute
+// route of statement in sharding database.
+type ShardingRoute struct {
+	// Keys
+	// values of shard key column, statement is executed on all shards when it is empty.
+	Keys []any
+	// Single
+	// statement must be executed on only one shard, such as insert.
+	Single bool
+}
+
+func WithShardingRoute(ctx context.Context, route ShardingRoute) context.Context {
+	return context.WithValue(ctx, shardingRouteContextKey{}, route)
+}
+
+func LoadShardingRoute(ctx context.Context) (route ShardingRoute, has bool) {
+	route, has = ctx.Value(shardingRouteContextKey{}).(ShardingRoute)
+	return
+}
+
+// Sharding
+// database of shards, statement is routed by ShardingRoute in context.
+// when route has no keys, query is executed on all shards and rows of shards are concatenated,
+// and execute is executed on all shards in transactions of shards and affected rows are summed,
+// execute on more than one shards outside transaction is rejected unless AllowCrossShardTransaction is true.
+func Sharding() Database {
+	return &sharding{}
+}
+
+type ShardConfig struct {
+	Name string `json:"name"`
+	// Kind
+	// standalone, masterSlave or cluster
+	Kind    string          `json:"kind"`
+	Options json.RawMessage `json:"options"`
+}
+
+type ShardingConfig struct {
+	// Strategy
+	// hash, range, lookup or registered one, default is hash.
+	Strategy        string          `json:"strategy"`
+	StrategyOptions json.RawMessage `json:"strategyOptions"`
+	// AllowCrossShardTransaction
+	// transaction and execute outside transaction can use more than one shards, note: it is not atomic.
+	AllowCrossShardTransaction bool          `json:"allowCrossShardTransaction"`
+	Shards                     []ShardConfig `json:"shards"`
+}
+
+type sharding struct {
+	log                        logs.Logger
+	strategy                   ShardingStrategy
+	names                      []string
+	shards                     []Database
+	allowCrossShardTransaction bool
+}
+
+func (db *sharding) Name() string {
+	return "sharding"
+}
+
+func (db *sharding) Construct(options Options) (err error) {
+	db.log = options.Log
+	config := ShardingConfig{}
+	configErr := options.Config.As(&config)
+	if configErr != nil {
+		err = errors.Warning("sql: sharding database construct failed").WithCause(configErr)
+		return
+	}
+	if len(config.Shards) == 0 {
+		err = errors.Warning("sql: sharding database construct failed").WithCause(fmt.Errorf("no shards"))
+		return
+	}
+	for _, sc := range config.Shards {
+		name := strings.TrimSpace(sc.Name)
+		if name == "" {
+			err = errors.Warning("sql: sharding database construct failed").WithCause(fmt.Errorf("name of shard is required"))
+			return
+		}
+		for _, n := range db.names {
+			if n == name {
+				err = errors.Warning("sql: sharding database construct failed").WithCause(fmt.Errorf("shard name is duplicated")).WithMeta("shard", name)
+				return
+			}
+		}
+		var shard Database
+		switch strings.ToLower(strings.TrimSpace(sc.Kind)) {
+		case "", "standalone":
+			shard = Standalone()
+			break
+		case "masterslave":
+			shard = MasterSlave()
+			break
+		case "cluster":
+			shard = Cluster()
+			break
+		default:
+			err = errors.Warning("sql: sharding database construct failed").WithCause(fmt.Errorf("%s kind is not supported", sc.Kind)).WithMeta("shard", name)
+			return
+		}
+		if len(sc.Options) == 0 {
+			sc.Options = []byte{'{', '}'}
+		}
+		shardConfig, shardConfigErr := configures.NewJsonConfig(sc.Options)
+		if shardConfigErr != nil {
+			err = errors.Warning("sql: sharding database construct failed").WithCause(shardConfigErr).WithMeta("shard", name)
+			return
+		}
+		err = shard.Construct(Options{
+			Log:      db.log.With("shard", name),
+			Config:   shardConfig,
+			Sessions: options.Sessions,
+		})
+		if err != nil {
+			err = errors.Warning("sql: sharding database construct failed").WithCause(err).WithMeta("shard", name)
+			return
+		}
+		db.names = append(db.names, name)
+		db.shards = append(db.shards, shard)
+	}
+	strategyName := strings.TrimSpace(config.Strategy)
+	if strategyName == "" {
+		strategyName = "hash"
+	}
+	strategy, hasStrategy := getShardingStrategy(strategyName)
+	if !hasStrategy {
+		err = errors.Warning("sql: sharding database construct failed").WithCause(fmt.Errorf("%s strategy was not found", strategyName))
+		return
+	}
+	if len(config.StrategyOptions) == 0 {
+		config.StrategyOptions = []byte{'{', '}'}
+	}
+	strategyConfig, strategyConfigErr := configures.NewJsonConfig(config.StrategyOptions)
+	if strategyConfigErr != nil {
+		err = errors.Warning("sql: sharding database construct failed").WithCause(strategyConfigErr)
+		return
+	}
+	err = strategy.Construct(ShardingStrategyOptions{
+		Log:       db.log.With("strategy", strategyName),
+		Config:    strategyConfig,
+		Shards:    db.names,
+		Databases: db.shards,
+	})
+	if err != nil {
+		err = errors.Warning("sql: sharding database construct failed").WithCause(err)
+		return
+	}
+	db.strategy = strategy
+	db.allowCrossShardTransaction = config.AllowCrossShardTransaction
+	return
+}
+
+// route
+// returns sorted positions of shards.
+func (db *sharding) route(ctx context.Context) (positions []int, err error) {
+	route, has := LoadShardingRoute(ctx)
+	if !has || len(route.Keys) == 0 {
+		if has && route.Single && len(db.shards) > 1 {
+			err = errors.Warning("sql: route shard failed").WithCause(fmt.Errorf("shard key is required"))
+			return
+		}
+		positions = make([]int, len(db.shards))
+		for i := range positions {
+			positions[i] = i
+		}
+		return
+	}
+	for _, key := range route.Keys {
+		pos, shardErr := db.strategy.Shard(ctx, key)
+		if shardErr != nil {
+			err = errors.Warning("sql: route shard failed").WithCause(shardErr).WithMeta("key", fmt.Sprintf("%v", key))
+			return
+		}
+		if pos < 0 || pos >= len(db.shards) {
+			err = errors.Warning("sql: route shard failed").WithCause(fmt.Errorf("shard is out of range")).WithMeta("key", fmt.Sprintf("%v", key))
+			return
+		}
+		exist := false
+		for _, position := range positions {
+			if position == pos {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			positions = append(positions, pos)
+		}
+	}
+	if route.Single && len(positions) > 1 {
+		err = errors.Warning("sql: route shard failed").WithCause(fmt.Errorf("keys of statement are in different shards"))
+		return
+	}
+	sort.Ints(positions)
+	return
+}
+
+func (db *sharding) Begin(_ context.Context, options TransactionOptions) (tx Transaction, err error) {
+	tx = &shardingTransaction{
+		db:         db,
+		options:    options,
+		crossShard: db.allowCrossShardTransaction || options.CrossShard,
+		positions:  make([]int, 0, 1),
+		txs:        make([]Transaction, 0, 1),
+	}
+	return
+}
+
+func (db *sharding) Query(ctx context.Context, query []byte, args []any) (rows Rows, err error) {
+	positions, routeErr := db.route(ctx)
+	if routeErr != nil {
+		err = routeErr
+		return
+	}
+	if len(positions) == 1 {
+		rows, err = db.shards[positions[0]].Query(ctx, query, args)
+		return
+	}
+	parts := make([]Rows, len(positions))
+	errs := make([]error, len(positions))
+	wg := new(sync.WaitGroup)
+	for i, position := range positions {
+		wg.Add(1)
+		go func(i int, shard Database) {
+			parts[i], errs[i] = shard.Query(ctx, query, args)
+			wg.Done()
+		}(i, db.shards[position])
+	}
+	wg.Wait()
+	for i, queryErr := range errs {
+		if queryErr != nil {
+			for _, part := range parts {
+				if part != nil {
+					_ = part.Close()
+				}
+			}
+			err = errors.Warning("sql: sharding query failed").WithCause(queryErr).WithMeta("shard", db.names[positions[i]])
+			return
+		}
+	}
+	rows = NewMultiRows(parts)
+	return
+}
+
+func (db *sharding) Execute(ctx context.Context, query []byte, args []any) (result Result, err error) {
+	positions, routeErr := db.route(ctx)
+	if routeErr != nil {
+		err = routeErr
+		return
+	}
+	if len(positions) == 1 {
+		result, err = db.shards[positions[0]].Execute(ctx, query, args)
+		return
+	}
+	if !db.allowCrossShardTransaction {
+		err = ErrCrossShardExecute.WithMeta("shards", strconv.Itoa(len(positions)))
+		return
+	}
+	result, err = db.executeInTransactions(ctx, positions, query, args)
+	return
+}
+
+// executeInTransactions
+// execute in transaction of each shard, all transactions are rolled back when one of executes failed,
+// and transactions are committed one by one, when commit failed, applied shards are in meta of error and rest are rolled back.
+func (db *sharding) executeInTransactions(ctx context.Context, positions []int, query []byte, args []any) (result Result, err error) {
+	txs := make([]Transaction, 0, len(positions))
+	rollback := func(txs []Transaction) {
+		for _, tx := range txs {
+			_ = tx.Rollback()
+		}
+	}
+	result.LastInsertId = -1
+	for _, position := range positions {
+		tx, beginErr := db.shards[position].Begin(ctx, TransactionOptions{})
+		if beginErr != nil {
+			rollback(txs)
+			err = errors.Warning("sql: sharding execute failed").WithCause(beginErr).WithMeta("shard", db.names[position])
+			return
+		}
+		txs = append(txs, tx)
+		r, execErr := tx.Execute(ctx, query, args)
+		if execErr != nil {
+			rollback(txs)
+			err = errors.Warning("sql: sharding execute failed").WithCause(execErr).WithMeta("shard", db.names[position])
+			return
+		}
+		result.RowsAffected += r.RowsAffected
+	}
+	applied := make([]string, 0, len(positions))
+	for i, tx := range txs {
+		if cmtErr := tx.Commit(); cmtErr != nil {
+			rollback(txs[i+1:])
+			err = errors.Warning("sql: sharding execute failed").WithCause(cmtErr).
+				WithMeta("shard", db.names[positions[i]]).WithMeta("applied", strings.Join(applied, ","))
+			return
+		}
+		applied = append(applied, db.names[positions[i]])
+	}
+	return
+}
+
+func (db *sharding) Close(ctx context.Context) (err error) {
+	errs := errors.MakeErrors()
+	for _, shard := range db.shards {
+		if closeErr := shard.Close(ctx); closeErr != nil {
+			errs.Append(closeErr)
+		}
+	}
+	if len(errs) > 0 {
+		err = errors.Warning("sql: sharding database close failed").WithCause(errs.Error())
+		return
+	}
+	return
+}
+
+// shardingTransaction
+// transaction of shard is began when it is used first.
+type shardingTransaction struct {
+	db         *sharding
+	options    TransactionOptions
+	crossShard bool
+	positions  []int
+	txs        []Transaction
+}
+
+func (tx *shardingTransaction) use(ctx context.Context) (txs []Transaction, err error) {
+	positions, routeErr := tx.db.route(ctx)
+	if routeErr != nil {
+		err = routeErr
+		return
+	}
+	for _, position := range positions {
+		var target Transaction
+		for i, used := range tx.positions {
+			if used == position {
+				target = tx.txs[i]
+				break
+			}
+		}
+		if target == nil {
+			if len(tx.txs) > 0 && !tx.crossShard {
+				err = ErrCrossShardTransaction.WithMeta("shard", tx.db.names[position])
+				return
+			}
+			target, err = tx.db.shards[position].Begin(ctx, tx.options)
+			if err != nil {
+				err = errors.Warning("sql: sharding transaction begin failed").WithCause(err).WithMeta("shard", tx.db.names[position])
+				return
+			}
+			tx.positions = append(tx.positions, position)
+			tx.txs = append(tx.txs, target)
+		}
+		txs = append(txs, target)
+	}
+	return
+}
+
+func (tx *shardingTransaction) Commit() (err error) {
+	errs := errors.MakeErrors()
+	for i, target := range tx.txs {
+		if cmtErr := target.Commit(); cmtErr != nil {
+			errs.Append(errors.Warning("sql: sharding transaction commit failed").WithCause(cmtErr).WithMeta("shard", tx.db.names[tx.positions[i]]))
+		}
+	}
+	if len(errs) > 0 {
+		err = errs.Error()
+		return
+	}
+	return
+}
+
+func (tx *shardingTransaction) Rollback() (err error) {
+	errs := errors.MakeErrors()
+	for i, target := range tx.txs {
+		if rbErr := target.Rollback(); rbErr != nil {
+			errs.Append(errors.Warning("sql: sharding transaction rollback failed").WithCause(rbErr).WithMeta("shard", tx.db.names[tx.positions[i]]))
+		}
+	}
+	if len(errs) > 0 {
+		err = errs.Error()
+		return
+	}
+	return
+}
+
+func (tx *shardingTransaction) Query(ctx context.Context, query []byte, args []any) (rows Rows, err error) {
+	txs, useErr := tx.use(ctx)
+	if useErr != nil {
+		err = useErr
+		return
+	}
+	if len(txs) == 1 {
+		rows, err = txs[0].Query(ctx, query, args)
+		return
+	}
+	parts := make([]Rows, 0, len(txs))
+	for _, target := range txs {
+		part, queryErr := target.Query(ctx, query, args)
+		if queryErr != nil {
+			for _, p := range parts {
+				_ = p.Close()
+			}
+			err = queryErr
+			return
+		}
+		parts = append(parts, part)
+	}
+	rows = NewMultiRows(parts)
+	return
+}
+
+func (tx *shardingTransaction) Execute(ctx context.Context, query []byte, args []any) (result Result, err error) {
+	txs, useErr := tx.use(ctx)
+	if useErr != nil {
+		err = useErr
+		return
+	}
+	if len(txs) == 1 {
+		result, err = txs[0].Execute(ctx, query, args)
+		return
+	}
+	result.LastInsertId = -1
+	for _, target := range txs {
+		r, execErr := target.Execute(ctx, query, args)
+		if execErr != nil {
+			err = execErr
+			return
+		}
+		result.RowsAffected += r.RowsAffected
+	}
+	return
+}
+
+// NewMultiRows
+// rows of parts are read one by one, columns of parts must be same.
+func NewMultiRows(parts []Rows) Rows {
+	return &MultiRows{
+		parts: parts,
+		idx:   0,
+	}
+}
+
+type MultiRows struct {
+	parts []Rows
+	idx   int
+}
+
+func (rows *MultiRows) Columns() ([]string, error) {
+	if len(rows.parts) == 0 {
+		return nil, nil
+	}
+	return rows.parts[0].Columns()
+}
+
+func (rows *MultiRows) ColumnTypes() ([]ColumnType, error) {
+	if len(rows.parts) == 0 {
+		return nil, nil
+	}
+	return rows.parts[0].ColumnTypes()
+}
+
+func (rows *MultiRows) Next() bool {
+	for rows.idx < len(rows.parts) {
+		if rows.parts[rows.idx].Next() {
+			return true
+		}
+		rows.idx++
+	}
+	return false
+}
+
+func (rows *MultiRows) Scan(dst ...any) error {
+	if rows.idx >= len(rows.parts) {
+		return fmt.Errorf("sql: no rows")
+	}
+	return rows.parts[rows.idx].Scan(dst...)
+}
+
+func (rows *MultiRows) Close() (err error) {
+	for _, part := range rows.parts {
+		if closeErr := part.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return
+}
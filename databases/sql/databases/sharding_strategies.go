@@ -0,0 +1,314 @@
+package databases
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/aacfactory/configures"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/logs"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ShardingStrategyOptions struct {
+	Log       logs.Logger
+	Config    configures.Config
+	Shards    []string
+	Databases []Database
+}
+
+// ShardingStrategy
+// returns position of shard by value of shard key.
+type ShardingStrategy interface {
+	Name() string
+	Construct(options ShardingStrategyOptions) (err error)
+	Shard(ctx context.Context, key any) (shard int, err error)
+}
+
+var (
+	shardingStrategies = map[string]func() ShardingStrategy{
+		"hash":   func() ShardingStrategy { return &hashShardingStrategy{} },
+		"range":  func() ShardingStrategy { return &rangeShardingStrategy{} },
+		"lookup": func() ShardingStrategy { return &lookupShardingStrategy{} },
+	}
+)
+
+// RegisterShardingStrategy
+// register custom sharding strategy, it must be called before service constructed.
+func RegisterShardingStrategy(name string, maker func() ShardingStrategy) {
+	name = strings.TrimSpace(name)
+	if name == "" || maker == nil {
+		return
+	}
+	shardingStrategies[name] = maker
+}
+
+func getShardingStrategy(name string) (strategy ShardingStrategy, has bool) {
+	maker, exist := shardingStrategies[name]
+	if !exist {
+		return
+	}
+	strategy = maker()
+	has = true
+	return
+}
+
+func shardingKeyString(key any) (s string, err error) {
+	if valuer, ok := key.(driver.Valuer); ok {
+		key, err = valuer.Value()
+		if err != nil {
+			return
+		}
+	}
+	switch k := key.(type) {
+	case nil:
+		err = fmt.Errorf("shard key is nil")
+		break
+	case string:
+		s = k
+		break
+	case []byte:
+		s = bytex.ToString(k)
+		break
+	case time.Time:
+		s = k.UTC().Format(time.RFC3339Nano)
+		break
+	case fmt.Stringer:
+		s = k.String()
+		break
+	default:
+		s = fmt.Sprintf("%v", k)
+		break
+	}
+	return
+}
+
+// hash
+// shard = fnv64a(key) % len(shards)
+type hashShardingStrategy struct {
+	n uint64
+}
+
+func (strategy *hashShardingStrategy) Name() string {
+	return "hash"
+}
+
+func (strategy *hashShardingStrategy) Construct(options ShardingStrategyOptions) (err error) {
+	strategy.n = uint64(len(options.Shards))
+	return
+}
+
+func (strategy *hashShardingStrategy) Shard(_ context.Context, key any) (shard int, err error) {
+	s, sErr := shardingKeyString(key)
+	if sErr != nil {
+		err = sErr
+		return
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(bytex.FromString(s))
+	shard = int(h.Sum64() % strategy.n)
+	return
+}
+
+type ShardingRangeConfig struct {
+	Shard string `json:"shard"`
+	// From
+	// inclusive, empty means unbounded
+	From string `json:"from"`
+	// To
+	// exclusive, empty means unbounded
+	To string `json:"to"`
+}
+
+type RangeShardingStrategyConfig struct {
+	Ranges []ShardingRangeConfig `json:"ranges"`
+}
+
+type shardingRange struct {
+	shard int
+	from  string
+	to    string
+}
+
+// range
+// shard is matched by [from, to), values are compared as number or RFC3339 time when both sides can be parsed, otherwise as string.
+type rangeShardingStrategy struct {
+	ranges []shardingRange
+}
+
+func (strategy *rangeShardingStrategy) Name() string {
+	return "range"
+}
+
+func (strategy *rangeShardingStrategy) Construct(options ShardingStrategyOptions) (err error) {
+	config := RangeShardingStrategyConfig{}
+	configErr := options.Config.As(&config)
+	if configErr != nil {
+		err = errors.Warning("sql: range sharding strategy construct failed").WithCause(configErr)
+		return
+	}
+	if len(config.Ranges) == 0 {
+		err = errors.Warning("sql: range sharding strategy construct failed").WithCause(fmt.Errorf("ranges is required"))
+		return
+	}
+	for _, rc := range config.Ranges {
+		pos := shardPosition(options.Shards, rc.Shard)
+		if pos < 0 {
+			err = errors.Warning("sql: range sharding strategy construct failed").WithCause(fmt.Errorf("shard was not found")).WithMeta("shard", rc.Shard)
+			return
+		}
+		strategy.ranges = append(strategy.ranges, shardingRange{
+			shard: pos,
+			from:  strings.TrimSpace(rc.From),
+			to:    strings.TrimSpace(rc.To),
+		})
+	}
+	return
+}
+
+func (strategy *rangeShardingStrategy) Shard(_ context.Context, key any) (shard int, err error) {
+	s, sErr := shardingKeyString(key)
+	if sErr != nil {
+		err = sErr
+		return
+	}
+	for _, r := range strategy.ranges {
+		if r.from != "" && compareShardingValue(s, r.from) < 0 {
+			continue
+		}
+		if r.to != "" && compareShardingValue(s, r.to) >= 0 {
+			continue
+		}
+		shard = r.shard
+		return
+	}
+	err = fmt.Errorf("no range matched")
+	return
+}
+
+func compareShardingValue(a string, b string) int {
+	af, aErr := strconv.ParseFloat(a, 64)
+	bf, bErr := strconv.ParseFloat(b, 64)
+	if aErr == nil && bErr == nil {
+		if af < bf {
+			return -1
+		}
+		if af > bf {
+			return 1
+		}
+		return 0
+	}
+	at, atErr := time.Parse(time.RFC3339Nano, a)
+	bt, btErr := time.Parse(time.RFC3339Nano, b)
+	if atErr == nil && btErr == nil {
+		return at.Compare(bt)
+	}
+	return strings.Compare(a, b)
+}
+
+type LookupShardingStrategyConfig struct {
+	// Shard
+	// name of shard which has lookup table
+	Shard string `json:"shard"`
+	// Query
+	// query with one argument (value of shard key), returns name of shard,
+	// such as SELECT "SHARD" FROM "SHARD_LOOKUP" WHERE "KEY" = $1
+	Query string `json:"query"`
+	// Cache
+	// cache lookup results in memory
+	Cache bool `json:"cache"`
+}
+
+// lookup
+// shard is found by lookup table.
+type lookupShardingStrategy struct {
+	shards []string
+	db     Database
+	query  []byte
+	cache  bool
+	values sync.Map
+}
+
+func (strategy *lookupShardingStrategy) Name() string {
+	return "lookup"
+}
+
+func (strategy *lookupShardingStrategy) Construct(options ShardingStrategyOptions) (err error) {
+	config := LookupShardingStrategyConfig{}
+	configErr := options.Config.As(&config)
+	if configErr != nil {
+		err = errors.Warning("sql: lookup sharding strategy construct failed").WithCause(configErr)
+		return
+	}
+	pos := shardPosition(options.Shards, config.Shard)
+	if pos < 0 {
+		err = errors.Warning("sql: lookup sharding strategy construct failed").WithCause(fmt.Errorf("shard was not found")).WithMeta("shard", config.Shard)
+		return
+	}
+	query := strings.TrimSpace(config.Query)
+	if query == "" {
+		err = errors.Warning("sql: lookup sharding strategy construct failed").WithCause(fmt.Errorf("query is required"))
+		return
+	}
+	strategy.shards = options.Shards
+	strategy.db = options.Databases[pos]
+	strategy.query = []byte(query)
+	strategy.cache = config.Cache
+	return
+}
+
+func (strategy *lookupShardingStrategy) Shard(ctx context.Context, key any) (shard int, err error) {
+	s, sErr := shardingKeyString(key)
+	if sErr != nil {
+		err = sErr
+		return
+	}
+	if strategy.cache {
+		if cached, has := strategy.values.Load(s); has {
+			shard = cached.(int)
+			return
+		}
+	}
+	rows, queryErr := strategy.db.Query(ctx, strategy.query, []any{key})
+	if queryErr != nil {
+		err = queryErr
+		return
+	}
+	name := ""
+	found := false
+	if rows.Next() {
+		err = rows.Scan(&name)
+		found = true
+	}
+	_ = rows.Close()
+	if err != nil {
+		return
+	}
+	if !found {
+		err = fmt.Errorf("shard of key was not found in lookup table")
+		return
+	}
+	shard = shardPosition(strategy.shards, strings.TrimSpace(name))
+	if shard < 0 {
+		err = fmt.Errorf("shard %s was not found", name)
+		return
+	}
+	if strategy.cache {
+		strategy.values.Store(s, shard)
+	}
+	return
+}
+
+func shardPosition(shards []string, name string) int {
+	for i, shard := range shards {
+		if shard == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,136 @@
+package databases
+
+import (
+	"context"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"strings"
+	"testing"
+)
+
+type shardingTestShard struct {
+	executes   int
+	commits    int
+	rollbacks  int
+	executeErr error
+	commitErr  error
+}
+
+func (shard *shardingTestShard) Name() string {
+	return "test"
+}
+
+func (shard *shardingTestShard) Construct(_ Options) (err error) {
+	return
+}
+
+func (shard *shardingTestShard) Begin(_ context.Context, _ TransactionOptions) (tx Transaction, err error) {
+	tx = &shardingTestTransaction{shard: shard}
+	return
+}
+
+func (shard *shardingTestShard) Query(_ context.Context, _ []byte, _ []any) (rows Rows, err error) {
+	err = errors.Warning("sql: no rows in test")
+	return
+}
+
+func (shard *shardingTestShard) Execute(_ context.Context, _ []byte, _ []any) (result Result, err error) {
+	shard.executes++
+	result.RowsAffected = 1
+	return
+}
+
+func (shard *shardingTestShard) Close(_ context.Context) (err error) {
+	return
+}
+
+type shardingTestTransaction struct {
+	shard *shardingTestShard
+}
+
+func (tx *shardingTestTransaction) Commit() error {
+	if tx.shard.commitErr != nil {
+		return tx.shard.commitErr
+	}
+	tx.shard.commits++
+	return nil
+}
+
+func (tx *shardingTestTransaction) Rollback() error {
+	tx.shard.rollbacks++
+	return nil
+}
+
+func (tx *shardingTestTransaction) Query(_ context.Context, _ []byte, _ []any) (rows Rows, err error) {
+	err = errors.Warning("sql: no rows in test")
+	return
+}
+
+func (tx *shardingTestTransaction) Execute(_ context.Context, _ []byte, _ []any) (result Result, err error) {
+	if tx.shard.executeErr != nil {
+		err = tx.shard.executeErr
+		return
+	}
+	tx.shard.executes++
+	result.RowsAffected = 1
+	return
+}
+
+func newShardingTestDatabase(allow bool, shards ...*shardingTestShard) *sharding {
+	db := &sharding{
+		allowCrossShardTransaction: allow,
+	}
+	for i, shard := range shards {
+		db.names = append(db.names, "s"+string(rune('0'+i)))
+		db.shards = append(db.shards, shard)
+	}
+	return db
+}
+
+func TestSharding_ExecuteRejected(t *testing.T) {
+	s0, s1 := &shardingTestShard{}, &shardingTestShard{}
+	db := newShardingTestDatabase(false, s0, s1)
+	_, err := db.Execute(context.TODO(), []byte("DELETE FROM T"), nil)
+	if !errors.Contains(err, ErrCrossShardExecute) {
+		t.Errorf("execute on all shards must be rejected, got %v", err)
+	}
+	if s0.executes != 0 || s1.executes != 0 {
+		t.Errorf("rejected execute must not be sent")
+	}
+}
+
+func TestSharding_ExecuteInTransactions(t *testing.T) {
+	s0, s1 := &shardingTestShard{}, &shardingTestShard{}
+	db := newShardingTestDatabase(true, s0, s1)
+	result, err := db.Execute(context.TODO(), []byte("DELETE FROM T"), nil)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if result.RowsAffected != 2 || s0.commits != 1 || s1.commits != 1 {
+		t.Errorf("execute must be committed on all shards, got %+v", result)
+	}
+	// execute failed
+	s0, s1 = &shardingTestShard{}, &shardingTestShard{executeErr: errors.Warning("failed")}
+	db = newShardingTestDatabase(true, s0, s1)
+	if _, err = db.Execute(context.TODO(), []byte("DELETE FROM T"), nil); err == nil {
+		t.Errorf("execute must be failed")
+	}
+	if s0.commits != 0 || s0.rollbacks != 1 || s1.rollbacks != 1 {
+		t.Errorf("all shards must be rolled back when execute failed")
+	}
+	// commit failed
+	s0, s1, s2 := &shardingTestShard{}, &shardingTestShard{commitErr: errors.Warning("failed")}, &shardingTestShard{}
+	db = newShardingTestDatabase(true, s0, s1, s2)
+	_, err = db.Execute(context.TODO(), []byte("DELETE FROM T"), nil)
+	if err == nil {
+		t.Errorf("execute must be failed")
+		return
+	}
+	if text := fmt.Sprintf("%+v", err); !strings.Contains(text, "applied") || !strings.Contains(text, "s0") {
+		t.Errorf("applied shards must be in meta of error, got %+v", err)
+	}
+	if s0.commits != 1 || s2.commits != 0 || s2.rollbacks != 1 {
+		t.Errorf("shards after failed one must be rolled back")
+	}
+}
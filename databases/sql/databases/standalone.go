@@ -2,9 +2,7 @@ package databases
 
 import (
 	"context"
-	"database/sql"
 	"github.com/aacfactory/errors"
-	"github.com/aacfactory/fns/commons/bytex"
 	"github.com/aacfactory/logs"
 	"time"
 )
@@ -14,20 +12,20 @@ func Standalone() Database {
 }
 
 type standaloneConfig struct {
-	Driver      string           `json:"driver"`
-	DSN         string           `json:"dsn"`
-	MaxIdles    int              `json:"maxIdles"`
-	MaxOpens    int              `json:"maxOpens"`
-	MaxIdleTime time.Duration    `json:"maxIdleTime"`
-	MaxLifetime time.Duration    `json:"maxLifetime"`
-	Statements  StatementsConfig `json:"statements"`
+	Driver      string            `json:"driver"`
+	DSN         string            `json:"dsn"`
+	MaxIdles    int               `json:"maxIdles"`
+	MaxOpens    int               `json:"maxOpens"`
+	MaxIdleTime time.Duration     `json:"maxIdleTime"`
+	MaxLifetime time.Duration     `json:"maxLifetime"`
+	Statements  StatementsConfig  `json:"statements"`
+	Credentials CredentialsConfig `json:"credentials"`
 }
 
 type standalone struct {
-	log        logs.Logger
-	core       *sql.DB
-	prepare    bool
-	statements *Statements
+	log     logs.Logger
+	core    *connection
+	unwatch func()
 }
 
 func (db *standalone) Name() string {
@@ -42,142 +40,50 @@ func (db *standalone) Construct(options Options) (err error) {
 		err = errors.Warning("sql: standalone database construct failed").WithCause(configErr)
 		return
 	}
-	db.core, err = sql.Open(config.Driver, config.DSN)
-	if err != nil {
-		err = errors.Warning("sql: standalone database construct failed").WithCause(err)
+	provider, providerErr := newCredentialProvider(db.log, config.Credentials)
+	if providerErr != nil {
+		err = errors.Warning("sql: standalone database construct failed").WithCause(providerErr)
 		return
 	}
-	maxIdles := config.MaxIdles
-	if maxIdles > 0 {
-		db.core.SetMaxIdleConns(maxIdles)
-	}
-	maxOpens := config.MaxOpens
-	if maxOpens > 0 {
-		db.core.SetMaxOpenConns(maxOpens)
-	}
-	maxIdleTime := config.MaxIdleTime
-	if maxIdleTime > 0 {
-		db.core.SetConnMaxIdleTime(maxIdleTime)
-	}
-	maxLifetime := config.MaxLifetime
-	if maxLifetime > 0 {
-		db.core.SetConnMaxLifetime(maxLifetime)
-	}
-	err = db.core.Ping()
+	db.core, err = newConnection(db.log, connectionOptions{
+		driver:       config.Driver,
+		dsn:          config.DSN,
+		maxIdles:     config.MaxIdles,
+		maxOpens:     config.MaxOpens,
+		maxIdleTime:  config.MaxIdleTime,
+		maxLifetime:  config.MaxLifetime,
+		statements:   config.Statements,
+		drainTimeout: config.Credentials.DrainTimeout(),
+		sessions:     options.Sessions,
+	}, provider)
 	if err != nil {
+		if provider != nil {
+			provider.Close()
+		}
 		err = errors.Warning("sql: standalone database construct failed").WithCause(err)
 		return
 	}
-	if config.Statements.Enable {
-		cacheSize := config.Statements.CacheSize
-		if cacheSize < 1 {
-			cacheSize = 1024
-		}
-		evictTimeoutSeconds := config.Statements.EvictTimeoutSeconds
-		if evictTimeoutSeconds < 1 {
-			evictTimeoutSeconds = 10
-		}
-		db.statements, err = NewStatements(db.log, db.core, cacheSize, time.Duration(evictTimeoutSeconds)*time.Second)
-		if err != nil {
-			err = errors.Warning("sql: standalone database construct failed").WithCause(err)
-			return
-		}
-		db.prepare = true
-	}
+	db.unwatch = watchCredentials(db.log, provider, []*connection{db.core})
 	return
 }
 
 func (db *standalone) Begin(ctx context.Context, options TransactionOptions) (tx Transaction, err error) {
-	core, begErr := db.core.BeginTx(ctx, &sql.TxOptions{
-		Isolation: sql.IsolationLevel(options.Isolation),
-		ReadOnly:  options.Readonly,
-	})
-	if begErr != nil {
-		err = begErr
-		return
-	}
-	tx = &DefaultTransaction{
-		core:       core,
-		prepare:    db.prepare,
-		statements: db.statements,
-	}
+	tx, err = db.core.begin(ctx, options)
 	return
 }
 
 func (db *standalone) Query(ctx context.Context, query []byte, args []any) (rows Rows, err error) {
-	var r *sql.Rows
-	if db.prepare {
-		stmt, prepareErr := db.statements.Get(query)
-		if prepareErr != nil {
-			err = prepareErr
-			return
-		}
-		r, err = stmt.QueryContext(ctx, args...)
-		if err != nil {
-			if errors.Contains(err, ErrStatementClosed) {
-				rows, err = db.Query(ctx, query, args)
-				return
-			}
-			return
-		}
-	} else {
-		r, err = db.core.QueryContext(ctx, bytex.ToString(query), args...)
-		if err != nil {
-			return
-		}
-	}
-
-	rows = &DefaultRows{
-		core: r,
-	}
+	rows, err = db.core.query(ctx, query, args)
 	return
 }
 
 func (db *standalone) Execute(ctx context.Context, query []byte, args []any) (result Result, err error) {
-	var r sql.Result
-	if db.prepare {
-		stmt, prepareErr := db.statements.Get(query)
-		if prepareErr != nil {
-			err = prepareErr
-			return
-		}
-		r, err = stmt.ExecContext(ctx, args...)
-		if err != nil {
-			if errors.Contains(err, ErrStatementClosed) {
-				result, err = db.Execute(ctx, query, args)
-				return
-			}
-			return
-		}
-	} else {
-		r, err = db.core.ExecContext(ctx, bytex.ToString(query), args...)
-		if err != nil {
-			return
-		}
-	}
-
-	rowsAffected, rowsAffectedErr := r.RowsAffected()
-	if rowsAffectedErr != nil {
-		err = rowsAffectedErr
-		return
-	}
-
-	lastInsertId, lastInsertIdErr := r.LastInsertId()
-	if lastInsertIdErr != nil {
-		lastInsertId = -1
-	}
-
-	result = Result{
-		LastInsertId: lastInsertId,
-		RowsAffected: rowsAffected,
-	}
+	result, err = db.core.execute(ctx, query, args)
 	return
 }
 
 func (db *standalone) Close(_ context.Context) (err error) {
-	if db.prepare {
-		db.statements.Close()
-	}
-	err = db.core.Close()
+	db.unwatch()
+	err = db.core.close()
 	return
 }
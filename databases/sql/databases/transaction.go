@@ -3,13 +3,25 @@ package databases
 import (
 	"context"
 	"database/sql"
+	"github.com/aacfactory/errors"
 	"github.com/aacfactory/fns/commons/bytex"
+	"sync/atomic"
+)
+
+var (
+	ErrReadOnlyTransaction = errors.Warning("sql: write is not allowed in read only transaction")
 )
 
 type TransactionOptions struct {
 	Id        []byte
 	Isolation Isolation
-	Readonly  bool
+	// Readonly
+	// transaction is read only, it is begun on slaver when kind of database is masterSlave,
+	// and write statements in it are rejected, see IsWriteStatement.
+	Readonly bool
+	// CrossShard
+	// transaction can use more than one shards when database is sharding.
+	CrossShard bool
 }
 
 type TransactionOption func(options *TransactionOptions)
@@ -37,21 +49,49 @@ func NewTransaction(tx *sql.Tx) Transaction {
 	}
 }
 
+// NewReadOnlyTransaction
+// write statements are rejected by it, use it when driver does not support read only transaction.
+func NewReadOnlyTransaction(tx *sql.Tx) Transaction {
+	return &DefaultTransaction{
+		core:       tx,
+		prepare:    false,
+		statements: nil,
+		readonly:   true,
+	}
+}
+
 type DefaultTransaction struct {
 	core       *sql.Tx
 	prepare    bool
 	statements *Statements
+	readonly   bool
+	// done
+	// is called once when transaction is committed or rolled back
+	done     func()
+	finished atomic.Bool
 }
 
 func (tx *DefaultTransaction) Commit() error {
+	defer tx.finish()
 	return tx.core.Commit()
 }
 
 func (tx *DefaultTransaction) Rollback() error {
+	defer tx.finish()
 	return tx.core.Rollback()
 }
 
+func (tx *DefaultTransaction) finish() {
+	if tx.done != nil && tx.finished.CompareAndSwap(false, true) {
+		tx.done()
+	}
+}
+
 func (tx *DefaultTransaction) Query(ctx context.Context, query []byte, args []any) (rows Rows, err error) {
+	if tx.readonly && IsWriteStatement(query) {
+		err = ErrReadOnlyTransaction
+		return
+	}
 	var r *sql.Rows
 	if tx.prepare {
 		stmt, prepareErr := tx.statements.Get(query)
@@ -83,6 +123,10 @@ func (tx *DefaultTransaction) Query(ctx context.Context, query []byte, args []an
 }
 
 func (tx *DefaultTransaction) Execute(ctx context.Context, query []byte, args []any) (result Result, err error) {
+	if tx.readonly && IsWriteStatement(query) {
+		err = ErrReadOnlyTransaction
+		return
+	}
 	var r sql.Result
 	if tx.prepare {
 		stmt, prepareErr := tx.statements.Get(query)
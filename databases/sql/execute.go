@@ -19,6 +19,12 @@ var (
 )
 
 func Execute(ctx context.Context, query []byte, arguments ...interface{}) (result databases.Result, err error) {
+	query, arguments, err = preprocess(ctx, query, arguments)
+	if err != nil {
+		err = errors.Warning("sql: execute failed").WithCause(err).WithMeta("query", bytex.ToString(query))
+		return
+	}
+	sharding, _ := LoadSharding(ctx)
 	tx, hasTx := loadTransaction(ctx)
 	if hasTx {
 		var log logs.Logger
@@ -30,7 +36,20 @@ func Execute(ctx context.Context, query []byte, arguments ...interface{}) (resul
 				handleBegin = time.Now()
 			}
 		}
-		result, err = tx.Execute(context.TODO(), query, arguments)
+		timeout := loadTimeout(ctx)
+		if timeout <= 0 {
+			timeout = tx.StatementTimeout
+		}
+		if dialect, dialectErr := Dialect(ctx); dialectErr == nil {
+			query = statementTimeoutHint(dialect, query, timeout)
+			if sessionErr := useStatementTimeout(ctx, dialect, tx, timeout); sessionErr != nil {
+				err = errors.Warning("sql: execute failed").WithCause(sessionErr)
+				return
+			}
+		}
+		stmtCtx, cancel := statementContext(ctx, sharding, timeout)
+		result, err = tx.Execute(stmtCtx, query, arguments)
+		cancel()
 		if debug && log.DebugEnabled() {
 			latency := time.Now().Sub(handleBegin)
 			log.Debug().With("succeed", err == nil).With("latency", latency.String()).With("transaction", tx.Id).
@@ -55,6 +74,8 @@ func Execute(ctx context.Context, query []byte, arguments ...interface{}) (resul
 	param := executeParam{
 		Query:     bytex.ToString(query),
 		Arguments: Arguments(arguments),
+		Sharding:  sharding,
+		Timeout:   loadTimeout(ctx),
 	}
 	ep := endpointName
 	if epn := used(ctx); len(epn) > 0 {
@@ -76,13 +97,19 @@ func Execute(ctx context.Context, query []byte, arguments ...interface{}) (resul
 type executeParam struct {
 	Query     string    `json:"query" avro:"query"`
 	Arguments Arguments `json:"arguments" avro:"arguments"`
+	Sharding  Sharding  `json:"sharding" avro:"sharding"`
+	// Timeout
+	// timeout of statement which is set by WithTimeout, zero means using statementTimeout of config.
+	Timeout time.Duration `json:"timeout" avro:"timeout"`
 }
 
 type executeFn struct {
-	debug bool
-	log   logs.Logger
-	db    databases.Database
-	group *transactions.Group
+	debug   bool
+	log     logs.Logger
+	db      databases.Database
+	group   *transactions.Group
+	dialect string
+	timeout time.Duration
 }
 
 func (fn *executeFn) Name() string {
@@ -107,6 +134,11 @@ func (fn *executeFn) Handle(r services.Request) (v interface{}, err error) {
 		err = errors.Warning("sql: execute failed").WithCause(fmt.Errorf("query is required"))
 		return
 	}
+	timeout := param.Timeout
+	if timeout <= 0 {
+		timeout = fn.timeout
+	}
+	query := statementTimeoutHint(fn.dialect, bytex.FromString(param.Query), timeout)
 	info, has, loadErr := loadTransactionInfo(r)
 	if loadErr != nil {
 		err = errors.Warning("sql: execute failed").WithCause(loadErr)
@@ -120,7 +152,13 @@ func (fn *executeFn) Handle(r services.Request) (v interface{}, err error) {
 				useDebugLog(r)
 				handleBegin = time.Now()
 			}
-			result, executeErr := tx.Execute(context.TODO(), bytex.FromString(param.Query), param.Arguments)
+			if sessionErr := useStatementTimeout(r, fn.dialect, tx, timeout); sessionErr != nil {
+				err = errors.Warning("sql: execute failed").WithCause(sessionErr)
+				return
+			}
+			stmtCtx, cancel := statementContext(r, param.Sharding, timeout)
+			result, executeErr := tx.Execute(stmtCtx, query, param.Arguments)
+			cancel()
 			if fn.debug && fn.log.DebugEnabled() {
 				latency := time.Now().Sub(handleBegin)
 				fn.log.Debug().With("succeed", executeErr == nil).With("latency", latency.String()).With("transaction", info.Id).
@@ -139,7 +177,9 @@ func (fn *executeFn) Handle(r services.Request) (v interface{}, err error) {
 		useDebugLog(r)
 		handleBegin = time.Now()
 	}
-	result, executeErr := fn.db.Execute(context.TODO(), bytex.FromString(param.Query), param.Arguments)
+	stmtCtx, cancel := statementContext(r, param.Sharding, timeout)
+	result, executeErr := fn.db.Execute(stmtCtx, query, param.Arguments)
+	cancel()
 	if fn.debug && fn.log.DebugEnabled() {
 		latency := time.Now().Sub(handleBegin)
 		fn.log.Debug().With("succeed", executeErr == nil).With("latency", latency.String()).
@@ -11,3 +11,9 @@ func FAG() []modules.FnAnnotationCodeWriter {
 		&generators.TransactionWriter{},
 	}
 }
+
+// QG
+// query generator which generates typed query functions from annotated sql files.
+func QG() *generators.QueryGenerator {
+	return &generators.QueryGenerator{}
+}
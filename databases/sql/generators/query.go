@@ -0,0 +1,454 @@
+package generators
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns/cmd/generates/sources"
+	"github.com/aacfactory/gcg"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+const (
+	queryOne  = "one"
+	queryMany = "many"
+	queryExec = "exec"
+)
+
+var (
+	queryTypePackages = map[string]string{
+		"time":  "time",
+		"times": "github.com/aacfactory/fns/commons/times",
+		"json":  "github.com/aacfactory/json",
+		"sql":   "github.com/aacfactory/fns-contrib/databases/sql",
+	}
+	// reservedQueryParamNames
+	// locals, results and imported packages of generated function, param can not use them.
+	reservedQueryParamNames = map[string]struct{}{
+		"ctx": {}, "entries": {}, "queryErr": {}, "row": {}, "rows": {}, "has": {}, "result": {}, "err": {},
+		"sql": {}, "bytex": {}, "context": {}, "databases": {}, "time": {}, "times": {}, "json": {},
+	}
+)
+
+// QueryGenerator
+// generate typed query functions from annotated sql files, {name}.sql will be generated into {name}.sql.go in same dir.
+// annotations are sql comments before statement:
+// -- @name {FuncName} {one|many|exec}
+// -- @param {name} {type}
+// -- @column {Field} {type}
+// named parameters in statement are `:name`, they are bound by sql.Named, so placeholders are rewritten by the dialect of ctx.
+// columns of row are scanned in order.
+type QueryGenerator struct {
+}
+
+func (generator *QueryGenerator) Generate(ctx context.Context, mod *sources.Module) (err error) {
+	filenames := make([]string, 0, 1)
+	walkErr := filepath.WalkDir(mod.Dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != mod.Dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".sql" {
+			filenames = append(filenames, path)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		err = errors.Warning("sql: generate query code failed").WithCause(walkErr).WithMeta("dir", mod.Dir)
+		return
+	}
+	for _, filename := range filenames {
+		if ctx.Err() != nil {
+			err = errors.Warning("sql: generate query code failed").WithCause(ctx.Err())
+			return
+		}
+		queries, parseErr := parseQueryFile(filename)
+		if parseErr != nil {
+			err = errors.Warning("sql: generate query code failed").WithCause(parseErr).WithMeta("file", filename)
+			return
+		}
+		if len(queries) == 0 {
+			continue
+		}
+		writeErr := writeQueryFile(filename, queries)
+		if writeErr != nil {
+			err = errors.Warning("sql: generate query code failed").WithCause(writeErr).WithMeta("file", filename)
+			return
+		}
+	}
+	return
+}
+
+type queryParam struct {
+	Name string
+	Type string
+}
+
+type queryColumn struct {
+	Field string
+	Type  string
+}
+
+type namedQuery struct {
+	Name    string
+	Kind    string
+	Params  []queryParam
+	Columns []queryColumn
+	Query   string
+}
+
+func (query *namedQuery) param(name string) (param queryParam, has bool) {
+	for _, p := range query.Params {
+		if p.Name == name {
+			param = p
+			has = true
+			return
+		}
+	}
+	return
+}
+
+func parseQueryFile(filename string) (queries []*namedQuery, err error) {
+	p, readErr := os.ReadFile(filename)
+	if readErr != nil {
+		err = readErr
+		return
+	}
+	var current *namedQuery
+	body := bytes.NewBuffer(nil)
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+		if compileErr := current.compile(strings.TrimSpace(body.String())); compileErr != nil {
+			return compileErr
+		}
+		queries = append(queries, current)
+		current = nil
+		body.Reset()
+		return nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(p))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+		if strings.HasPrefix(trimmed, "--") {
+			comment := strings.TrimSpace(strings.TrimPrefix(trimmed, "--"))
+			if !strings.HasPrefix(comment, "@") {
+				continue
+			}
+			items := strings.Fields(comment)
+			switch items[0] {
+			case "@name":
+				if err = flush(); err != nil {
+					return
+				}
+				if len(items) != 3 {
+					err = fmt.Errorf("line %d: @name requires name and kind", line)
+					return
+				}
+				kind := strings.ToLower(items[2])
+				if kind != queryOne && kind != queryMany && kind != queryExec {
+					err = fmt.Errorf("line %d: kind of query must be one, many or exec", line)
+					return
+				}
+				if !isExportedIdent(items[1]) {
+					err = fmt.Errorf("line %d: name of query must be exported ident", line)
+					return
+				}
+				current = &namedQuery{
+					Name: items[1],
+					Kind: kind,
+				}
+				break
+			case "@param":
+				if current == nil || len(items) != 3 {
+					err = fmt.Errorf("line %d: invalid @param", line)
+					return
+				}
+				if nameErr := validateQueryParamName(items[1]); nameErr != nil {
+					err = fmt.Errorf("line %d: %v", line, nameErr)
+					return
+				}
+				if _, has := current.param(items[1]); has {
+					err = fmt.Errorf("line %d: param %s is duplicated", line, items[1])
+					return
+				}
+				if typeErr := validateQueryType(items[2]); typeErr != nil {
+					err = fmt.Errorf("line %d: %v", line, typeErr)
+					return
+				}
+				current.Params = append(current.Params, queryParam{Name: items[1], Type: items[2]})
+				break
+			case "@column":
+				if current == nil || len(items) != 3 {
+					err = fmt.Errorf("line %d: invalid @column", line)
+					return
+				}
+				if !isExportedIdent(items[1]) {
+					err = fmt.Errorf("line %d: field of column must be exported ident", line)
+					return
+				}
+				if typeErr := validateQueryType(items[2]); typeErr != nil {
+					err = fmt.Errorf("line %d: %v", line, typeErr)
+					return
+				}
+				current.Columns = append(current.Columns, queryColumn{Field: items[1], Type: items[2]})
+				break
+			default:
+				err = fmt.Errorf("line %d: %s annotation is unknown", line, items[0])
+				return
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		body.WriteString(text)
+		body.WriteByte('\n')
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		err = scanErr
+		return
+	}
+	err = flush()
+	return
+}
+
+// compile
+// check named parameters of statement, string literals, quoted idents, comments and casts are skipped.
+// dialect is unknown when generating, so backslash escaped quotes are tried when statement is not valid in standard sql.
+func (query *namedQuery) compile(content string) (err error) {
+	content = strings.TrimSuffix(content, ";")
+	if content == "" {
+		err = fmt.Errorf("statement of %s is empty", query.Name)
+		return
+	}
+	if query.Kind != queryExec && len(query.Columns) == 0 {
+		err = fmt.Errorf("columns of %s is required", query.Name)
+		return
+	}
+	tokens, lexErr := databases.LexQuery([]byte(content), false)
+	if lexErr != nil {
+		tokens, lexErr = databases.LexQuery([]byte(content), true)
+		if lexErr != nil {
+			err = fmt.Errorf("statement of %s is invalid, %v", query.Name, lexErr)
+			return
+		}
+	}
+	for _, token := range tokens {
+		switch token.Kind {
+		case databases.NamedQueryToken:
+			name := string(token.Value[1:])
+			if _, has := query.param(name); !has {
+				err = fmt.Errorf("param %s of %s was not declared", name, query.Name)
+				return
+			}
+			break
+		case databases.PlaceholderQueryToken, databases.NumberedPlaceholderQueryToken:
+			err = fmt.Errorf("statement of %s must use named params instead of %s", query.Name, token.Value)
+			return
+		default:
+			break
+		}
+	}
+	query.Query = content
+	return
+}
+
+func isExportedIdent(s string) bool {
+	if s == "" || !unicode.IsUpper(rune(s[0])) {
+		return false
+	}
+	for _, c := range s {
+		if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateQueryParamName
+// param must be an ident which is not go keyword and not reserved by generated function.
+func validateQueryParamName(name string) (err error) {
+	if !token.IsIdentifier(name) {
+		err = fmt.Errorf("param %s is not valid ident", name)
+		return
+	}
+	if _, reserved := reservedQueryParamNames[name]; reserved {
+		err = fmt.Errorf("param %s is reserved, please use another name", name)
+		return
+	}
+	return
+}
+
+func validateQueryType(typ string) (err error) {
+	t := strings.TrimPrefix(typ, "[]")
+	if idx := strings.IndexByte(t, '.'); idx > 0 {
+		if _, has := queryTypePackages[t[0:idx]]; !has {
+			err = fmt.Errorf("package of %s is not supported", typ)
+			return
+		}
+		return
+	}
+	switch t {
+	case "string", "bool", "byte", "int", "int8", "int16", "int32", "int64", "float32", "float64", "any":
+		break
+	default:
+		err = fmt.Errorf("%s is not supported", typ)
+		return
+	}
+	return
+}
+
+func queryTypeCode(typ string) (code gcg.Code) {
+	t := strings.TrimPrefix(typ, "[]")
+	if idx := strings.IndexByte(t, '.'); idx > 0 {
+		code = gcg.Token(typ, gcg.NewPackage(queryTypePackages[t[0:idx]]))
+		return
+	}
+	code = gcg.Token(typ)
+	return
+}
+
+func queryPackageName(dir string) (name string) {
+	entries, readErr := os.ReadDir(dir)
+	if readErr == nil {
+		fset := token.NewFileSet()
+		for _, entry := range entries {
+			filename := entry.Name()
+			if entry.IsDir() || filepath.Ext(filename) != ".go" || strings.HasSuffix(filename, "_test.go") || strings.HasSuffix(filename, ".sql.go") {
+				continue
+			}
+			file, parseErr := parser.ParseFile(fset, filepath.Join(dir, filename), nil, parser.PackageClauseOnly)
+			if parseErr == nil {
+				name = file.Name.Name
+				return
+			}
+		}
+	}
+	name = strings.ReplaceAll(filepath.Base(dir), "-", "_")
+	return
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[0:1]) + s[1:]
+}
+
+func writeQueryFile(filename string, queries []*namedQuery) (err error) {
+	file := gcg.NewFileWithoutNote(queryPackageName(filepath.Dir(filename)))
+	file.FileComments("NOTE: this file has been automatically generated, DON'T EDIT IT!!!\n")
+
+	// queries
+	consts := gcg.Constants()
+	for _, query := range queries {
+		consts.Add(lowerFirst(query.Name)+"Query", query.Query)
+	}
+	file.AddCode(consts.Build())
+
+	for _, query := range queries {
+		// row
+		if query.Kind != queryExec {
+			row := gcg.Statements()
+			row.Token(fmt.Sprintf("type %sRow struct {", query.Name)).Line()
+			for _, column := range query.Columns {
+				row.Tab().Token(column.Field).Space().Add(queryTypeCode(column.Type)).Space().Token(fmt.Sprintf("`json:\"%s\" avro:\"%s\"`", lowerFirst(column.Field), lowerFirst(column.Field))).Line()
+			}
+			row.Token("}").Line()
+			file.AddCode(row)
+		}
+		// func
+		fn := gcg.Func()
+		fn.Name(query.Name)
+		fn.Comments(fmt.Sprintf("generated from %s", filepath.Base(filename)))
+		fn.AddParam("ctx", gcg.Token("context.Context", gcg.NewPackage("github.com/aacfactory/fns/context")))
+		for _, param := range query.Params {
+			fn.AddParam(param.Name, queryTypeCode(param.Type))
+		}
+		switch query.Kind {
+		case queryOne:
+			fn.AddResult("row", gcg.Token(query.Name+"Row"))
+			fn.AddResult("has", gcg.Token("bool"))
+			break
+		case queryMany:
+			fn.AddResult("rows", gcg.Token("[]"+query.Name+"Row"))
+			break
+		default:
+			fn.AddResult("result", gcg.Token("databases.Result", gcg.NewPackage("github.com/aacfactory/fns-contrib/databases/sql/databases")))
+			break
+		}
+		fn.AddResult("err", gcg.Error())
+
+		body := gcg.Statements()
+		arguments := make([]string, 0, len(query.Params))
+		for _, param := range query.Params {
+			arguments = append(arguments, fmt.Sprintf("sql.Named(\"%s\", %s)", param.Name, param.Name))
+		}
+		statement := fmt.Sprintf("bytex.FromString(%sQuery)", lowerFirst(query.Name))
+		if len(arguments) > 0 {
+			statement = statement + ", " + strings.Join(arguments, ", ")
+		}
+		if query.Kind == queryExec {
+			body.Token(fmt.Sprintf("result, err = sql.Execute(ctx, %s)", statement), gcg.NewPackage("github.com/aacfactory/fns-contrib/databases/sql"), gcg.NewPackage("github.com/aacfactory/fns/commons/bytex")).Line()
+			body.Token("return")
+		} else {
+			fields := make([]string, 0, len(query.Columns))
+			for _, column := range query.Columns {
+				fields = append(fields, "&row."+column.Field)
+			}
+			body.Token(fmt.Sprintf("entries, queryErr := sql.Query(ctx, %s)", statement), gcg.NewPackage("github.com/aacfactory/fns-contrib/databases/sql"), gcg.NewPackage("github.com/aacfactory/fns/commons/bytex")).Line()
+			body.Token("if queryErr != nil {").Line()
+			body.Tab().Token("err = queryErr").Line()
+			body.Tab().Token("return").Line()
+			body.Token("}").Line()
+			body.Token("defer entries.Close()").Line()
+			if query.Kind == queryOne {
+				body.Token("if entries.Next() {").Line()
+				body.Tab().Token(fmt.Sprintf("if err = entries.Scan(%s); err != nil {", strings.Join(fields, ", "))).Line()
+				body.Tab().Tab().Token("return").Line()
+				body.Tab().Token("}").Line()
+				body.Tab().Token("has = true").Line()
+				body.Token("}").Line()
+			} else {
+				body.Token("for entries.Next() {").Line()
+				body.Tab().Token(fmt.Sprintf("row := %sRow{}", query.Name)).Line()
+				body.Tab().Token(fmt.Sprintf("if err = entries.Scan(%s); err != nil {", strings.Join(fields, ", "))).Line()
+				body.Tab().Tab().Token("return").Line()
+				body.Tab().Token("}").Line()
+				body.Tab().Token("rows = append(rows, row)").Line()
+				body.Token("}").Line()
+			}
+			body.Token("return")
+		}
+		fn.Body(body)
+		file.AddCode(fn.Build())
+	}
+
+	buf := bytes.NewBuffer(nil)
+	renderErr := file.Render(buf)
+	if renderErr != nil {
+		err = renderErr
+		return
+	}
+	err = os.WriteFile(filename+".go", buf.Bytes(), 0644)
+	return
+}
@@ -0,0 +1,128 @@
+package generators
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeQueryTestFile(t *testing.T, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "users.sql")
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestParseQueryFile(t *testing.T) {
+	filename := writeQueryTestFile(t, `
+-- @name GetUser one
+-- @param id string
+-- @column Id string
+-- @column Name string
+SELECT "ID", "NAME"::text FROM "USER" WHERE "ID" = :id OR "PARENT" = :id AND "NAME" <> ':id';
+`)
+	queries, err := parseQueryFile(filename)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if len(queries) != 1 {
+		t.Errorf("queries is not matched")
+		return
+	}
+	query := queries[0]
+	if query.Query != `SELECT "ID", "NAME"::text FROM "USER" WHERE "ID" = :id OR "PARENT" = :id AND "NAME" <> ':id'` {
+		t.Errorf("query is not matched, %s", query.Query)
+	}
+}
+
+func TestParseQueryFile_QuotesAndComments(t *testing.T) {
+	filename := writeQueryTestFile(t, `
+-- @name GetUser one
+-- @param id string
+-- @column Id string
+SELECT "ID" /* :name */ FROM "USER" WHERE "NAME" <> 'it''s :name' AND "ID" = :id -- :name
+;
+-- @name GetMysqlUser one
+-- @param id string
+-- @column Id string
+SELECT ID FROM USER WHERE NAME <> 'it\'s :name' AND ID = :id;
+`)
+	queries, err := parseQueryFile(filename)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if len(queries) != 2 {
+		t.Errorf("queries is not matched")
+	}
+}
+
+func TestParseQueryFile_UndeclaredParam(t *testing.T) {
+	filename := writeQueryTestFile(t, `
+-- @name GetUser one
+-- @param id string
+-- @column Id string
+SELECT "ID" FROM "USER" WHERE "ID" = :id AND "NAME" = :name
+`)
+	if _, err := parseQueryFile(filename); err == nil {
+		t.Errorf("undeclared param must be rejected")
+	}
+}
+
+func TestWriteQueryFile(t *testing.T) {
+	filename := writeQueryTestFile(t, `
+-- @name GetUser one
+-- @param id string
+-- @column Id string
+SELECT "ID" FROM "USER" WHERE "ID" = :id
+`)
+	queries, err := parseQueryFile(filename)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if err = os.WriteFile(filepath.Join(filepath.Dir(filename), "doc.go"), []byte("package users\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = writeQueryFile(filename, queries); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	p, readErr := os.ReadFile(filename + ".go")
+	if readErr != nil {
+		t.Error(readErr)
+		return
+	}
+	if !strings.Contains(string(p), `sql.Query(ctx, bytex.FromString(getUserQuery), sql.Named("id", id))`) {
+		t.Errorf("generated code is not matched\n%s", p)
+	}
+}
+
+func TestParseQueryFile_ReservedParam(t *testing.T) {
+	for _, name := range []string{"ctx", "entries", "rows", "err", "sql", "func", "1id"} {
+		filename := writeQueryTestFile(t, `
+-- @name DeleteUser exec
+-- @param `+name+` string
+DELETE FROM "USER" WHERE "ID" = :`+name+`
+`)
+		if _, err := parseQueryFile(filename); err == nil {
+			t.Errorf("param %s must be rejected", name)
+		}
+	}
+}
+
+func TestParseQueryFile_DuplicatedParam(t *testing.T) {
+	filename := writeQueryTestFile(t, `
+-- @name DeleteUser exec
+-- @param id string
+-- @param id int64
+DELETE FROM "USER" WHERE "ID" = :id
+`)
+	if _, err := parseQueryFile(filename); err == nil {
+		t.Errorf("duplicated param must be rejected")
+	}
+}
@@ -82,7 +82,7 @@ func (writer *TransactionWriter) HandleBefore(ctx context.Context, params []stri
 
 	stmt.Tab().Token("if err = sql.Begin(ctx")
 	if readonly {
-		stmt.Token(", sql.Readonly()")
+		stmt.Token(", sql.ReadOnly()")
 	}
 	if isolation != sql.LevelDefault {
 		stmt.Token(", sql.WithIsolation(")
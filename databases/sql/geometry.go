@@ -0,0 +1,647 @@
+package sql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	PointGeometry           = GeometryType("Point")
+	LineStringGeometry      = GeometryType("LineString")
+	PolygonGeometry         = GeometryType("Polygon")
+	MultiPointGeometry      = GeometryType("MultiPoint")
+	MultiLineStringGeometry = GeometryType("MultiLineString")
+	MultiPolygonGeometry    = GeometryType("MultiPolygon")
+)
+
+const (
+	// WGS84
+	// default srid of geojson
+	WGS84 = 4326
+)
+
+const (
+	wkbPoint           uint32 = 1
+	wkbLineString      uint32 = 2
+	wkbPolygon         uint32 = 3
+	wkbMultiPoint      uint32 = 4
+	wkbMultiLineString uint32 = 5
+	wkbMultiPolygon    uint32 = 6
+	ewkbSRIDFlag       uint32 = 0x20000000
+	ewkbZFlag          uint32 = 0x80000000
+	ewkbMFlag          uint32 = 0x40000000
+)
+
+type GeometryType string
+
+func (t GeometryType) wkb() (v uint32) {
+	switch t {
+	case PointGeometry:
+		v = wkbPoint
+		break
+	case LineStringGeometry:
+		v = wkbLineString
+		break
+	case PolygonGeometry:
+		v = wkbPolygon
+		break
+	case MultiPointGeometry:
+		v = wkbMultiPoint
+		break
+	case MultiLineStringGeometry:
+		v = wkbMultiLineString
+		break
+	case MultiPolygonGeometry:
+		v = wkbMultiPolygon
+		break
+	}
+	return
+}
+
+// Position
+// [x, y], when srid is 4326 then it is [longitude, latitude]
+type Position [2]float64
+
+func (p Position) X() float64 {
+	return p[0]
+}
+
+func (p Position) Y() float64 {
+	return p[1]
+}
+
+func NewPoint(x float64, y float64) Geometry {
+	return Geometry{
+		Type:  PointGeometry,
+		SRID:  WGS84,
+		Point: Position{x, y},
+	}
+}
+
+func NewLineString(positions ...Position) Geometry {
+	return Geometry{
+		Type: LineStringGeometry,
+		SRID: WGS84,
+		Path: positions,
+	}
+}
+
+// NewPolygon
+// first ring is exterior ring, others are holes.
+func NewPolygon(rings ...[]Position) Geometry {
+	return Geometry{
+		Type:  PolygonGeometry,
+		SRID:  WGS84,
+		Paths: rings,
+	}
+}
+
+func NewMultiPoint(positions ...Position) Geometry {
+	return Geometry{
+		Type: MultiPointGeometry,
+		SRID: WGS84,
+		Path: positions,
+	}
+}
+
+func NewMultiLineString(lines ...[]Position) Geometry {
+	return Geometry{
+		Type:  MultiLineStringGeometry,
+		SRID:  WGS84,
+		Paths: lines,
+	}
+}
+
+func NewMultiPolygon(polygons ...[][]Position) Geometry {
+	return Geometry{
+		Type:     MultiPolygonGeometry,
+		SRID:     WGS84,
+		Polygons: polygons,
+	}
+}
+
+// Geometry
+// 2D geometry value, encoded as wkb in database and as geojson in json.
+// Point uses Point, LineString and MultiPoint use Path, Polygon and MultiLineString use Paths, MultiPolygon uses Polygons.
+type Geometry struct {
+	Type     GeometryType
+	SRID     int
+	Point    Position
+	Path     []Position
+	Paths    [][]Position
+	Polygons [][][]Position
+}
+
+func (g Geometry) WithSRID(srid int) Geometry {
+	g.SRID = srid
+	return g
+}
+
+func (g Geometry) IsEmpty() bool {
+	return g.Type == ""
+}
+
+// WKB
+// little endian ogc well-known binary without srid
+func (g Geometry) WKB() (p []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 32))
+	err = g.writeWKB(buf, false)
+	if err != nil {
+		return
+	}
+	p = buf.Bytes()
+	return
+}
+
+// EWKB
+// postgis extended well-known binary, contains srid when srid is not zero
+func (g Geometry) EWKB() (p []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 36))
+	err = g.writeWKB(buf, g.SRID > 0)
+	if err != nil {
+		return
+	}
+	p = buf.Bytes()
+	return
+}
+
+// MysqlInternal
+// mysql internal geometry format, which is 4 bytes srid and wkb
+func (g Geometry) MysqlInternal() (p []byte, err error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 36))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(g.SRID))
+	err = g.writeWKB(buf, false)
+	if err != nil {
+		return
+	}
+	p = buf.Bytes()
+	return
+}
+
+func (g Geometry) writeWKB(buf *bytes.Buffer, withSRID bool) (err error) {
+	typ := g.Type.wkb()
+	if typ == 0 {
+		err = errors.Warning("sql: encode geometry failed").WithCause(fmt.Errorf("%s type is not supported", g.Type))
+		return
+	}
+	buf.WriteByte(1)
+	if withSRID {
+		_ = binary.Write(buf, binary.LittleEndian, typ|ewkbSRIDFlag)
+		_ = binary.Write(buf, binary.LittleEndian, uint32(g.SRID))
+	} else {
+		_ = binary.Write(buf, binary.LittleEndian, typ)
+	}
+	switch g.Type {
+	case PointGeometry:
+		writeWKBPosition(buf, g.Point)
+		break
+	case LineStringGeometry:
+		writeWKBPositions(buf, g.Path)
+		break
+	case PolygonGeometry:
+		writeWKBRings(buf, g.Paths)
+		break
+	case MultiPointGeometry:
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(g.Path)))
+		for _, position := range g.Path {
+			_ = Geometry{Type: PointGeometry, Point: position}.writeWKB(buf, false)
+		}
+		break
+	case MultiLineStringGeometry:
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(g.Paths)))
+		for _, line := range g.Paths {
+			_ = Geometry{Type: LineStringGeometry, Path: line}.writeWKB(buf, false)
+		}
+		break
+	case MultiPolygonGeometry:
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(g.Polygons)))
+		for _, polygon := range g.Polygons {
+			_ = Geometry{Type: PolygonGeometry, Paths: polygon}.writeWKB(buf, false)
+		}
+		break
+	}
+	return
+}
+
+func writeWKBPosition(buf *bytes.Buffer, position Position) {
+	_ = binary.Write(buf, binary.LittleEndian, position[0])
+	_ = binary.Write(buf, binary.LittleEndian, position[1])
+}
+
+func writeWKBPositions(buf *bytes.Buffer, positions []Position) {
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(positions)))
+	for _, position := range positions {
+		writeWKBPosition(buf, position)
+	}
+}
+
+func writeWKBRings(buf *bytes.Buffer, rings [][]Position) {
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(rings)))
+	for _, ring := range rings {
+		writeWKBPositions(buf, ring)
+	}
+}
+
+// Scan
+// support wkb, postgis ewkb (hex or binary) and mysql internal format.
+func (g *Geometry) Scan(src any) (err error) {
+	if src == nil {
+		return
+	}
+	var p []byte
+	switch s := src.(type) {
+	case []byte:
+		p = s
+		break
+	case string:
+		p = []byte(s)
+		break
+	default:
+		err = errors.Warning("sql: scan geometry failed").WithCause(fmt.Errorf("%T is not supported", src))
+		return
+	}
+	if len(p) == 0 {
+		return
+	}
+	if isHexGeometry(p) {
+		decoded := make([]byte, hex.DecodedLen(len(p)))
+		_, decodeErr := hex.Decode(decoded, p)
+		if decodeErr != nil {
+			err = errors.Warning("sql: scan geometry failed").WithCause(decodeErr)
+			return
+		}
+		p = decoded
+	}
+	// wkb or ewkb
+	if p[0] == 0 || p[0] == 1 {
+		r := &wkbReader{p: p}
+		v, readErr := r.read()
+		if readErr == nil && r.pos == len(p) {
+			*g = v
+			return
+		}
+	}
+	// mysql internal
+	if len(p) > 5 {
+		srid := binary.LittleEndian.Uint32(p[0:4])
+		r := &wkbReader{p: p[4:]}
+		v, readErr := r.read()
+		if readErr != nil {
+			err = errors.Warning("sql: scan geometry failed").WithCause(readErr)
+			return
+		}
+		v.SRID = int(srid)
+		*g = v
+		return
+	}
+	err = errors.Warning("sql: scan geometry failed").WithCause(fmt.Errorf("invalid geometry value"))
+	return
+}
+
+func isHexGeometry(p []byte) bool {
+	if len(p)%2 != 0 || len(p) < 10 {
+		return false
+	}
+	// byte order of wkb is 00 or 01
+	if p[0] != '0' || (p[1] != '0' && p[1] != '1') {
+		return false
+	}
+	for _, b := range p {
+		if !((b >= '0' && b <= '9') || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
+
+// Value
+// hex ewkb, which can be used as geometry or geography text input of postgis.
+func (g Geometry) Value() (driver.Value, error) {
+	if g.IsEmpty() {
+		return nil, nil
+	}
+	p, err := g.EWKB()
+	if err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString(p), nil
+}
+
+type wkbReader struct {
+	p     []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) uint32() (v uint32, err error) {
+	if r.pos+4 > len(r.p) {
+		err = fmt.Errorf("unexpected end of wkb")
+		return
+	}
+	v = r.order.Uint32(r.p[r.pos:])
+	r.pos += 4
+	return
+}
+
+// count
+// reads number of elements, and checks it by remaining bytes, cause it is untrusted.
+func (r *wkbReader) count(size int) (n uint32, err error) {
+	n, err = r.uint32()
+	if err != nil {
+		return
+	}
+	if uint64(n)*uint64(size) > uint64(len(r.p)-r.pos) {
+		err = fmt.Errorf("invalid number of elements of wkb")
+		return
+	}
+	return
+}
+
+func (r *wkbReader) position() (v Position, err error) {
+	if r.pos+16 > len(r.p) {
+		err = fmt.Errorf("unexpected end of wkb")
+		return
+	}
+	v[0] = math.Float64frombits(r.order.Uint64(r.p[r.pos:]))
+	v[1] = math.Float64frombits(r.order.Uint64(r.p[r.pos+8:]))
+	r.pos += 16
+	return
+}
+
+func (r *wkbReader) positions() (v []Position, err error) {
+	n, nErr := r.count(16)
+	if nErr != nil {
+		err = nErr
+		return
+	}
+	v = make([]Position, 0, n)
+	for i := uint32(0); i < n; i++ {
+		position, positionErr := r.position()
+		if positionErr != nil {
+			err = positionErr
+			return
+		}
+		v = append(v, position)
+	}
+	return
+}
+
+func (r *wkbReader) rings() (v [][]Position, err error) {
+	n, nErr := r.count(4)
+	if nErr != nil {
+		err = nErr
+		return
+	}
+	v = make([][]Position, 0, n)
+	for i := uint32(0); i < n; i++ {
+		ring, ringErr := r.positions()
+		if ringErr != nil {
+			err = ringErr
+			return
+		}
+		v = append(v, ring)
+	}
+	return
+}
+
+func (r *wkbReader) read() (g Geometry, err error) {
+	if r.pos >= len(r.p) {
+		err = fmt.Errorf("unexpected end of wkb")
+		return
+	}
+	switch r.p[r.pos] {
+	case 0:
+		r.order = binary.BigEndian
+		break
+	case 1:
+		r.order = binary.LittleEndian
+		break
+	default:
+		err = fmt.Errorf("invalid byte order of wkb")
+		return
+	}
+	r.pos++
+	typ, typErr := r.uint32()
+	if typErr != nil {
+		err = typErr
+		return
+	}
+	if typ&(ewkbZFlag|ewkbMFlag) != 0 {
+		err = fmt.Errorf("only 2d geometry is supported")
+		return
+	}
+	if typ&ewkbSRIDFlag != 0 {
+		srid, sridErr := r.uint32()
+		if sridErr != nil {
+			err = sridErr
+			return
+		}
+		g.SRID = int(srid)
+		typ = typ &^ ewkbSRIDFlag
+	}
+	if typ > 1000 {
+		err = fmt.Errorf("only 2d geometry is supported")
+		return
+	}
+	switch typ {
+	case wkbPoint:
+		g.Type = PointGeometry
+		g.Point, err = r.position()
+		break
+	case wkbLineString:
+		g.Type = LineStringGeometry
+		g.Path, err = r.positions()
+		break
+	case wkbPolygon:
+		g.Type = PolygonGeometry
+		g.Paths, err = r.rings()
+		break
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon:
+		n, nErr := r.count(5)
+		if nErr != nil {
+			err = nErr
+			return
+		}
+		for i := uint32(0); i < n; i++ {
+			sub, subErr := r.read()
+			if subErr != nil {
+				err = subErr
+				return
+			}
+			switch typ {
+			case wkbMultiPoint:
+				g.Path = append(g.Path, sub.Point)
+				break
+			case wkbMultiLineString:
+				g.Paths = append(g.Paths, sub.Path)
+				break
+			default:
+				g.Polygons = append(g.Polygons, sub.Paths)
+				break
+			}
+		}
+		switch typ {
+		case wkbMultiPoint:
+			g.Type = MultiPointGeometry
+			break
+		case wkbMultiLineString:
+			g.Type = MultiLineStringGeometry
+			break
+		default:
+			g.Type = MultiPolygonGeometry
+			break
+		}
+		break
+	default:
+		err = fmt.Errorf("%d type of wkb is not supported", typ)
+		return
+	}
+	return
+}
+
+type geoJson struct {
+	Type        GeometryType    `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// MarshalJSON
+// encode to geojson geometry object
+func (g Geometry) MarshalJSON() (p []byte, err error) {
+	if g.IsEmpty() {
+		p = json.NullBytes
+		return
+	}
+	var coordinates any
+	switch g.Type {
+	case PointGeometry:
+		coordinates = g.Point
+		break
+	case LineStringGeometry, MultiPointGeometry:
+		coordinates = g.Path
+		break
+	case PolygonGeometry, MultiLineStringGeometry:
+		coordinates = g.Paths
+		break
+	case MultiPolygonGeometry:
+		coordinates = g.Polygons
+		break
+	default:
+		err = errors.Warning("sql: encode geometry failed").WithCause(fmt.Errorf("%s type is not supported", g.Type))
+		return
+	}
+	cp, encodeErr := json.Marshal(coordinates)
+	if encodeErr != nil {
+		err = errors.Warning("sql: encode geometry failed").WithCause(encodeErr)
+		return
+	}
+	p, err = json.Marshal(geoJson{
+		Type:        g.Type,
+		Coordinates: cp,
+	})
+	return
+}
+
+// UnmarshalJSON
+// decode from geojson geometry object, srid is 4326.
+func (g *Geometry) UnmarshalJSON(p []byte) (err error) {
+	if len(p) == 0 || bytes.Equal(p, json.NullBytes) {
+		return
+	}
+	v := geoJson{}
+	err = json.Unmarshal(p, &v)
+	if err != nil {
+		err = errors.Warning("sql: decode geometry failed").WithCause(err)
+		return
+	}
+	r := Geometry{
+		Type: v.Type,
+		SRID: WGS84,
+	}
+	switch v.Type {
+	case PointGeometry:
+		err = json.Unmarshal(v.Coordinates, &r.Point)
+		break
+	case LineStringGeometry, MultiPointGeometry:
+		err = json.Unmarshal(v.Coordinates, &r.Path)
+		break
+	case PolygonGeometry, MultiLineStringGeometry:
+		err = json.Unmarshal(v.Coordinates, &r.Paths)
+		break
+	case MultiPolygonGeometry:
+		err = json.Unmarshal(v.Coordinates, &r.Polygons)
+		break
+	default:
+		err = fmt.Errorf("%s type is not supported", v.Type)
+		break
+	}
+	if err != nil {
+		err = errors.Warning("sql: decode geometry failed").WithCause(err)
+		return
+	}
+	*g = r
+	return
+}
+
+// String
+// well-known text
+func (g Geometry) String() string {
+	if g.IsEmpty() {
+		return ""
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, 64))
+	buf.WriteString(strings.ToUpper(string(g.Type)))
+	switch g.Type {
+	case PointGeometry:
+		writeWKTPositions(buf, []Position{g.Point})
+		break
+	case LineStringGeometry, MultiPointGeometry:
+		writeWKTPositions(buf, g.Path)
+		break
+	case PolygonGeometry, MultiLineStringGeometry:
+		writeWKTRings(buf, g.Paths)
+		break
+	case MultiPolygonGeometry:
+		buf.WriteByte('(')
+		for i, polygon := range g.Polygons {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeWKTRings(buf, polygon)
+		}
+		buf.WriteByte(')')
+		break
+	}
+	return buf.String()
+}
+
+func writeWKTPositions(buf *bytes.Buffer, positions []Position) {
+	buf.WriteByte('(')
+	for i, position := range positions {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strconv.FormatFloat(position[0], 'f', -1, 64))
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatFloat(position[1], 'f', -1, 64))
+	}
+	buf.WriteByte(')')
+}
+
+func writeWKTRings(buf *bytes.Buffer, rings [][]Position) {
+	buf.WriteByte('(')
+	for i, ring := range rings {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeWKTPositions(buf, ring)
+	}
+	buf.WriteByte(')')
+}
@@ -0,0 +1,36 @@
+package sql_test
+
+import (
+	"encoding/binary"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"testing"
+)
+
+func TestGeometry_Scan(t *testing.T) {
+	polygon := sql.NewPolygon([]sql.Position{{0, 0}, {0, 1}, {1, 1}, {0, 0}}).WithSRID(4326)
+	p, encodeErr := polygon.EWKB()
+	if encodeErr != nil {
+		t.Errorf("%+v", encodeErr)
+		return
+	}
+	scanned := sql.Geometry{}
+	if scanErr := scanned.Scan(p); scanErr != nil {
+		t.Errorf("%+v", scanErr)
+		return
+	}
+	if scanned.String() != polygon.String() {
+		t.Errorf("geometry is not matched, expected %s, got %s", polygon, scanned)
+		return
+	}
+}
+
+func TestGeometry_ScanInvalidCount(t *testing.T) {
+	// little endian linestring with a count much larger than remaining bytes
+	p := []byte{1, 2, 0, 0, 0}
+	p = binary.LittleEndian.AppendUint32(p, 1<<30)
+	scanned := sql.Geometry{}
+	if scanErr := scanned.Scan(p); scanErr == nil {
+		t.Errorf("invalid count must be failed")
+		return
+	}
+}
@@ -11,6 +11,7 @@ require (
 	github.com/aacfactory/gcg v1.0.5
 	github.com/aacfactory/json v1.16.9
 	github.com/aacfactory/logs v1.13.13
+	github.com/klauspost/compress v1.17.7
 	github.com/valyala/bytebufferpool v1.0.0
 	golang.org/x/sync v0.7.0
 )
@@ -0,0 +1,335 @@
+package sql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns/context"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// QueryPlaceholder
+// placeholder of dialect, such as `?` or `$n`.
+type QueryPlaceholder interface {
+	Next() (v string)
+}
+
+// BackslashEscapes
+// placeholder which implements it and returns true tells Preprocess that backslash escapes quote in string literals, such as mysql.
+type BackslashEscapes interface {
+	BackslashEscapes() bool
+}
+
+var (
+	placeholders = sync.Map{}
+)
+
+// RegisterQueryPlaceholder
+// dac dialect is registered automatically when RegisterDialect.
+func RegisterQueryPlaceholder(dialect string, fn func() QueryPlaceholder) {
+	if dialect == "" || fn == nil {
+		return
+	}
+	placeholders.Store(dialect, fn)
+}
+
+func getQueryPlaceholder(dialect string) (ph QueryPlaceholder, has bool) {
+	v, exist := placeholders.Load(dialect)
+	if !exist {
+		return
+	}
+	ph = v.(func() QueryPlaceholder)()
+	has = true
+	return
+}
+
+// Named
+// named argument, it is bound to `:name` or `@name` in query.
+func Named(name string, value any) sql.NamedArg {
+	return sql.Named(name, value)
+}
+
+// List
+// list argument, it is expanded into placeholder list, such as `IN (?)` is rewritten to `IN (?, ?, ?)`.
+// when values is empty, then it is rendered as NULL.
+// note: plain slice argument is not expanded, it is bound as array by driver, such as `= ANY($1)` of postgres.
+func List(values any) ListArgument {
+	return ListArgument{
+		values: values,
+	}
+}
+
+type ListArgument struct {
+	values any
+}
+
+type questionPlaceholder struct{}
+
+func (ph *questionPlaceholder) Next() string {
+	return "?"
+}
+
+type numberedPlaceholder struct {
+	count int
+}
+
+func (ph *numberedPlaceholder) Next() string {
+	ph.count++
+	return "$" + strconv.Itoa(ph.count)
+}
+
+func preprocess(ctx context.Context, query []byte, arguments []any) (v []byte, args []any, err error) {
+	if !needPreprocess(arguments) {
+		v, args = query, arguments
+		return
+	}
+	dialect, dialectErr := Dialect(ctx)
+	if dialectErr != nil {
+		err = errors.Warning("sql: preprocess query failed").WithCause(dialectErr)
+		return
+	}
+	ph, has := getQueryPlaceholder(dialect)
+	if !has {
+		if bytes.Contains(query, []byte("$1")) {
+			ph = &numberedPlaceholder{}
+		} else {
+			ph = &questionPlaceholder{}
+		}
+	}
+	v, args, err = Preprocess(ph, query, arguments)
+	return
+}
+
+func needPreprocess(arguments []any) bool {
+	for _, argument := range arguments {
+		if argument == nil {
+			continue
+		}
+		switch argument.(type) {
+		case sql.NamedArg, ListArgument:
+			return true
+		}
+		if isBindingArgument(argument) {
+			return true
+		}
+	}
+	return false
+}
+
+func isBindingArgument(argument any) bool {
+	rt := reflect.TypeOf(argument)
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	switch rt.Kind() {
+	case reflect.Map:
+		return rt.Key().Kind() == reflect.String
+	case reflect.Struct:
+		if rt.Implements(valuerType) || reflect.PointerTo(rt).Implements(valuerType) {
+			return false
+		}
+		if rt.ConvertibleTo(datetimeType) || rt.ConvertibleTo(dateType) || rt.ConvertibleTo(timeType) ||
+			rt.ConvertibleTo(jsonDateType) || rt.ConvertibleTo(jsonTimeType) {
+			return false
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+type preprocessBinding struct {
+	positional []any
+	named      map[string]any
+	structs    []reflect.Value
+}
+
+func (binding *preprocessBinding) lookup(name string) (v any, has bool) {
+	v, has = binding.named[name]
+	if has {
+		return
+	}
+	for _, sv := range binding.structs {
+		if sv.Kind() == reflect.Map {
+			mv := sv.MapIndex(reflect.ValueOf(name).Convert(sv.Type().Key()))
+			if mv.IsValid() {
+				v = mv.Interface()
+				has = true
+				return
+			}
+			continue
+		}
+		v, has = lookupStructField(sv, name)
+		if has {
+			return
+		}
+	}
+	return
+}
+
+func lookupStructField(sv reflect.Value, name string) (v any, has bool) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && isBindingArgument(sv.Field(i).Interface()) {
+			v, has = lookupStructField(sv.Field(i), name)
+			if has {
+				return
+			}
+			continue
+		}
+		matched := field.Name == name
+		if !matched {
+			if tag, ok := field.Tag.Lookup("column"); ok {
+				tag = strings.TrimSpace(strings.Split(tag, ",")[0])
+				matched = tag == name || strings.EqualFold(tag, name)
+			}
+		}
+		if !matched {
+			if tag, ok := field.Tag.Lookup("json"); ok {
+				matched = strings.TrimSpace(strings.Split(tag, ",")[0]) == name
+			}
+		}
+		if !matched {
+			matched = strings.EqualFold(field.Name, name)
+		}
+		if matched {
+			v = sv.Field(i).Interface()
+			has = true
+			return
+		}
+	}
+	return
+}
+
+// Preprocess
+// rewrite `:name` and `@name` parameters into placeholders of dialect, and expand List arguments into placeholder lists.
+// named values are from sql.NamedArg, struct or map[string]any arguments, others are positional arguments of `?` or `$n`.
+// when List is empty, then it is rendered as NULL, other slice arguments are bound as they are.
+// string literals, quoted idents, comments and postgres dollar quoted strings are skipped.
+// note: when named values are used, mysql user variables such as `@var` are treated as parameters, so do not mix them, `@@var` system variables are skipped.
+func Preprocess(ph QueryPlaceholder, query []byte, arguments []any) (v []byte, args []any, err error) {
+	binding := preprocessBinding{
+		positional: make([]any, 0, len(arguments)),
+		named:      make(map[string]any),
+	}
+	for _, argument := range arguments {
+		if argument == nil {
+			binding.positional = append(binding.positional, argument)
+			continue
+		}
+		if named, ok := argument.(sql.NamedArg); ok {
+			binding.named[named.Name] = named.Value
+			continue
+		}
+		if list, ok := argument.(ListArgument); ok {
+			binding.positional = append(binding.positional, list)
+			continue
+		}
+		if isBindingArgument(argument) {
+			binding.structs = append(binding.structs, reflect.Indirect(reflect.ValueOf(argument)))
+			continue
+		}
+		binding.positional = append(binding.positional, argument)
+	}
+	hasNamed := len(binding.named) > 0 || len(binding.structs) > 0
+	backslash := false
+	if be, ok := ph.(BackslashEscapes); ok {
+		backslash = be.BackslashEscapes()
+	}
+	tokens, lexErr := databases.LexQuery(query, backslash)
+	if lexErr != nil {
+		err = errors.Warning("sql: preprocess query failed").WithCause(lexErr)
+		return
+	}
+	// `?` is an operator of jsonb in postgres, so it is not a placeholder when query uses `$n` or `?n`
+	numbered := false
+	for _, token := range tokens {
+		if token.Kind == databases.NumberedPlaceholderQueryToken {
+			numbered = true
+			break
+		}
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(query)+16))
+	args = make([]any, 0, len(arguments))
+	write := func(value any) {
+		if list, ok := value.(ListArgument); ok {
+			rv := reflect.ValueOf(list.values)
+			if rv.IsValid() && rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				buf.WriteString(ph.Next())
+				args = append(args, list.values)
+				return
+			}
+			if !rv.IsValid() || rv.Len() == 0 {
+				buf.WriteString("NULL")
+				return
+			}
+			for i := 0; i < rv.Len(); i++ {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				buf.WriteString(ph.Next())
+				args = append(args, rv.Index(i).Interface())
+			}
+			return
+		}
+		buf.WriteString(ph.Next())
+		args = append(args, value)
+	}
+
+	cursor := 0
+	for _, token := range tokens {
+		switch token.Kind {
+		case databases.NumberedPlaceholderQueryToken:
+			n, _ := strconv.Atoi(string(token.Value[1:]))
+			if n < 1 || n > len(binding.positional) {
+				err = errors.Warning("sql: preprocess query failed").WithCause(fmt.Errorf("%s is out of range of arguments", token.Value))
+				return
+			}
+			write(binding.positional[n-1])
+			if n > cursor {
+				cursor = n
+			}
+			break
+		case databases.PlaceholderQueryToken:
+			if numbered {
+				buf.Write(token.Value)
+				break
+			}
+			if cursor >= len(binding.positional) {
+				err = errors.Warning("sql: preprocess query failed").WithCause(fmt.Errorf("arguments are not enough"))
+				return
+			}
+			write(binding.positional[cursor])
+			cursor++
+			break
+		case databases.NamedQueryToken:
+			if !hasNamed {
+				buf.Write(token.Value)
+				break
+			}
+			name := string(token.Value[1:])
+			value, has := binding.lookup(name)
+			if !has {
+				err = errors.Warning("sql: preprocess query failed").WithCause(fmt.Errorf("%s was not found in arguments", name))
+				return
+			}
+			write(value)
+			break
+		default:
+			buf.Write(token.Value)
+			break
+		}
+	}
+	v = buf.Bytes()
+	return
+}
@@ -0,0 +1,124 @@
+package sql_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type numbered struct {
+	n int
+}
+
+func (ph *numbered) Next() string {
+	ph.n++
+	return "$" + strconv.Itoa(ph.n)
+}
+
+type question struct {
+	backslash bool
+}
+
+func (ph *question) Next() string {
+	return "?"
+}
+
+func (ph *question) BackslashEscapes() bool {
+	return ph.backslash
+}
+
+func assertPreprocess(t *testing.T, ph sql.QueryPlaceholder, query string, arguments []any, expected string, expectedArgs ...any) {
+	t.Helper()
+	v, args, err := sql.Preprocess(ph, []byte(query), arguments)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if string(v) != expected {
+		t.Errorf("query is not matched\n expected: %s\n      got: %s", expected, v)
+	}
+	if len(args) != len(expectedArgs) || (len(args) > 0 && !reflect.DeepEqual(args, expectedArgs)) {
+		t.Errorf("arguments are not matched, expected %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestPreprocess_Named(t *testing.T) {
+	assertPreprocess(t, &numbered{},
+		`SELECT * FROM "USER" WHERE "AGE" > :age AND "ID" IN (:ids) AND "NAME"::text <> ':age'`,
+		[]any{sql.Named("age", 18), sql.Named("ids", sql.List([]string{"1", "2"}))},
+		`SELECT * FROM "USER" WHERE "AGE" > $1 AND "ID" IN ($2, $3) AND "NAME"::text <> ':age'`,
+		18, "1", "2",
+	)
+}
+
+func TestPreprocess_DollarQuoted(t *testing.T) {
+	assertPreprocess(t, &numbered{},
+		`SELECT $$it's :age$$, $fn$ @age $fn$ WHERE "AGE" > :age`,
+		[]any{sql.Named("age", 18)},
+		`SELECT $$it's :age$$, $fn$ @age $fn$ WHERE "AGE" > $1`,
+		18,
+	)
+}
+
+func TestPreprocess_BackslashEscapes(t *testing.T) {
+	assertPreprocess(t, &question{backslash: true},
+		`SELECT * FROM USER WHERE NAME = 'it\'s :age' AND AGE > :age`,
+		[]any{sql.Named("age", 18)},
+		`SELECT * FROM USER WHERE NAME = 'it\'s :age' AND AGE > ?`,
+		18,
+	)
+	// standard string, backslash is literal
+	assertPreprocess(t, &question{},
+		`SELECT * FROM USER WHERE PATH = 'C:\' AND AGE > :age`,
+		[]any{sql.Named("age", 18)},
+		`SELECT * FROM USER WHERE PATH = 'C:\' AND AGE > ?`,
+		18,
+	)
+	// postgres escape string
+	assertPreprocess(t, &numbered{},
+		`SELECT E'it\'s :age' WHERE AGE > :age`,
+		[]any{sql.Named("age", 18)},
+		`SELECT E'it\'s :age' WHERE AGE > $1`,
+		18,
+	)
+}
+
+func TestPreprocess_SystemVariable(t *testing.T) {
+	assertPreprocess(t, &question{backslash: true},
+		`SELECT @@session.time_zone WHERE AGE > @age`,
+		[]any{sql.Named("age", 18)},
+		`SELECT @@session.time_zone WHERE AGE > ?`,
+		18,
+	)
+}
+
+func TestPreprocess_List(t *testing.T) {
+	assertPreprocess(t, &question{},
+		`SELECT * FROM user WHERE id IN (?) AND age > ?`,
+		[]any{sql.List([]int{1, 2}), 18},
+		`SELECT * FROM user WHERE id IN (?, ?) AND age > ?`,
+		1, 2, 18,
+	)
+	assertPreprocess(t, &question{},
+		`SELECT * FROM user WHERE id IN (?)`,
+		[]any{sql.List([]int{})},
+		`SELECT * FROM user WHERE id IN (NULL)`,
+	)
+}
+
+func TestPreprocess_Array(t *testing.T) {
+	// plain slice is bound as array, so it is not expanded
+	assertPreprocess(t, &numbered{},
+		`SELECT * FROM t WHERE id = ANY($1) AND x = $2`,
+		[]any{[]string{"a", "b", "c"}, 1},
+		`SELECT * FROM t WHERE id = ANY($1) AND x = $2`,
+		[]string{"a", "b", "c"}, 1,
+	)
+	assertPreprocess(t, &numbered{},
+		`SELECT * FROM t WHERE id = ANY(:ids) AND x = :x`,
+		[]any{sql.Named("ids", []string{"a", "b"}), sql.Named("x", 1)},
+		`SELECT * FROM t WHERE id = ANY($1) AND x = $2`,
+		[]string{"a", "b"}, 1,
+	)
+}
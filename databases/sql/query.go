@@ -19,6 +19,12 @@ var (
 )
 
 func Query(ctx context.Context, query []byte, arguments ...interface{}) (v Rows, err error) {
+	query, arguments, err = preprocess(ctx, query, arguments)
+	if err != nil {
+		err = errors.Warning("sql: query failed").WithCause(err).WithMeta("query", bytex.ToString(query))
+		return
+	}
+	sharding, _ := LoadSharding(ctx)
 	tx, hasTx := loadTransaction(ctx)
 	if hasTx {
 		var log logs.Logger
@@ -30,17 +36,35 @@ func Query(ctx context.Context, query []byte, arguments ...interface{}) (v Rows,
 				handleBegin = time.Now()
 			}
 		}
-		rows, queryErr := tx.Query(context.TODO(), query, arguments)
+		timeout := loadTimeout(ctx)
+		if timeout <= 0 {
+			timeout = tx.StatementTimeout
+		}
+		if dialect, dialectErr := Dialect(ctx); dialectErr == nil {
+			query = statementTimeoutHint(dialect, query, timeout)
+			if sessionErr := useStatementTimeout(ctx, dialect, tx, timeout); sessionErr != nil {
+				err = errors.Warning("sql: query failed").WithCause(sessionErr)
+				return
+			}
+		}
+		stmtCtx, cancel := statementContext(ctx, sharding, timeout)
+		rows, queryErr := tx.Query(stmtCtx, query, arguments)
 		if debug && log.DebugEnabled() {
 			latency := time.Now().Sub(handleBegin)
 			log.Debug().With("succeed", queryErr == nil).With("latency", latency.String()).With("transaction", tx.Id).
 				Message(fmt.Sprintf("query debug log:\n- query:\n  %s\n- arguments:\n  %s\n", bytex.ToString(query), fmt.Sprintf("%+v", arguments)))
 		}
 		if queryErr != nil {
+			cancel()
 			err = errors.Warning("sql: query failed").WithCause(queryErr).WithMeta("query", bytex.ToString(query))
 			return
 		}
-		v, err = NewRows(rows)
+		v, err = NewRows(&cancelableRows{Rows: rows, cancel: cancel})
+		if err != nil {
+			err = errors.Warning("sql: query failed").WithCause(err).WithMeta("query", bytex.ToString(query))
+			return
+		}
+		err = v.merge(sharding.Merge)
 		if err != nil {
 			err = errors.Warning("sql: query failed").WithCause(err).WithMeta("query", bytex.ToString(query))
 			return
@@ -48,6 +72,7 @@ func Query(ctx context.Context, query []byte, arguments ...interface{}) (v Rows,
 		return
 	}
 	options := make([]services.RequestOption, 0, 1)
+	getOptions := make([]services.EndpointGetOption, 0, 1)
 	info, hasInfo, loadInfoErr := loadTransactionInfo(ctx)
 	if loadInfoErr != nil {
 		err = errors.Warning("sql: query failed").WithCause(loadInfoErr)
@@ -55,24 +80,50 @@ func Query(ctx context.Context, query []byte, arguments ...interface{}) (v Rows,
 	}
 	if hasInfo {
 		options = append(options, services.WithEndpointId(bytex.FromString(info.EndpointId)))
+		getOptions = append(getOptions, services.EndpointId(bytex.FromString(info.EndpointId)))
 	}
 	eps := runtime.Endpoints(ctx)
 	param := queryParam{
 		Query:     bytex.ToString(query),
 		Arguments: Arguments(arguments),
+		Sharding:  sharding,
+		Timeout:   loadTimeout(ctx),
 	}
 	ep := endpointName
 	if epn := used(ctx); len(epn) > 0 {
 		ep = epn
 	}
-	response, handleErr := eps.Request(ctx, ep, queryFnName, param, options...)
-	if handleErr != nil {
-		err = handleErr
-		return
+	if endpoint, has := eps.Get(ctx, ep, getOptions...); has && columnarSupported(endpoint) {
+		response, handleErr := eps.Request(ctx, ep, queryColumnarFnName, param, options...)
+		if handleErr != nil {
+			err = handleErr
+			return
+		}
+		p, responseErr := services.ValueOfResponse[[]byte](response)
+		if responseErr != nil {
+			err = errors.Warning("sql: query failed").WithCause(responseErr)
+			return
+		}
+		v, err = decodeColumnarRows(p)
+		if err != nil {
+			err = errors.Warning("sql: query failed").WithCause(err)
+			return
+		}
+	} else {
+		response, handleErr := eps.Request(ctx, ep, queryFnName, param, options...)
+		if handleErr != nil {
+			err = handleErr
+			return
+		}
+		v, err = services.ValueOfResponse[Rows](response)
+		if err != nil {
+			err = errors.Warning("sql: query failed").WithCause(err)
+			return
+		}
 	}
-	v, err = services.ValueOfResponse[Rows](response)
+	err = v.merge(sharding.Merge)
 	if err != nil {
-		err = errors.Warning("sql: query failed").WithCause(err)
+		err = errors.Warning("sql: query failed").WithCause(err).WithMeta("query", bytex.ToString(query))
 		return
 	}
 	return
@@ -81,13 +132,19 @@ func Query(ctx context.Context, query []byte, arguments ...interface{}) (v Rows,
 type queryParam struct {
 	Query     string    `json:"query" avro:"query"`
 	Arguments Arguments `json:"arguments" avro:"arguments"`
+	Sharding  Sharding  `json:"sharding" avro:"sharding"`
+	// Timeout
+	// timeout of statement which is set by WithTimeout, zero means using statementTimeout of config.
+	Timeout time.Duration `json:"timeout" avro:"timeout"`
 }
 
 type queryFn struct {
-	debug bool
-	log   logs.Logger
-	db    databases.Database
-	group *transactions.Group
+	debug   bool
+	log     logs.Logger
+	db      databases.Database
+	group   *transactions.Group
+	dialect string
+	timeout time.Duration
 }
 
 func (fn *queryFn) Name() string {
@@ -112,6 +169,11 @@ func (fn *queryFn) Handle(r services.Request) (v interface{}, err error) {
 		err = errors.Warning("sql: query failed").WithCause(fmt.Errorf("query is required"))
 		return
 	}
+	timeout := param.Timeout
+	if timeout <= 0 {
+		timeout = fn.timeout
+	}
+	query := statementTimeoutHint(fn.dialect, bytex.FromString(param.Query), timeout)
 	info, has, loadErr := loadTransactionInfo(r)
 	if loadErr != nil {
 		err = errors.Warning("sql: query failed").WithCause(loadErr)
@@ -125,17 +187,23 @@ func (fn *queryFn) Handle(r services.Request) (v interface{}, err error) {
 				useDebugLog(r)
 				handleBegin = time.Now()
 			}
-			rows, queryErr := tx.Query(context.TODO(), bytex.FromString(param.Query), param.Arguments)
+			if sessionErr := useStatementTimeout(r, fn.dialect, tx, timeout); sessionErr != nil {
+				err = errors.Warning("sql: query failed").WithCause(sessionErr)
+				return
+			}
+			stmtCtx, cancel := statementContext(r, param.Sharding, timeout)
+			rows, queryErr := tx.Query(stmtCtx, query, param.Arguments)
 			if fn.debug && fn.log.DebugEnabled() {
 				latency := time.Now().Sub(handleBegin)
 				fn.log.Debug().With("succeed", queryErr == nil).With("latency", latency.String()).With("transaction", info.Id).
 					Message(fmt.Sprintf("query debug log:\n- query:\n  %s\n- arguments:\n  %s\n", param.Query, fmt.Sprintf("%+v", param.Arguments)))
 			}
 			if queryErr != nil {
+				cancel()
 				err = errors.Warning("sql: query failed").WithCause(queryErr).WithMeta("query", param.Query)
 				return
 			}
-			v, err = NewRows(rows)
+			v, err = NewRows(&cancelableRows{Rows: rows, cancel: cancel})
 			if err != nil {
 				err = errors.Warning("sql: query failed").WithCause(err).WithMeta("query", param.Query)
 				return
@@ -148,17 +216,19 @@ func (fn *queryFn) Handle(r services.Request) (v interface{}, err error) {
 		useDebugLog(r)
 		handleBegin = time.Now()
 	}
-	rows, queryErr := fn.db.Query(context.TODO(), bytex.FromString(param.Query), param.Arguments)
+	stmtCtx, cancel := statementContext(r, param.Sharding, timeout)
+	rows, queryErr := fn.db.Query(stmtCtx, query, param.Arguments)
 	if fn.debug && fn.log.DebugEnabled() {
 		latency := time.Now().Sub(handleBegin)
 		fn.log.Debug().With("succeed", err == nil).With("latency", latency.String()).
 			Message(fmt.Sprintf("query debug log:\n- query:\n  %s\n- arguments:\n  %s\n", param.Query, fmt.Sprintf("%+v", param.Arguments)))
 	}
 	if queryErr != nil {
+		cancel()
 		err = errors.Warning("sql: query failed").WithCause(queryErr)
 		return
 	}
-	v, err = NewRows(rows)
+	v, err = NewRows(&cancelableRows{Rows: rows, cancel: cancel})
 	if err != nil {
 		err = errors.Warning("sql: query failed").WithCause(err)
 		return
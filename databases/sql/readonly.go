@@ -0,0 +1,42 @@
+package sql
+
+import (
+	"sync"
+)
+
+var (
+	readOnlyTransactions = sync.Map{}
+)
+
+// ReadOnlyTransaction
+// read only transaction of dialect.
+type ReadOnlyTransaction interface {
+	// ReadOnly
+	// statement which is executed at beginning of read only transaction, such as SET TRANSACTION READ ONLY of postgres,
+	// returns nil when it is not supported or driver has done it.
+	ReadOnly() []byte
+}
+
+// RegisterReadOnlyTransaction
+// dac dialect is registered automatically when RegisterDialect and dialect implements ReadOnlyTransaction.
+func RegisterReadOnlyTransaction(dialect string, readOnly ReadOnlyTransaction) {
+	if dialect == "" || readOnly == nil {
+		return
+	}
+	readOnlyTransactions.Store(dialect, readOnly)
+}
+
+func readOnlySession(dialect string) []byte {
+	if dialect == "" {
+		return nil
+	}
+	v, exist := readOnlyTransactions.Load(dialect)
+	if !exist {
+		return nil
+	}
+	readOnly, ok := v.(ReadOnlyTransaction)
+	if !ok {
+		return nil
+	}
+	return readOnly.ReadOnly()
+}
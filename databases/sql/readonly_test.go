@@ -0,0 +1,63 @@
+package sql
+
+import (
+	stdctx "context"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns-contrib/databases/sql/transactions"
+	"github.com/aacfactory/fns/context"
+	"testing"
+	"time"
+)
+
+type readOnlyTestTransaction struct {
+	queries  int
+	executes int
+}
+
+func (tx *readOnlyTestTransaction) Commit() error {
+	return nil
+}
+
+func (tx *readOnlyTestTransaction) Rollback() error {
+	return nil
+}
+
+func (tx *readOnlyTestTransaction) Query(_ stdctx.Context, _ []byte, _ []any) (rows databases.Rows, err error) {
+	tx.queries++
+	err = errors.Warning("sql: no rows in test")
+	return
+}
+
+func (tx *readOnlyTestTransaction) Execute(_ stdctx.Context, _ []byte, _ []any) (result databases.Result, err error) {
+	tx.executes++
+	return
+}
+
+func TestReadOnlyTransaction(t *testing.T) {
+	ctx := ForceDialect(context.Acquire(stdctx.TODO()), "readonly")
+	core := &readOnlyTestTransaction{}
+	tx := transactions.NewTransaction([]byte("readonly"), nil, core, time.Now().Add(time.Minute))
+	tx.ReadOnly = true
+	withTransaction(ctx, tx)
+
+	_, err := Execute(ctx, []byte(`UPDATE "USER" SET "NAME" = $1`), "a")
+	if !errors.Contains(err, databases.ErrReadOnlyTransaction) {
+		t.Errorf("execute must be rejected, got %v", err)
+	}
+	_, err = Query(ctx, []byte(`INSERT INTO "USER" ("NAME") VALUES ($1) RETURNING "ID"`), "a")
+	if !errors.Contains(err, databases.ErrReadOnlyTransaction) {
+		t.Errorf("insert returning must be rejected, got %v", err)
+	}
+	_, err = Query(ctx, []byte(`WITH "X" AS (DELETE FROM "USER" RETURNING "ID") SELECT "ID" FROM "X"`))
+	if !errors.Contains(err, databases.ErrReadOnlyTransaction) {
+		t.Errorf("delete in cte must be rejected, got %v", err)
+	}
+	if core.queries != 0 || core.executes != 0 {
+		t.Errorf("rejected statements must not be sent, got %d queries and %d executes", core.queries, core.executes)
+	}
+	_, _ = Query(ctx, []byte(`SELECT "ID" FROM "USER"`))
+	if core.queries != 1 {
+		t.Errorf("select must be sent in read only transaction")
+	}
+}
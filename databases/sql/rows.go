@@ -187,7 +187,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 			}
 			*d = int16(cv)
 			break
-		case NullInt64:
+		case *NullInt64:
 			cv, cvErr := column.Int()
 			if cvErr != nil {
 				err = errors.Warning("sql: scan failed").WithCause(cvErr).WithMeta("column", ct.Name)
@@ -205,7 +205,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 			d.Valid = true
 			d.Int64 = cv
 			break
-		case NullInt32:
+		case *NullInt32:
 			cv, cvErr := column.Int()
 			if cvErr != nil {
 				err = errors.Warning("sql: scan failed").WithCause(cvErr).WithMeta("column", ct.Name)
@@ -223,7 +223,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 			d.Valid = true
 			d.Int32 = int32(cv)
 			break
-		case NullInt16:
+		case *NullInt16:
 			cv, cvErr := column.Int()
 			if cvErr != nil {
 				err = errors.Warning("sql: scan failed").WithCause(cvErr).WithMeta("column", ct.Name)
@@ -257,7 +257,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 			}
 			*d = float32(cv)
 			break
-		case sql.NullFloat64:
+		case *NullFloat64:
 			cv, cvErr := column.Float()
 			if cvErr != nil {
 				err = errors.Warning("sql: scan failed").WithCause(cvErr).WithMeta("column", ct.Name)
@@ -494,7 +494,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 					err = errors.Warning("sql: scan failed").WithCause(scanErr).WithMeta("column", ct.Name)
 					return
 				}
-				return
+				continue
 			}
 			if ct.Type == "json" {
 				decodeErr := json.Unmarshal(column.Value, item)
@@ -502,7 +502,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 					err = errors.Warning("sql: scan failed").WithCause(decodeErr).WithMeta("column", ct.Name)
 					return
 				}
-				return
+				continue
 			}
 			rv := reflect.ValueOf(item).Elem()
 			switch rv.Type().Kind() {
@@ -610,7 +610,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 							err = errors.Warning("sql: scan failed").WithCause(decodeErr).WithMeta("column", ct.Name)
 							return
 						}
-						return
+						continue
 					}
 					cv, cvErr := column.Bytes()
 					if cvErr != nil {
@@ -622,7 +622,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 						err = errors.Warning("sql: scan failed").WithCause(decodeErr).WithMeta("column", ct.Name)
 						return
 					}
-					return
+					continue
 				}
 			}
 			break
@@ -632,7 +632,7 @@ func (rows *Rows) Scan(dst ...any) (err error) {
 }
 
 func (rows Rows) MarshalAvro() (p []byte, err error) {
-	if len(rows.values) > 0 {
+	if len(rows.values) > 0 || rows.rows == nil {
 		tr := transferRows{
 			ColumnTypes: rows.columnTypes,
 			Values:      rows.values,
@@ -685,7 +685,7 @@ func (rows *Rows) UnmarshalAvro(p []byte) (err error) {
 }
 
 func (rows Rows) MarshalJSON() (p []byte, err error) {
-	if len(rows.values) > 0 {
+	if len(rows.values) > 0 || rows.rows == nil {
 		tr := transferRows{
 			ColumnTypes: rows.columnTypes,
 			Values:      rows.values,
@@ -0,0 +1,299 @@
+package sql
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/fns/services"
+	"github.com/klauspost/compress/zstd"
+	"strconv"
+	"sync"
+)
+
+const (
+	columnarRowsVersion = byte(1)
+	columnarRowsZstd    = byte(1)
+)
+
+var (
+	queryColumnarFnName = []byte("query_columnar")
+	// columnarRowsMaxDecompressedSize
+	// max size of decompressed body, body which is greater than it is not compressed by encoder.
+	columnarRowsMaxDecompressedSize = uint64(1 << 30)
+)
+
+type RowsConfig struct {
+	// Compress
+	// compress columnar rows by zstd when size of them is greater than CompressThreshold.
+	Compress bool `json:"compress"`
+	// CompressThreshold
+	// bytes, default is 65536.
+	CompressThreshold int `json:"compressThreshold"`
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func loadZstd() (encoder *zstd.Encoder, decoder *zstd.Decoder, err error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(columnarRowsMaxDecompressedSize))
+	})
+	encoder, decoder, err = zstdEncoder, zstdDecoder, zstdErr
+	return
+}
+
+// encodeColumnarRows
+// rows are read from driver and written into columns directly, they are not held as []Row.
+// layout: version, flags, body (compressed by zstd when flags has it).
+// body: columns, then column types (name, database type, type), then rows, then cells of each column,
+// cell is uvarint(0) when it is null, otherwise uvarint(len+1) and avro bytes of value.
+func encodeColumnarRows(rows Rows, config RowsConfig) (p []byte, err error) {
+	if rows.rows == nil {
+		err = errors.Warning("sql: encode columnar rows failed").WithCause(fmt.Errorf("rows has been read"))
+		return
+	}
+	if rows.idx != 0 {
+		err = errors.Warning("sql: encode columnar rows failed").WithCause(fmt.Errorf("rows has been used"))
+		return
+	}
+	columns := make([][]byte, rows.columnLen)
+	scanners := make([]any, rows.columnLen)
+	for i := 0; i < rows.columnLen; i++ {
+		scanners[i] = &Column{}
+	}
+	size := 0
+	for rows.rows.Next() {
+		scanErr := rows.rows.Scan(scanners...)
+		if scanErr != nil {
+			_ = rows.rows.Close()
+			err = errors.Warning("sql: encode columnar rows failed").WithCause(scanErr)
+			return
+		}
+		for i, scanner := range scanners {
+			column := scanner.(*Column)
+			if column.Valid {
+				columns[i] = binary.AppendUvarint(columns[i], uint64(len(column.Value))+1)
+				columns[i] = append(columns[i], column.Value...)
+			} else {
+				columns[i] = binary.AppendUvarint(columns[i], 0)
+			}
+			column.Reset()
+		}
+		size++
+	}
+	_ = rows.rows.Close()
+
+	bodyLen := 16
+	for _, column := range columns {
+		bodyLen += len(column)
+	}
+	body := make([]byte, 0, bodyLen)
+	body = binary.AppendUvarint(body, uint64(rows.columnLen))
+	for _, ct := range rows.columnTypes {
+		body = appendColumnarString(body, ct.Name)
+		body = appendColumnarString(body, ct.DatabaseType)
+		body = appendColumnarString(body, ct.Type)
+	}
+	body = binary.AppendUvarint(body, uint64(size))
+	for _, column := range columns {
+		body = append(body, column...)
+	}
+
+	threshold := config.CompressThreshold
+	if threshold < 1 {
+		threshold = 64 * 1024
+	}
+	if config.Compress && len(body) > threshold && uint64(len(body)) <= columnarRowsMaxDecompressedSize {
+		encoder, _, zstdErr := loadZstd()
+		if zstdErr != nil {
+			err = errors.Warning("sql: encode columnar rows failed").WithCause(zstdErr)
+			return
+		}
+		p = encoder.EncodeAll(body, []byte{columnarRowsVersion, columnarRowsZstd})
+		return
+	}
+	p = make([]byte, 0, len(body)+2)
+	p = append(p, columnarRowsVersion, 0)
+	p = append(p, body...)
+	return
+}
+
+func appendColumnarString(p []byte, s string) []byte {
+	p = binary.AppendUvarint(p, uint64(len(s)))
+	return append(p, s...)
+}
+
+// decodeColumnarRows
+// values of columns refer to p (or decompressed p), they are not copied.
+func decodeColumnarRows(p []byte) (rows Rows, err error) {
+	if len(p) < 2 {
+		err = errors.Warning("sql: decode columnar rows failed").WithCause(fmt.Errorf("invalid columnar rows"))
+		return
+	}
+	if p[0] != columnarRowsVersion {
+		err = errors.Warning("sql: decode columnar rows failed").WithCause(fmt.Errorf("version %d is not supported", p[0]))
+		return
+	}
+	body := p[2:]
+	if p[1]&columnarRowsZstd != 0 {
+		_, decoder, zstdErr := loadZstd()
+		if zstdErr != nil {
+			err = errors.Warning("sql: decode columnar rows failed").WithCause(zstdErr)
+			return
+		}
+		header := zstd.Header{}
+		if headerErr := header.Decode(body); headerErr != nil {
+			err = errors.Warning("sql: decode columnar rows failed").WithCause(headerErr)
+			return
+		}
+		if header.HasFCS && header.FrameContentSize > columnarRowsMaxDecompressedSize {
+			err = errors.Warning("sql: decode columnar rows failed").WithCause(fmt.Errorf("decompressed size is too large")).
+				WithMeta("size", strconv.FormatUint(header.FrameContentSize, 10))
+			return
+		}
+		// decoder limits total decompressed size, so frames without content size are bounded too
+		body, err = decoder.DecodeAll(body, nil)
+		if err != nil {
+			err = errors.Warning("sql: decode columnar rows failed").WithCause(err)
+			return
+		}
+	}
+	reader := columnarReader{p: body}
+	columnLen := int(reader.uvarint())
+	// each column type has three lengths at least
+	if columnLen < 0 || columnLen > len(reader.p)/3 {
+		err = errors.Warning("sql: decode columnar rows failed").WithCause(fmt.Errorf("invalid columnar rows")).WithMeta("columns", strconv.Itoa(columnLen))
+		return
+	}
+	columnTypes := make([]ColumnType, 0, columnLen)
+	for i := 0; i < columnLen; i++ {
+		columnTypes = append(columnTypes, ColumnType{
+			Name:         bytex.ToString(reader.bytes()),
+			DatabaseType: bytex.ToString(reader.bytes()),
+			Type:         bytex.ToString(reader.bytes()),
+		})
+	}
+	size := int(reader.uvarint())
+	if reader.err != nil {
+		err = errors.Warning("sql: decode columnar rows failed").WithCause(reader.err)
+		return
+	}
+	// each cell has one length at least, so size * columnLen is not greater than remains
+	if size < 0 || size > len(reader.p)/max(columnLen, 1) {
+		err = errors.Warning("sql: decode columnar rows failed").WithCause(fmt.Errorf("invalid columnar rows")).WithMeta("rows", strconv.Itoa(size))
+		return
+	}
+	cells := make([]Column, size*columnLen)
+	values := make([]Row, size)
+	for i := 0; i < size; i++ {
+		values[i] = cells[i*columnLen : (i+1)*columnLen : (i+1)*columnLen]
+	}
+	for j := 0; j < columnLen; j++ {
+		for i := 0; i < size; i++ {
+			n := reader.uvarint()
+			if n == 0 {
+				continue
+			}
+			values[i][j] = Column{
+				Valid: true,
+				Value: reader.next(int(n - 1)),
+			}
+		}
+	}
+	if reader.err != nil {
+		err = errors.Warning("sql: decode columnar rows failed").WithCause(reader.err)
+		return
+	}
+	rows = Rows{
+		idx:         0,
+		rows:        nil,
+		columnTypes: columnTypes,
+		columnLen:   columnLen,
+		values:      values,
+		size:        size,
+	}
+	return
+}
+
+type columnarReader struct {
+	p   []byte
+	err error
+}
+
+func (reader *columnarReader) uvarint() (n uint64) {
+	if reader.err != nil {
+		return
+	}
+	size := 0
+	n, size = binary.Uvarint(reader.p)
+	if size <= 0 {
+		reader.err = fmt.Errorf("invalid columnar rows")
+		n = 0
+		return
+	}
+	reader.p = reader.p[size:]
+	return
+}
+
+func (reader *columnarReader) next(n int) (p []byte) {
+	if reader.err != nil {
+		return
+	}
+	if n < 0 || n > len(reader.p) {
+		reader.err = fmt.Errorf("invalid columnar rows")
+		return
+	}
+	p = reader.p[:n:n]
+	reader.p = reader.p[n:]
+	return
+}
+
+func (reader *columnarReader) bytes() (p []byte) {
+	p = reader.next(int(reader.uvarint()))
+	return
+}
+
+// queryColumnarFn
+// same as query fn, but rows are encoded in columnar, it is used by other nodes, endpoints of old version have no it.
+type queryColumnarFn struct {
+	*queryFn
+	config RowsConfig
+}
+
+func (fn *queryColumnarFn) Name() string {
+	return string(queryColumnarFnName)
+}
+
+func (fn *queryColumnarFn) Handle(r services.Request) (v interface{}, err error) {
+	result, handleErr := fn.queryFn.Handle(r)
+	if handleErr != nil {
+		err = handleErr
+		return
+	}
+	v, err = encodeColumnarRows(result.(Rows), fn.config)
+	if err != nil {
+		err = errors.Warning("sql: query failed").WithCause(err)
+		return
+	}
+	return
+}
+
+// columnarSupported
+// check whether endpoint which handles query supports columnar rows,
+// endpoint in local is not used, cause rows are not encoded in local.
+func columnarSupported(endpoint services.Endpoint) (ok bool) {
+	if _, local := endpoint.(services.Service); local {
+		return
+	}
+	_, ok = endpoint.Functions().Find(queryColumnarFnName)
+	return
+}
@@ -0,0 +1,126 @@
+package sql
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"reflect"
+	"testing"
+)
+
+type columnarTestRows struct {
+	values [][]any
+	idx    int
+}
+
+func (rows *columnarTestRows) Columns() ([]string, error) {
+	return []string{"ID", "NAME"}, nil
+}
+
+func (rows *columnarTestRows) ColumnTypes() ([]databases.ColumnType, error) {
+	return []databases.ColumnType{
+		{DatabaseType: "INT8", ScanType: reflect.TypeOf(int64(0))},
+		{DatabaseType: "VARCHAR", ScanType: reflect.TypeOf("")},
+	}, nil
+}
+
+func (rows *columnarTestRows) Next() bool {
+	rows.idx++
+	return rows.idx <= len(rows.values)
+}
+
+func (rows *columnarTestRows) Scan(dst ...any) error {
+	for i, value := range rows.values[rows.idx-1] {
+		if err := dst[i].(*Column).Scan(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rows *columnarTestRows) Close() error {
+	return nil
+}
+
+func TestColumnarRows(t *testing.T) {
+	values := make([][]any, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, []any{int64(i), "name"})
+	}
+	values = append(values, []any{int64(100), nil})
+	for _, config := range []RowsConfig{{}, {Compress: true, CompressThreshold: 1}} {
+		rows, rowsErr := NewRows(&columnarTestRows{values: values})
+		if rowsErr != nil {
+			t.Fatal(rowsErr)
+		}
+		p, encodeErr := encodeColumnarRows(rows, config)
+		if encodeErr != nil {
+			t.Fatal(encodeErr)
+		}
+		if compressed := p[1]&columnarRowsZstd != 0; compressed != config.Compress {
+			t.Errorf("compressed must be %v", config.Compress)
+		}
+		decoded, decodeErr := decodeColumnarRows(p)
+		if decodeErr != nil {
+			t.Errorf("%+v", decodeErr)
+			return
+		}
+		if decoded.size != len(values) || decoded.columnLen != 2 {
+			t.Errorf("size and columns are not matched, got %d and %d", decoded.size, decoded.columnLen)
+			return
+		}
+		name, _ := decoded.values[99][1].String()
+		if id, _ := decoded.values[99][0].Int(); id != 99 || name != "name" {
+			t.Errorf("values are not matched, got %d and %s", id, name)
+		}
+		if decoded.values[100][1].Valid {
+			t.Errorf("null must be invalid")
+		}
+	}
+}
+
+func TestColumnarRows_Invalid(t *testing.T) {
+	header := func(columns uint64) []byte {
+		p := []byte{columnarRowsVersion, 0}
+		p = binary.AppendUvarint(p, columns)
+		for i := uint64(0); i < columns && i < 2; i++ {
+			p = appendColumnarString(p, "A")
+			p = appendColumnarString(p, "INT8")
+			p = appendColumnarString(p, "int")
+		}
+		return p
+	}
+	cases := map[string][]byte{
+		// rows are far more than cells in payload
+		"rows": binary.AppendUvarint(header(2), 1<<40),
+		// rows * columns overflows
+		"overflow": append(binary.AppendUvarint(header(2), 1<<62), 0, 0, 0, 0),
+		// columns are far more than column types in payload
+		"columns": header(1 << 40),
+		// truncated cells
+		"cells": append(binary.AppendUvarint(header(2), 2), 1, 1, 1),
+	}
+	for name, p := range cases {
+		if _, err := decodeColumnarRows(p); err == nil {
+			t.Errorf("%s: invalid columnar rows must be rejected", name)
+		}
+	}
+}
+
+func TestColumnarRows_DecompressedSize(t *testing.T) {
+	limit := columnarRowsMaxDecompressedSize
+	defer func() {
+		columnarRowsMaxDecompressedSize = limit
+	}()
+	encoder, _, err := loadZstd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// highly compressible body which is greater than limit
+	body := bytes.Repeat([]byte{0}, 1<<20)
+	p := encoder.EncodeAll(body, []byte{columnarRowsVersion, columnarRowsZstd})
+	columnarRowsMaxDecompressedSize = 1 << 16
+	if _, err = decodeColumnarRows(p); err == nil {
+		t.Errorf("decompressed size which is greater than limit must be rejected")
+	}
+}
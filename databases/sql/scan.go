@@ -0,0 +1,629 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/commons/times"
+	"github.com/aacfactory/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type UnknownColumnPolicy int
+
+const (
+	// IgnoreUnknownColumns
+	// columns which are not matched by any field are skipped.
+	IgnoreUnknownColumns UnknownColumnPolicy = iota
+	// DisallowUnknownColumns
+	// columns which are not matched by any field cause an error.
+	DisallowUnknownColumns
+)
+
+type ScanOptions struct {
+	UnknownColumns UnknownColumnPolicy
+}
+
+type ScanOption func(options *ScanOptions)
+
+func WithUnknownColumnPolicy(policy UnknownColumnPolicy) ScanOption {
+	return func(options *ScanOptions) {
+		options.UnknownColumns = policy
+	}
+}
+
+// ScanOne
+// scan first row into T, then close rows.
+// see ScanAll.
+func ScanOne[T any](rows Rows, options ...ScanOption) (v T, has bool, err error) {
+	values, scanErr := scan[T](&rows, 1, options)
+	if scanErr != nil {
+		err = scanErr
+		return
+	}
+	if len(values) == 0 {
+		return
+	}
+	v = values[0]
+	has = true
+	return
+}
+
+// ScanAll
+// scan rows into T by column name, then close rows.
+// when T is a struct, column is matched by `column` tag, `json` tag and field name in order,
+// and when none is matched, then matched again by case and underscore insensitive name. fields of embedded struct are supported.
+// when T is not a struct, such as string, then rows must have only one column.
+// null column is skipped, so pointer field is nil and Null* field is invalid.
+func ScanAll[T any](rows Rows, options ...ScanOption) (v []T, err error) {
+	v, err = scan[T](&rows, 0, options)
+	return
+}
+
+// ScanMaps
+// scan rows into maps which key is column name, then close rows.
+// value of null column is nil, date column is times.Date, and time column is times.Time.
+func ScanMaps(rows Rows) (v []map[string]any, err error) {
+	defer func(rows *Rows) {
+		_ = rows.Close()
+	}(&rows)
+	cts := rows.Columns()
+	v = make([]map[string]any, 0, 1)
+	for rows.Next() {
+		values, scanErr := rows.scanValues()
+		if scanErr != nil {
+			err = errors.Warning("sql: scan maps failed").WithCause(scanErr)
+			return
+		}
+		m := make(map[string]any, len(cts))
+		for i, ct := range cts {
+			value := values[i]
+			if t, ok := value.(time.Time); ok {
+				switch ct.Type {
+				case "date":
+					value = times.DataOf(t)
+					break
+				case "time":
+					value = times.TimeOf(t)
+					break
+				default:
+					break
+				}
+			}
+			m[ct.Name] = value
+		}
+		v = append(v, m)
+	}
+	return
+}
+
+func scan[T any](rows *Rows, limit int, options []ScanOption) (v []T, err error) {
+	defer func(rows *Rows) {
+		_ = rows.Close()
+	}(rows)
+	opt := ScanOptions{
+		UnknownColumns: IgnoreUnknownColumns,
+	}
+	for _, option := range options {
+		option(&opt)
+	}
+	rt := reflect.TypeOf(new(T)).Elem()
+	st := rt
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	cts := rows.Columns()
+	var indexes [][]int
+	if isScanStruct(st) {
+		fields := getScanFields(st)
+		indexes = make([][]int, len(cts))
+		for i, ct := range cts {
+			index, has := fields.match(ct.Name)
+			if !has && opt.UnknownColumns == DisallowUnknownColumns {
+				err = errors.Warning("sql: scan failed").
+					WithCause(fmt.Errorf("column was not matched by any field of %s", st.String())).
+					WithMeta("column", ct.Name)
+				return
+			}
+			indexes[i] = index
+		}
+	} else if len(cts) != 1 {
+		err = errors.Warning("sql: scan failed").WithCause(fmt.Errorf("%s can only be scanned from one column", rt.String()))
+		return
+	}
+	v = make([]T, 0, 1)
+	for rows.Next() {
+		values, scanErr := rows.scanValues()
+		if scanErr != nil {
+			err = errors.Warning("sql: scan failed").WithCause(scanErr)
+			return
+		}
+		var t T
+		tv := reflect.ValueOf(&t).Elem()
+		if rt.Kind() == reflect.Ptr {
+			tv.Set(reflect.New(st))
+			tv = tv.Elem()
+		}
+		if indexes == nil {
+			if values[0] != nil {
+				assignErr := assignScanValue(tv, values[0])
+				if assignErr != nil {
+					err = errors.Warning("sql: scan failed").WithCause(assignErr).WithMeta("column", cts[0].Name)
+					return
+				}
+			}
+		} else {
+			for i, index := range indexes {
+				if index == nil || values[i] == nil {
+					continue
+				}
+				assignErr := assignScanValue(scanFieldByIndex(tv, index), values[i])
+				if assignErr != nil {
+					err = errors.Warning("sql: scan failed").WithCause(assignErr).WithMeta("column", cts[i].Name)
+					return
+				}
+			}
+		}
+		v = append(v, t)
+		if limit > 0 && len(v) >= limit {
+			break
+		}
+	}
+	return
+}
+
+// scanValues
+// values of current row, value of null column is nil, and value of datetime, date and time column is time.Time.
+func (rows *Rows) scanValues() (values []any, err error) {
+	values = make([]any, rows.columnLen)
+	if rows.rows != nil {
+		holders := make([]any, rows.columnLen)
+		for i, ct := range rows.columnTypes {
+			switch ct.Type {
+			case "string":
+				holders[i] = &sql.NullString{}
+				break
+			case "bool":
+				holders[i] = &sql.NullBool{}
+				break
+			case "int":
+				holders[i] = &sql.NullInt64{}
+				break
+			case "float":
+				holders[i] = &sql.NullFloat64{}
+				break
+			case "datetime", "date", "time":
+				holders[i] = &sql.NullTime{}
+				break
+			case "byte":
+				holders[i] = &sql.NullByte{}
+				break
+			default:
+				holders[i] = &NullBytes{}
+				break
+			}
+		}
+		err = rows.rows.Scan(holders...)
+		if err != nil {
+			return
+		}
+		for i, holder := range holders {
+			switch h := holder.(type) {
+			case *sql.NullString:
+				if h.Valid {
+					values[i] = h.String
+				}
+				break
+			case *sql.NullBool:
+				if h.Valid {
+					values[i] = h.Bool
+				}
+				break
+			case *sql.NullInt64:
+				if h.Valid {
+					values[i] = h.Int64
+				}
+				break
+			case *sql.NullFloat64:
+				if h.Valid {
+					values[i] = h.Float64
+				}
+				break
+			case *sql.NullTime:
+				if h.Valid {
+					values[i] = h.Time
+				}
+				break
+			case *sql.NullByte:
+				if h.Valid {
+					values[i] = h.Byte
+				}
+				break
+			case *NullBytes:
+				if h.Valid {
+					values[i] = h.Bytes
+				}
+				break
+			}
+		}
+		return
+	}
+	if rows.idx < 1 || rows.idx > rows.size {
+		err = sql.ErrNoRows
+		return
+	}
+	row := rows.values[rows.idx-1]
+	for i, ct := range rows.columnTypes {
+		column := row[i]
+		if !column.Valid {
+			continue
+		}
+		var value any
+		var valueErr error
+		switch ct.Type {
+		case "string":
+			value, valueErr = column.String()
+			break
+		case "bool":
+			value, valueErr = column.Bool()
+			break
+		case "int":
+			value, valueErr = column.Int()
+			break
+		case "float":
+			value, valueErr = column.Float()
+			break
+		case "datetime", "date", "time":
+			value, valueErr = column.Datetime()
+			break
+		case "byte":
+			value, valueErr = column.Byte()
+			break
+		default:
+			value, valueErr = column.Bytes()
+			break
+		}
+		if valueErr != nil {
+			err = errors.Warning("sql: scan failed").WithCause(valueErr).WithMeta("column", ct.Name)
+			return
+		}
+		values[i] = value
+	}
+	return
+}
+
+func assignScanValue(fv reflect.Value, value any) (err error) {
+	ft := fv.Type()
+	if ft.Kind() == reflect.Ptr {
+		ev := reflect.New(ft.Elem())
+		err = assignScanValue(ev.Elem(), value)
+		if err != nil {
+			return
+		}
+		fv.Set(ev)
+		return
+	}
+	if t, isTime := value.(time.Time); isTime {
+		if ft.ConvertibleTo(datetimeType) {
+			fv.Set(reflect.ValueOf(t).Convert(ft))
+			return
+		}
+		if ft.ConvertibleTo(dateType) {
+			fv.Set(reflect.ValueOf(times.DataOf(t)).Convert(ft))
+			return
+		}
+		if ft.ConvertibleTo(timeType) {
+			fv.Set(reflect.ValueOf(times.TimeOf(t)).Convert(ft))
+			return
+		}
+	}
+	if reflect.PointerTo(ft).Implements(scannerType) {
+		err = fv.Addr().Interface().(sql.Scanner).Scan(value)
+		return
+	}
+	switch ft.Kind() {
+	case reflect.String:
+		switch s := value.(type) {
+		case string:
+			fv.SetString(s)
+			break
+		case []byte:
+			fv.SetString(string(s))
+			break
+		case time.Time:
+			fv.SetString(s.Format(time.RFC3339))
+			break
+		default:
+			fv.SetString(fmt.Sprintf("%v", s))
+			break
+		}
+		return
+	case reflect.Bool:
+		switch s := value.(type) {
+		case bool:
+			fv.SetBool(s)
+			break
+		case int64:
+			fv.SetBool(s != 0)
+			break
+		case byte:
+			fv.SetBool(s != 0)
+			break
+		case string:
+			b, parseErr := strconv.ParseBool(s)
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			fv.SetBool(b)
+			break
+		case []byte:
+			b, parseErr := strconv.ParseBool(string(s))
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			fv.SetBool(b)
+			break
+		default:
+			err = fmt.Errorf("%T can not be assigned to %s", value, ft.String())
+			return
+		}
+		return
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch s := value.(type) {
+		case int64:
+			fv.SetInt(s)
+			break
+		case float64:
+			fv.SetInt(int64(s))
+			break
+		case byte:
+			fv.SetInt(int64(s))
+			break
+		case bool:
+			if s {
+				fv.SetInt(1)
+			}
+			break
+		case string:
+			n, parseErr := strconv.ParseInt(s, 10, 64)
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			fv.SetInt(n)
+			break
+		case []byte:
+			n, parseErr := strconv.ParseInt(string(s), 10, 64)
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			fv.SetInt(n)
+			break
+		default:
+			err = fmt.Errorf("%T can not be assigned to %s", value, ft.String())
+			return
+		}
+		return
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch s := value.(type) {
+		case int64:
+			fv.SetUint(uint64(s))
+			break
+		case float64:
+			fv.SetUint(uint64(s))
+			break
+		case byte:
+			fv.SetUint(uint64(s))
+			break
+		case string:
+			n, parseErr := strconv.ParseUint(s, 10, 64)
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			fv.SetUint(n)
+			break
+		case []byte:
+			n, parseErr := strconv.ParseUint(string(s), 10, 64)
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			fv.SetUint(n)
+			break
+		default:
+			err = fmt.Errorf("%T can not be assigned to %s", value, ft.String())
+			return
+		}
+		return
+	case reflect.Float32, reflect.Float64:
+		switch s := value.(type) {
+		case float64:
+			fv.SetFloat(s)
+			break
+		case int64:
+			fv.SetFloat(float64(s))
+			break
+		case string:
+			f, parseErr := strconv.ParseFloat(s, 64)
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			fv.SetFloat(f)
+			break
+		case []byte:
+			f, parseErr := strconv.ParseFloat(string(s), 64)
+			if parseErr != nil {
+				err = parseErr
+				return
+			}
+			fv.SetFloat(f)
+			break
+		default:
+			err = fmt.Errorf("%T can not be assigned to %s", value, ft.String())
+			return
+		}
+		return
+	case reflect.Interface:
+		fv.Set(reflect.ValueOf(value))
+		return
+	default:
+		break
+	}
+	var p []byte
+	switch s := value.(type) {
+	case []byte:
+		p = s
+		break
+	case string:
+		p = []byte(s)
+		break
+	default:
+		err = fmt.Errorf("%T can not be assigned to %s", value, ft.String())
+		return
+	}
+	if ft.ConvertibleTo(bytesType) {
+		fv.Set(reflect.ValueOf(append(make([]byte, 0, len(p)), p...)).Convert(ft))
+		return
+	}
+	if reflect.PointerTo(ft).Implements(unmarshalerType) {
+		err = fv.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(p)
+		return
+	}
+	err = json.Unmarshal(p, fv.Addr().Interface())
+	return
+}
+
+func scanFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func isScanStruct(rt reflect.Type) bool {
+	if rt.Kind() != reflect.Struct {
+		return false
+	}
+	if rt.ConvertibleTo(datetimeType) || rt.ConvertibleTo(dateType) || rt.ConvertibleTo(timeType) {
+		return false
+	}
+	return !reflect.PointerTo(rt).Implements(scannerType)
+}
+
+var (
+	scanFieldsCache = sync.Map{}
+)
+
+type scanFields struct {
+	names      map[string][]int
+	normalized map[string][]int
+}
+
+func (fields *scanFields) match(column string) (index []int, has bool) {
+	index, has = fields.names[column]
+	if has {
+		return
+	}
+	index, has = fields.normalized[normalizeScanName(column)]
+	return
+}
+
+func (fields *scanFields) add(name string, index []int) {
+	if name == "" {
+		return
+	}
+	if _, has := fields.names[name]; !has {
+		fields.names[name] = index
+	}
+	normalized := normalizeScanName(name)
+	if _, has := fields.normalized[normalized]; !has {
+		fields.normalized[normalized] = index
+	}
+}
+
+func normalizeScanName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+func getScanFields(rt reflect.Type) *scanFields {
+	cached, has := scanFieldsCache.Load(rt)
+	if has {
+		return cached.(*scanFields)
+	}
+	fields := &scanFields{
+		names:      make(map[string][]int),
+		normalized: make(map[string][]int),
+	}
+	collectScanFields(fields, rt, nil)
+	scanFieldsCache.Store(rt, fields)
+	return fields
+}
+
+func collectScanFields(fields *scanFields, rt reflect.Type, parent []int) {
+	embeds := make([]reflect.StructField, 0, 1)
+	// names of column tags are matched first, then json tags, then field names
+	for round := 0; round < 3; round++ {
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+				continue
+			}
+			columnTag, hasColumnTag := field.Tag.Lookup("column")
+			if columnTag == "-" || field.Tag.Get("json") == "-" {
+				continue
+			}
+			if field.Anonymous && !hasColumnTag {
+				et := field.Type
+				if et.Kind() == reflect.Ptr {
+					et = et.Elem()
+				}
+				if isScanStruct(et) {
+					if round == 0 {
+						embeds = append(embeds, field)
+					}
+					continue
+				}
+			}
+			index := append(append(make([]int, 0, len(parent)+1), parent...), i)
+			switch round {
+			case 0:
+				if hasColumnTag {
+					fields.add(strings.TrimSpace(strings.Split(columnTag, ",")[0]), index)
+				}
+				break
+			case 1:
+				if jsonTag, hasJsonTag := field.Tag.Lookup("json"); hasJsonTag {
+					fields.add(strings.TrimSpace(strings.Split(jsonTag, ",")[0]), index)
+				}
+				break
+			default:
+				fields.add(field.Name, index)
+				break
+			}
+		}
+	}
+	// fields of outer struct shadow fields of embedded struct
+	for _, embed := range embeds {
+		et := embed.Type
+		if et.Kind() == reflect.Ptr {
+			et = et.Elem()
+		}
+		index := append(append(make([]int, 0, len(parent)+1), parent...), embed.Index[0])
+		collectScanFields(fields, et, index)
+	}
+}
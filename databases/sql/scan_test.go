@@ -0,0 +1,101 @@
+package sql_test
+
+import (
+	"context"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type scanUser struct {
+	Id       string `column:"ID"`
+	Nickname string `json:"nickname"`
+	UserAge  int64
+}
+
+func newScanRows(t *testing.T, rows *sqltest.Rows) sql.Rows {
+	t.Helper()
+	db := sqltest.New()
+	db.ExpectQuery(`SELECT`).WillReturnRows(rows)
+	raw, queryErr := db.Query(context.TODO(), []byte(`SELECT`), nil)
+	if queryErr != nil {
+		t.Fatalf("%+v", queryErr)
+	}
+	v, err := sql.NewRows(raw)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return v
+}
+
+func TestScanAll(t *testing.T) {
+	rows := newScanRows(t, sqltest.NewRows("ID", "nickname", "USER_AGE").AddRow("1", "foo", int64(18)).AddRow("2", "bar", int64(20)))
+	users, err := sql.ScanAll[scanUser](rows)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if len(users) != 2 || users[0] != (scanUser{Id: "1", Nickname: "foo", UserAge: 18}) || users[1].Id != "2" {
+		t.Errorf("users are not matched, %+v", users)
+	}
+}
+
+func TestScanOne(t *testing.T) {
+	rows := newScanRows(t, sqltest.NewRows("ID", "UNKNOWN").AddRow("1", "x"))
+	if _, _, err := sql.ScanOne[scanUser](rows, sql.WithUnknownColumnPolicy(sql.DisallowUnknownColumns)); err == nil {
+		t.Errorf("unknown column must be failed")
+		return
+	}
+	rows = newScanRows(t, sqltest.NewRows("ID").AddRow("1").AddRow("2"))
+	id, has, err := sql.ScanOne[string](rows)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if !has || id != "1" {
+		t.Errorf("id is not matched, %v %s", has, id)
+	}
+}
+
+func TestScanMaps(t *testing.T) {
+	rows := newScanRows(t, sqltest.NewRows("ID", "AGE").AddRow("1", int64(18)))
+	maps, err := sql.ScanMaps(rows)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if len(maps) != 1 || maps[0]["ID"] != "1" || maps[0]["AGE"] != int64(18) {
+		t.Errorf("maps are not matched, %+v", maps)
+	}
+}
+
+func TestRows_ScanNull(t *testing.T) {
+	rows := newScanRows(t, sqltest.NewRows("AGE", "SCORE", "NAME").AddRow(int64(18), float64(1.5), "foo").AddRow(nil, nil, nil))
+	defer rows.Close()
+	for i := 0; rows.Next(); i++ {
+		age := sql.NullInt64{}
+		score := sql.NullFloat64{}
+		name := sql.NullString{}
+		if err := rows.Scan(&age, &score, &name); err != nil {
+			t.Errorf("%+v", err)
+			return
+		}
+		if i == 0 && (!age.Valid || age.Int64 != 18 || !score.Valid || score.Float64 != 1.5 || name.String != "foo") {
+			t.Errorf("row %d is not matched, %v %v %v", i, age, score, name)
+		}
+		if i == 1 && (age.Valid || score.Valid || name.Valid) {
+			t.Errorf("row %d must be null", i)
+		}
+	}
+}
+
+func TestNullJson_ScanString(t *testing.T) {
+	v := sql.NullJson[map[string]int]{}
+	if err := v.Scan(`{"a":1}`); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if !v.Valid || v.E["a"] != 1 {
+		t.Errorf("value is not matched, %+v", v)
+	}
+}
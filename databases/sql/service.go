@@ -71,7 +71,7 @@ func New(options ...Option) (v services.Service) {
 	v = &service{
 		Abstract:        services.NewAbstract(opt.name, true),
 		registerTLSFunc: opt.registerTLSFunc,
-		db:              nil,
+		db:              opt.db,
 		group:           nil,
 		dialect:         opt.dialect,
 	}
@@ -86,6 +86,7 @@ type service struct {
 	isolation       databases.Isolation
 	dialect         string
 	debug           bool
+	timeout         time.Duration
 }
 
 func (svc *service) Construct(options services.Options) (err error) {
@@ -129,6 +130,9 @@ func (svc *service) Construct(options services.Options) (err error) {
 	case "cluster":
 		svc.db = databases.Cluster()
 		break
+	case "sharding":
+		svc.db = databases.Sharding()
+		break
 	default:
 		if svc.db == nil {
 			err = errors.Warning(fmt.Sprintf("fns: %s construct failed", svc.Name())).WithMeta("service", svc.Name()).WithCause(fmt.Errorf("%s database was not found", config.Kind))
@@ -141,25 +145,6 @@ func (svc *service) Construct(options services.Options) (err error) {
 		}
 		break
 	}
-	dbConfig, dbConfigErr := configures.NewJsonConfig(config.Options)
-	if dbConfigErr != nil {
-		err = errors.Warning(fmt.Sprintf("fns: %s construct failed", svc.Name())).WithMeta("service", svc.Name()).WithCause(dbConfigErr)
-		return
-	}
-	err = svc.db.Construct(databases.Options{
-		Log:    svc.Log().With("database", svc.db.Name()),
-		Config: dbConfig,
-	})
-	if err != nil {
-		err = errors.Warning(fmt.Sprintf("fns: %s construct failed", svc.Name())).WithMeta("service", svc.Name()).WithCause(err)
-		return
-	}
-	svc.group = transactions.New(svc.Log(), time.Duration(config.TransactionMaxAge)*time.Second)
-	isolation := config.Isolation
-	if isolation < 0 || isolation > 7 {
-		isolation = databases.LevelReadCommitted
-	}
-	svc.isolation = isolation
 	if svc.dialect == "" {
 		drivers := stdsql.Drivers()
 		if len(drivers) != 1 {
@@ -176,11 +161,44 @@ func (svc *service) Construct(options services.Options) (err error) {
 		case "oracle":
 			svc.dialect = "oracle"
 			break
+		case "sqlite", "sqlite3":
+			svc.dialect = "sqlite"
+			break
+		case "clickhouse":
+			svc.dialect = "clickhouse"
+			break
 		default:
 			err = errors.Warning(fmt.Sprintf("fns: %s construct failed", svc.Name())).WithMeta("service", svc.Name()).WithCause(fmt.Errorf("please use WithDialect to set dialect"))
 			return
 		}
 	}
+	if config.StatementTimeout > 0 {
+		svc.timeout = time.Duration(config.StatementTimeout) * time.Millisecond
+	}
+	dbConfig, dbConfigErr := configures.NewJsonConfig(config.Options)
+	if dbConfigErr != nil {
+		err = errors.Warning(fmt.Sprintf("fns: %s construct failed", svc.Name())).WithMeta("service", svc.Name()).WithCause(dbConfigErr)
+		return
+	}
+	var sessions [][]byte
+	if session := statementTimeoutConnectionSession(svc.dialect, svc.timeout); len(session) > 0 {
+		sessions = append(sessions, session)
+	}
+	err = svc.db.Construct(databases.Options{
+		Log:      svc.Log().With("database", svc.db.Name()),
+		Config:   dbConfig,
+		Sessions: sessions,
+	})
+	if err != nil {
+		err = errors.Warning(fmt.Sprintf("fns: %s construct failed", svc.Name())).WithMeta("service", svc.Name()).WithCause(err)
+		return
+	}
+	svc.group = transactions.New(svc.Log(), time.Duration(config.TransactionMaxAge)*time.Second)
+	isolation := config.Isolation
+	if isolation < 0 || isolation > 7 {
+		isolation = databases.LevelReadCommitted
+	}
+	svc.isolation = isolation
 	svc.debug = config.DebugLog
 	// fn
 	svc.AddFunction(&transactionBeginFn{
@@ -189,28 +207,42 @@ func (svc *service) Construct(options services.Options) (err error) {
 		isolation:  svc.isolation,
 		db:         svc.db,
 		group:      svc.group,
+		dialect:    svc.dialect,
+		timeout:    svc.timeout,
 	})
 	svc.AddFunction(&transactionCommitFn{
 		endpointId: svc.Id(),
 		db:         svc.db,
 		group:      svc.group,
 	})
+	svc.AddFunction(&transactionIncrFn{
+		group: svc.group,
+	})
 	svc.AddFunction(&transactionRollbackFn{
 		endpointId: svc.Id(),
 		db:         svc.db,
 		group:      svc.group,
 	})
-	svc.AddFunction(&queryFn{
-		debug: svc.debug,
-		log:   svc.Log().With("fn", "query"),
-		db:    svc.db,
-		group: svc.group,
+	query := &queryFn{
+		debug:   svc.debug,
+		log:     svc.Log().With("fn", "query"),
+		db:      svc.db,
+		group:   svc.group,
+		dialect: svc.dialect,
+		timeout: svc.timeout,
+	}
+	svc.AddFunction(query)
+	svc.AddFunction(&queryColumnarFn{
+		queryFn: query,
+		config:  config.Rows,
 	})
 	svc.AddFunction(&executeFn{
-		debug: svc.debug,
-		log:   svc.Log().With("fn", "execute"),
-		db:    svc.db,
-		group: svc.group,
+		debug:   svc.debug,
+		log:     svc.Log().With("fn", "execute"),
+		db:      svc.db,
+		group:   svc.group,
+		dialect: svc.dialect,
+		timeout: svc.timeout,
 	})
 	svc.AddFunction(&dialectFn{
 		dialect: svc.dialect,
@@ -0,0 +1,252 @@
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns/context"
+	"sort"
+)
+
+var (
+	shardingContextKey = []byte("@fns:sql:sharding")
+)
+
+// Sharding
+// route of statement when database is sharding.
+type Sharding struct {
+	// Keys
+	// values of shard key column, statement is executed on all shards when it is empty.
+	Keys Arguments `json:"keys" avro:"keys"`
+	// Single
+	// statement must be executed on only one shard.
+	Single bool `json:"single" avro:"single"`
+	// Merge
+	// merge rows of shards, it is used in query.
+	Merge ShardingMerge `json:"merge" avro:"merge"`
+}
+
+type ShardingOrder struct {
+	Column string `json:"column" avro:"column"`
+	Desc   bool   `json:"desc" avro:"desc"`
+}
+
+type ShardingMerge struct {
+	// Sum
+	// sum numeric columns of rows into one row, such as count.
+	Sum    bool            `json:"sum" avro:"sum"`
+	Orders []ShardingOrder `json:"orders" avro:"orders"`
+	Offset int             `json:"offset" avro:"offset"`
+	// Length
+	// 0 means no limit.
+	Length int `json:"length" avro:"length"`
+}
+
+func (merge ShardingMerge) Exist() bool {
+	return merge.Sum || len(merge.Orders) > 0 || merge.Offset > 0 || merge.Length > 0
+}
+
+// WithSharding
+// route next statements by sharding, use RemoveSharding to remove it.
+func WithSharding(ctx context.Context, sharding Sharding) {
+	ctx.SetLocalValue(shardingContextKey, sharding)
+}
+
+func RemoveSharding(ctx context.Context) {
+	ctx.RemoveLocalValue(shardingContextKey)
+}
+
+func LoadSharding(ctx context.Context) (sharding Sharding, has bool) {
+	sharding, has = context.LocalValue[Sharding](ctx, shardingContextKey)
+	return
+}
+
+func (rows *Rows) merge(merge ShardingMerge) (err error) {
+	if !merge.Exist() {
+		return
+	}
+	if rows.rows != nil {
+		mc := newMultiColumns(rows.columnLen)
+		for rows.rows.Next() {
+			scanners := mc.Next()
+			scanErr := rows.rows.Scan(scanners...)
+			if scanErr != nil {
+				_ = rows.rows.Close()
+				mc.Release()
+				err = errors.Warning("sql: merge rows failed").WithCause(scanErr)
+				return
+			}
+		}
+		_ = rows.rows.Close()
+		rows.rows = nil
+		rows.values = mc.Rows()
+		mc.Release()
+	}
+	values := rows.values
+	if merge.Sum {
+		values, err = rows.sum(values)
+		if err != nil {
+			return
+		}
+	}
+	if len(merge.Orders) > 0 {
+		positions := make([]int, 0, len(merge.Orders))
+		for _, order := range merge.Orders {
+			pos := -1
+			for i, ct := range rows.columnTypes {
+				if ct.Name == order.Column {
+					pos = i
+					break
+				}
+			}
+			if pos < 0 {
+				err = errors.Warning("sql: merge rows failed").WithCause(fmt.Errorf("column of order was not found")).WithMeta("column", order.Column)
+				return
+			}
+			positions = append(positions, pos)
+		}
+		sort.SliceStable(values, func(i, j int) bool {
+			for n, pos := range positions {
+				c := compareColumn(rows.columnTypes[pos], &values[i][pos], &values[j][pos])
+				if c == 0 {
+					continue
+				}
+				if merge.Orders[n].Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+	if merge.Offset > 0 {
+		if merge.Offset >= len(values) {
+			values = values[:0]
+		} else {
+			values = values[merge.Offset:]
+		}
+	}
+	if merge.Length > 0 && merge.Length < len(values) {
+		values = values[:merge.Length]
+	}
+	rows.idx = 0
+	rows.values = values
+	rows.size = len(values)
+	return
+}
+
+func (rows *Rows) sum(values []Row) (v []Row, err error) {
+	if len(values) < 2 {
+		v = values
+		return
+	}
+	row := make(Row, rows.columnLen)
+	copy(row, values[0])
+	for i, ct := range rows.columnTypes {
+		switch ct.Type {
+		case "int":
+			n := int64(0)
+			for _, value := range values {
+				x, xErr := value[i].Int()
+				if xErr != nil {
+					err = errors.Warning("sql: merge rows failed").WithCause(xErr).WithMeta("column", ct.Name)
+					return
+				}
+				n += x
+			}
+			err = row[i].Scan(n)
+			break
+		case "float":
+			n := float64(0)
+			for _, value := range values {
+				x, xErr := value[i].Float()
+				if xErr != nil {
+					err = errors.Warning("sql: merge rows failed").WithCause(xErr).WithMeta("column", ct.Name)
+					return
+				}
+				n += x
+			}
+			err = row[i].Scan(n)
+			break
+		default:
+			break
+		}
+		if err != nil {
+			return
+		}
+	}
+	v = []Row{row}
+	return
+}
+
+// compareColumn
+// null is less than others.
+func compareColumn(ct ColumnType, a *Column, b *Column) int {
+	if !a.Valid || !b.Valid {
+		if a.Valid == b.Valid {
+			return 0
+		}
+		if !a.Valid {
+			return -1
+		}
+		return 1
+	}
+	switch ct.Type {
+	case "int":
+		x, _ := a.Int()
+		y, _ := b.Int()
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+		return 0
+	case "float":
+		x, _ := a.Float()
+		y, _ := b.Float()
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+		return 0
+	case "bool":
+		x, _ := a.Bool()
+		y, _ := b.Bool()
+		if x == y {
+			return 0
+		}
+		if !x {
+			return -1
+		}
+		return 1
+	case "string":
+		x, _ := a.String()
+		y, _ := b.String()
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+		return 0
+	case "datetime":
+		x, _ := a.Datetime()
+		y, _ := b.Datetime()
+		return x.Compare(y)
+	case "date":
+		x, _ := a.Date()
+		y, _ := b.Date()
+		return x.ToTime().Compare(y.ToTime())
+	case "time":
+		x, _ := a.Time()
+		y, _ := b.Time()
+		return x.ToTime().Compare(y.ToTime())
+	default:
+		x, _ := a.Bytes()
+		y, _ := b.Bytes()
+		return bytes.Compare(x, y)
+	}
+}
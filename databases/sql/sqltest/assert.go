@@ -0,0 +1,55 @@
+package sqltest
+
+import (
+	stdctx "context"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns/commons/versions"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/runtime"
+	"github.com/aacfactory/fns/shareds"
+	"testing"
+)
+
+// Context
+// returns context with forced dialect, then use it to call specifications.Build* functions.
+// note: dialect package must be imported, such as `_ "github.com/aacfactory/fns-contrib/databases/postgres"`.
+func Context(dialect string) context.Context {
+	ctx := context.Acquire(stdctx.TODO())
+	return sql.ForceDialect(ctx, dialect)
+}
+
+// Use
+// binds db into ctx, then sql.Query and sql.Execute with ctx are handled by db without sql endpoint,
+// so functions which call them, such as dac.Insert, can be tested. statements are recorded as in transaction.
+// a runtime with local shared store is bound too, so bind custom runtime after it when it is required.
+func Use(ctx context.Context, db *Database) (err error) {
+	shared, sharedErr := shareds.Local(nil, shareds.LocalSharedConfig{})
+	if sharedErr != nil {
+		err = errors.Warning("sql: sqltest use database failed").WithCause(sharedErr)
+		return
+	}
+	runtime.With(ctx, runtime.New(Name, Name, versions.Origin(), nil, nil, nil, nil, nil, shared))
+	sql.UseTransaction(ctx, []byte(Name), &Transaction{
+		db: db,
+	})
+	return
+}
+
+// AssertQuery
+// compare query with expected after spaces are normalized.
+func AssertQuery(t testing.TB, query []byte, expected string) {
+	t.Helper()
+	if got, exp := NormalizeQuery(string(query)), NormalizeQuery(expected); got != exp {
+		t.Errorf("sqltest: query is not matched\n expected: %s\n      got: %s", exp, got)
+	}
+}
+
+// AssertArguments
+// compare arguments by reflect.DeepEqual unless expected is Argument, such as AnyArg().
+func AssertArguments(t testing.TB, arguments []any, expected ...any) {
+	t.Helper()
+	if err := MatchArguments(arguments, expected...); err != nil {
+		t.Errorf("sqltest: arguments are not matched, %v", err)
+	}
+}
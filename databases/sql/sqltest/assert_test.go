@@ -0,0 +1,49 @@
+package sqltest_test
+
+import (
+	"fmt"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+)
+
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertQuery(t *testing.T) {
+	r := &recorder{TB: t}
+	sqltest.AssertQuery(r, []byte("SELECT \"ID\"\n\tFROM  \"USER\" WHERE \"ID\" = $1"), `SELECT "ID" FROM "USER" WHERE "ID" = $1`)
+	if len(r.failures) > 0 {
+		t.Errorf("spaces must be normalized, %v", r.failures)
+		return
+	}
+	sqltest.AssertQuery(r, []byte(`SELECT "ID" FROM "USER"`), `SELECT "NAME" FROM "USER"`)
+	if len(r.failures) != 1 {
+		t.Errorf("different query must be failed")
+	}
+}
+
+func TestAssertArguments(t *testing.T) {
+	r := &recorder{TB: t}
+	sqltest.AssertArguments(r, []any{"foo", int64(1)}, "foo", sqltest.AnyArg())
+	if len(r.failures) > 0 {
+		t.Errorf("any argument must be matched, %v", r.failures)
+		return
+	}
+	sqltest.AssertArguments(r, []any{"foo", int64(1)}, "foo", 1)
+	if len(r.failures) != 1 {
+		t.Errorf("int must not be matched with int64")
+		return
+	}
+	sqltest.AssertArguments(r, []any{"foo"}, "foo", "bar")
+	if len(r.failures) != 2 {
+		t.Errorf("different length must be failed")
+	}
+}
@@ -0,0 +1,267 @@
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns/commons/bytex"
+	"strings"
+	"sync"
+)
+
+const (
+	Name = "sqltest"
+)
+
+type Options struct {
+	exact     bool
+	unordered bool
+}
+
+type Option func(options *Options)
+
+// ExactQuery
+// query of expectation is compared with statement after spaces are normalized, default is regexp.
+func ExactQuery() Option {
+	return func(options *Options) {
+		options.exact = true
+	}
+}
+
+// Unordered
+// expectations can be matched in any order.
+func Unordered() Option {
+	return func(options *Options) {
+		options.unordered = true
+	}
+}
+
+// New
+// fake database which records statements and returns scripted rows and results.
+// use it by sql.New(sql.WithDatabase(db), sql.WithDialect(dialect)), and kind of sql config must be `sqltest`.
+func New(options ...Option) *Database {
+	opt := Options{}
+	for _, option := range options {
+		option(&opt)
+	}
+	return &Database{
+		locker:       new(sync.Mutex),
+		exact:        opt.exact,
+		unordered:    opt.unordered,
+		expectations: make([]*Expectation, 0, 1),
+		statements:   make([]Statement, 0, 1),
+	}
+}
+
+// Statement
+// recorded statement, Kind is query, execute, begin, commit or rollback.
+type Statement struct {
+	Kind        string
+	Query       string
+	Arguments   []any
+	Transaction bool
+}
+
+type Database struct {
+	locker       sync.Locker
+	exact        bool
+	unordered    bool
+	expectations []*Expectation
+	statements   []Statement
+}
+
+func (db *Database) Name() string {
+	return Name
+}
+
+func (db *Database) Construct(_ databases.Options) (err error) {
+	return
+}
+
+func (db *Database) expect(kind string, query string) *Expectation {
+	db.locker.Lock()
+	defer db.locker.Unlock()
+	e, err := newExpectation(kind, query, db.exact)
+	if err != nil {
+		panic(fmt.Errorf("%+v", errors.Warning("sql: sqltest expect failed").WithCause(err).WithMeta("query", query)))
+	}
+	db.expectations = append(db.expectations, e)
+	return e
+}
+
+// ExpectQuery
+// query is regexp unless ExactQuery is used.
+func (db *Database) ExpectQuery(query string) *Expectation {
+	return db.expect(queryKind, query)
+}
+
+// ExpectExecute
+// query is regexp unless ExactQuery is used.
+func (db *Database) ExpectExecute(query string) *Expectation {
+	return db.expect(executeKind, query)
+}
+
+func (db *Database) ExpectBegin() *Expectation {
+	return db.expect(beginKind, "")
+}
+
+func (db *Database) ExpectCommit() *Expectation {
+	return db.expect(commitKind, "")
+}
+
+func (db *Database) ExpectRollback() *Expectation {
+	return db.expect(rollbackKind, "")
+}
+
+// Statements
+// returns recorded statements.
+func (db *Database) Statements() []Statement {
+	db.locker.Lock()
+	defer db.locker.Unlock()
+	statements := make([]Statement, len(db.statements))
+	copy(statements, db.statements)
+	return statements
+}
+
+// ExpectationsWereMet
+// returns error when some expectations were not matched.
+func (db *Database) ExpectationsWereMet() (err error) {
+	db.locker.Lock()
+	defer db.locker.Unlock()
+	unmet := make([]string, 0, 1)
+	for _, e := range db.expectations {
+		if !e.triggered {
+			unmet = append(unmet, e.String())
+		}
+	}
+	if len(unmet) > 0 {
+		err = errors.Warning("sql: sqltest expectations were not met").WithCause(fmt.Errorf("%s", strings.Join(unmet, "; ")))
+		return
+	}
+	return
+}
+
+// Reset
+// remove expectations and recorded statements.
+func (db *Database) Reset() {
+	db.locker.Lock()
+	db.expectations = db.expectations[:0]
+	db.statements = db.statements[:0]
+	db.locker.Unlock()
+}
+
+func (db *Database) match(kind string, query []byte, args []any, tx bool) (e *Expectation, err error) {
+	db.locker.Lock()
+	defer db.locker.Unlock()
+	statement := Statement{
+		Kind:        kind,
+		Query:       string(query),
+		Arguments:   args,
+		Transaction: tx,
+	}
+	db.statements = append(db.statements, statement)
+	for _, expectation := range db.expectations {
+		if expectation.triggered {
+			continue
+		}
+		matchErr := expectation.match(kind, bytex.ToString(query), args)
+		if matchErr == nil {
+			expectation.triggered = true
+			e = expectation
+			return
+		}
+		if !db.unordered {
+			err = errors.Warning("sql: sqltest statement was not expected").WithCause(matchErr).
+				WithMeta("kind", kind).WithMeta("query", statement.Query)
+			return
+		}
+	}
+	err = errors.Warning("sql: sqltest statement was not expected").WithCause(fmt.Errorf("no expectation matched")).
+		WithMeta("kind", kind).WithMeta("query", statement.Query)
+	return
+}
+
+func (db *Database) Begin(_ context.Context, _ databases.TransactionOptions) (tx databases.Transaction, err error) {
+	e, matchErr := db.match(beginKind, nil, nil, false)
+	if matchErr != nil {
+		err = matchErr
+		return
+	}
+	if e.err != nil {
+		err = e.err
+		return
+	}
+	tx = &Transaction{
+		db: db,
+	}
+	return
+}
+
+func (db *Database) Query(_ context.Context, query []byte, args []any) (rows databases.Rows, err error) {
+	e, matchErr := db.match(queryKind, query, args, false)
+	if matchErr != nil {
+		err = matchErr
+		return
+	}
+	rows, err = e.rowsOrError()
+	return
+}
+
+func (db *Database) Execute(_ context.Context, query []byte, args []any) (result databases.Result, err error) {
+	e, matchErr := db.match(executeKind, query, args, false)
+	if matchErr != nil {
+		err = matchErr
+		return
+	}
+	result, err = e.resultOrError()
+	return
+}
+
+func (db *Database) Close(_ context.Context) (err error) {
+	return
+}
+
+type Transaction struct {
+	db *Database
+}
+
+func (tx *Transaction) Commit() (err error) {
+	e, matchErr := tx.db.match(commitKind, nil, nil, true)
+	if matchErr != nil {
+		err = matchErr
+		return
+	}
+	err = e.err
+	return
+}
+
+func (tx *Transaction) Rollback() (err error) {
+	e, matchErr := tx.db.match(rollbackKind, nil, nil, true)
+	if matchErr != nil {
+		err = matchErr
+		return
+	}
+	err = e.err
+	return
+}
+
+func (tx *Transaction) Query(_ context.Context, query []byte, args []any) (rows databases.Rows, err error) {
+	e, matchErr := tx.db.match(queryKind, query, args, true)
+	if matchErr != nil {
+		err = matchErr
+		return
+	}
+	rows, err = e.rowsOrError()
+	return
+}
+
+func (tx *Transaction) Execute(_ context.Context, query []byte, args []any) (result databases.Result, err error) {
+	e, matchErr := tx.db.match(executeKind, query, args, true)
+	if matchErr != nil {
+		err = matchErr
+		return
+	}
+	result, err = e.resultOrError()
+	return
+}
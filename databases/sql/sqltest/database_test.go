@@ -0,0 +1,69 @@
+package sqltest_test
+
+import (
+	"context"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"testing"
+	"time"
+)
+
+func TestDatabase(t *testing.T) {
+	db := sqltest.New()
+	db.ExpectBegin()
+	db.ExpectQuery(`SELECT .+ FROM "USER"`).WithArgs(sqltest.AnyArg()).
+		WillReturnRows(sqltest.NewRows("ID", "NAME", "CREATE_AT").AddRow("1", "foo", time.Now()).AddRow("2", nil, nil))
+	db.ExpectExecute(`UPDATE "USER"`).WithArgs("bar", "1").WillReturnResult(0, 1)
+	db.ExpectCommit()
+
+	ctx := context.TODO()
+	tx, beginErr := db.Begin(ctx, databases.TransactionOptions{})
+	if beginErr != nil {
+		t.Errorf("%+v", beginErr)
+		return
+	}
+	rows, queryErr := tx.Query(ctx, []byte(`SELECT "ID", "NAME", "CREATE_AT" FROM "USER" WHERE "ID" = $1`), []any{"1"})
+	if queryErr != nil {
+		t.Errorf("%+v", queryErr)
+		return
+	}
+	for rows.Next() {
+		id := ""
+		name := ""
+		createAt := time.Time{}
+		scanErr := rows.Scan(&id, &name, &createAt)
+		if scanErr != nil {
+			t.Errorf("%+v", scanErr)
+			return
+		}
+		t.Log(id, name, createAt)
+	}
+	_ = rows.Close()
+	result, execErr := tx.Execute(ctx, []byte(`UPDATE "USER" SET "NAME" = $1 WHERE "ID" = $2`), []any{"bar", "1"})
+	if execErr != nil {
+		t.Errorf("%+v", execErr)
+		return
+	}
+	if result.RowsAffected != 1 {
+		t.Errorf("rows affected is not matched")
+		return
+	}
+	if err := db.ExpectationsWereMet(); err == nil {
+		t.Errorf("commit was not called, but expectations were met")
+		return
+	}
+	commitErr := tx.Commit()
+	if commitErr != nil {
+		t.Errorf("%+v", commitErr)
+		return
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	_, unexpectedErr := db.Query(ctx, []byte(`SELECT 1`), nil)
+	if unexpectedErr == nil {
+		t.Errorf("unexpected query was passed")
+		return
+	}
+}
@@ -0,0 +1,173 @@
+package sqltest
+
+import (
+	"fmt"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const (
+	queryKind    = "query"
+	executeKind  = "execute"
+	beginKind    = "begin"
+	commitKind   = "commit"
+	rollbackKind = "rollback"
+)
+
+// Argument
+// custom matcher of argument.
+type Argument interface {
+	Match(v any) bool
+}
+
+type anyArgument struct{}
+
+func (a anyArgument) Match(_ any) bool {
+	return true
+}
+
+// AnyArg
+// matches any argument, such as generated id and time.
+func AnyArg() Argument {
+	return anyArgument{}
+}
+
+func newExpectation(kind string, query string, exact bool) (e *Expectation, err error) {
+	e = &Expectation{
+		kind:  kind,
+		query: query,
+		exact: exact,
+	}
+	if query != "" && !exact {
+		e.pattern, err = regexp.Compile(query)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+type Expectation struct {
+	kind      string
+	query     string
+	exact     bool
+	pattern   *regexp.Regexp
+	args      []any
+	hasArgs   bool
+	rows      *Rows
+	result    databases.Result
+	err       error
+	triggered bool
+}
+
+// WithArgs
+// arguments are compared by reflect.DeepEqual unless it is Argument.
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args = args
+	e.hasArgs = true
+	return e
+}
+
+func (e *Expectation) WillReturnRows(rows *Rows) *Expectation {
+	e.rows = rows
+	return e
+}
+
+func (e *Expectation) WillReturnResult(lastInsertId int64, rowsAffected int64) *Expectation {
+	e.result = databases.Result{
+		LastInsertId: lastInsertId,
+		RowsAffected: rowsAffected,
+	}
+	return e
+}
+
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+func (e *Expectation) String() string {
+	if e.query == "" {
+		return e.kind
+	}
+	return fmt.Sprintf("%s: %s", e.kind, e.query)
+}
+
+func (e *Expectation) match(kind string, query string, args []any) (err error) {
+	if e.kind != kind {
+		err = fmt.Errorf("%s is expected, but got %s", e.String(), kind)
+		return
+	}
+	if e.query != "" {
+		if e.exact {
+			if NormalizeQuery(e.query) != NormalizeQuery(query) {
+				err = fmt.Errorf("%s is expected, but got %s", e.query, query)
+				return
+			}
+		} else if !e.pattern.MatchString(query) {
+			err = fmt.Errorf("%s is expected, but got %s", e.query, query)
+			return
+		}
+	}
+	if e.hasArgs {
+		err = MatchArguments(args, e.args...)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (e *Expectation) rowsOrError() (rows databases.Rows, err error) {
+	if e.err != nil {
+		err = e.err
+		return
+	}
+	if e.rows == nil {
+		rows = NewRows().open()
+		return
+	}
+	rows = e.rows.open()
+	return
+}
+
+func (e *Expectation) resultOrError() (result databases.Result, err error) {
+	if e.err != nil {
+		err = e.err
+		return
+	}
+	result = e.result
+	return
+}
+
+// NormalizeQuery
+// trim spaces and replace continuous spaces with one space.
+func NormalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// MatchArguments
+// compare arguments by reflect.DeepEqual unless expected is Argument.
+func MatchArguments(args []any, expected ...any) (err error) {
+	if len(args) != len(expected) {
+		err = fmt.Errorf("%d arguments are expected, but got %d", len(expected), len(args))
+		return
+	}
+	for i, arg := range args {
+		exp := expected[i]
+		if matcher, ok := exp.(Argument); ok {
+			if !matcher.Match(arg) {
+				err = fmt.Errorf("argument %d is not matched, got %v", i, arg)
+				return
+			}
+			continue
+		}
+		if !reflect.DeepEqual(arg, exp) {
+			err = fmt.Errorf("argument %d is not matched, %v(%T) is expected, but got %v(%T)", i, exp, exp, arg, arg)
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,142 @@
+package sqltest
+
+import (
+	stdsql "database/sql"
+	"fmt"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"reflect"
+)
+
+var (
+	bytesType = reflect.TypeOf([]byte{})
+)
+
+// NewRows
+// scripted rows, scan type of column is type of first non nil value in column.
+func NewRows(columns ...string) *Rows {
+	return &Rows{
+		columns:   columns,
+		databases: make([]string, len(columns)),
+		values:    make([][]any, 0, 1),
+	}
+}
+
+type Rows struct {
+	columns   []string
+	databases []string
+	values    [][]any
+}
+
+// DatabaseTypes
+// set database type names of columns, such as VARCHAR, DATE and TIME.
+func (rows *Rows) DatabaseTypes(types ...string) *Rows {
+	copy(rows.databases, types)
+	return rows
+}
+
+func (rows *Rows) AddRow(values ...any) *Rows {
+	if len(values) != len(rows.columns) {
+		panic(fmt.Errorf("sql: sqltest add row failed, %d values are expected, but got %d", len(rows.columns), len(values)))
+	}
+	rows.values = append(rows.values, values)
+	return rows
+}
+
+func (rows *Rows) open() *cursor {
+	types := make([]databases.ColumnType, len(rows.columns))
+	for i := range rows.columns {
+		scanType := bytesType
+		for _, row := range rows.values {
+			if row[i] != nil {
+				scanType = reflect.TypeOf(row[i])
+				break
+			}
+		}
+		types[i] = databases.ColumnType{
+			DatabaseType: rows.databases[i],
+			ScanType:     scanType,
+		}
+	}
+	return &cursor{
+		columns: rows.columns,
+		types:   types,
+		values:  rows.values,
+		idx:     -1,
+	}
+}
+
+type cursor struct {
+	columns []string
+	types   []databases.ColumnType
+	values  [][]any
+	idx     int
+}
+
+func (c *cursor) Columns() ([]string, error) {
+	return c.columns, nil
+}
+
+func (c *cursor) ColumnTypes() ([]databases.ColumnType, error) {
+	return c.types, nil
+}
+
+func (c *cursor) Next() bool {
+	if c.idx+1 >= len(c.values) {
+		return false
+	}
+	c.idx++
+	return true
+}
+
+func (c *cursor) Scan(dst ...any) (err error) {
+	if c.idx < 0 || c.idx >= len(c.values) {
+		err = stdsql.ErrNoRows
+		return
+	}
+	row := c.values[c.idx]
+	if len(dst) != len(row) {
+		err = fmt.Errorf("sql: sqltest scan failed, %d dst are expected, but got %d", len(row), len(dst))
+		return
+	}
+	for i, d := range dst {
+		err = assign(d, row[i])
+		if err != nil {
+			err = fmt.Errorf("sql: sqltest scan %s failed, %v", c.columns[i], err)
+			return
+		}
+	}
+	return
+}
+
+func (c *cursor) Close() error {
+	c.idx = len(c.values)
+	return nil
+}
+
+func assign(dst any, src any) (err error) {
+	if scanner, ok := dst.(stdsql.Scanner); ok {
+		err = scanner.Scan(src)
+		return
+	}
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		err = fmt.Errorf("dst must be non nil ptr")
+		return
+	}
+	dv = dv.Elem()
+	if src == nil {
+		dv.Set(reflect.Zero(dv.Type()))
+		return
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dv.Type()) {
+		dv.Set(sv)
+		return
+	}
+	if sv.Type().ConvertibleTo(dv.Type()) {
+		dv.Set(sv.Convert(dv.Type()))
+		return
+	}
+	err = fmt.Errorf("%s can not be assigned to %s", sv.Type(), dv.Type())
+	return
+}
@@ -0,0 +1,178 @@
+package sql
+
+import (
+	stdctx "context"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns-contrib/databases/sql/transactions"
+	"github.com/aacfactory/fns/context"
+	"sync"
+	"time"
+)
+
+var (
+	timeoutContextKey = []byte("@fns:sql:timeout")
+	statementTimeouts = sync.Map{}
+)
+
+// WithTimeout
+// statements in fn are limited by timeout, it is prior to statementTimeout of config.
+// statement is cancelled when timeout or ctx is done, and server side limit is set when dialect supports it.
+// previous timeout of ctx is restored after fn returns, so statements after it are not affected.
+func WithTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) (err error)) (err error) {
+	if timeout <= 0 {
+		err = fn(ctx)
+		return
+	}
+	// local value can not be overwritten, so timeout is held by holder
+	holder, has := context.LocalValue[*timeoutHolder](ctx, timeoutContextKey)
+	if !has {
+		holder = &timeoutHolder{}
+		ctx.SetLocalValue(timeoutContextKey, holder)
+	}
+	prev := holder.value
+	holder.value = timeout
+	defer func() {
+		holder.value = prev
+	}()
+	err = fn(ctx)
+	return
+}
+
+type timeoutHolder struct {
+	value time.Duration
+}
+
+func loadTimeout(ctx context.Context) (timeout time.Duration) {
+	holder, has := context.LocalValue[*timeoutHolder](ctx, timeoutContextKey)
+	if has {
+		timeout = holder.value
+	}
+	return
+}
+
+// StatementTimeout
+// server side limit of statement of dialect.
+type StatementTimeout interface {
+	// Hint
+	// render limit into query, such as MAX_EXECUTION_TIME hint of mysql, returns query when it is not supported.
+	Hint(query []byte, timeout time.Duration) []byte
+	// Session
+	// statement which sets limit of following statements in transaction, such as SET LOCAL statement_timeout of postgres, returns nil when it is not supported.
+	// it is executed at beginning of transaction, and before statement whose timeout is not current, zero timeout means resetting to default.
+	Session(timeout time.Duration) []byte
+}
+
+// ConnectionStatementTimeout
+// server side limit of each connection of dialect, such as SET statement_timeout of postgres.
+// it is executed when connection of pool is opened with statementTimeout of config, so statements outside transaction are limited too.
+type ConnectionStatementTimeout interface {
+	ConnectionSession(timeout time.Duration) []byte
+}
+
+// RegisterStatementTimeout
+// dac dialect is registered automatically when RegisterDialect and dialect implements StatementTimeout.
+func RegisterStatementTimeout(dialect string, timeout StatementTimeout) {
+	if dialect == "" || timeout == nil {
+		return
+	}
+	statementTimeouts.Store(dialect, timeout)
+}
+
+func getStatementTimeout(dialect string) (timeout StatementTimeout, has bool) {
+	v, exist := statementTimeouts.Load(dialect)
+	if !exist {
+		return
+	}
+	timeout, has = v.(StatementTimeout)
+	return
+}
+
+func statementTimeoutHint(dialect string, query []byte, timeout time.Duration) []byte {
+	if timeout <= 0 || dialect == "" {
+		return query
+	}
+	st, has := getStatementTimeout(dialect)
+	if !has {
+		return query
+	}
+	return st.Hint(query, timeout)
+}
+
+func statementTimeoutSession(dialect string, timeout time.Duration) []byte {
+	if timeout <= 0 || dialect == "" {
+		return nil
+	}
+	st, has := getStatementTimeout(dialect)
+	if !has {
+		return nil
+	}
+	return st.Session(timeout)
+}
+
+func statementTimeoutConnectionSession(dialect string, timeout time.Duration) []byte {
+	if timeout <= 0 || dialect == "" {
+		return nil
+	}
+	st, has := getStatementTimeout(dialect)
+	if !has {
+		return nil
+	}
+	cst, ok := st.(ConnectionStatementTimeout)
+	if !ok {
+		return nil
+	}
+	return cst.ConnectionSession(timeout)
+}
+
+// useStatementTimeout
+// execute session statement of dialect in tx when timeout is not the current limit of tx.
+func useStatementTimeout(ctx stdctx.Context, dialect string, tx *transactions.Transaction, timeout time.Duration) (err error) {
+	if dialect == "" || tx.SessionTimeout == timeout {
+		return
+	}
+	st, has := getStatementTimeout(dialect)
+	if !has {
+		return
+	}
+	session := st.Session(timeout)
+	if len(session) == 0 {
+		return
+	}
+	// session statement is not a write, so it is executed by underlying transaction which is not checked by read only
+	_, err = tx.Transaction.Execute(ctx, session, nil)
+	if err != nil {
+		return
+	}
+	tx.SessionTimeout = timeout
+	return
+}
+
+// statementContext
+// returns context which is done when ctx is done or timeout, and with sharding route.
+// cancel must be called after statement is finished, for rows, it is called when rows are closed.
+func statementContext(ctx stdctx.Context, sharding Sharding, timeout time.Duration) (v stdctx.Context, cancel stdctx.CancelFunc) {
+	v = ctx
+	if len(sharding.Keys) > 0 || sharding.Single {
+		v = databases.WithShardingRoute(v, databases.ShardingRoute{
+			Keys:   sharding.Keys,
+			Single: sharding.Single,
+		})
+	}
+	if timeout > 0 {
+		v, cancel = stdctx.WithTimeout(v, timeout)
+		return
+	}
+	v, cancel = stdctx.WithCancel(v)
+	return
+}
+
+type cancelableRows struct {
+	databases.Rows
+	cancel stdctx.CancelFunc
+}
+
+func (rows *cancelableRows) Close() (err error) {
+	err = rows.Rows.Close()
+	rows.cancel()
+	return
+}
@@ -0,0 +1,30 @@
+package sql
+
+import (
+	stdctx "context"
+	"github.com/aacfactory/fns/context"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout(t *testing.T) {
+	ctx := context.Acquire(stdctx.TODO())
+	err := WithTimeout(ctx, time.Second, func(ctx context.Context) (err error) {
+		if timeout := loadTimeout(ctx); timeout != time.Second {
+			t.Errorf("timeout must be 1s, got %s", timeout)
+		}
+		return WithTimeout(ctx, 2*time.Second, func(ctx context.Context) (err error) {
+			if timeout := loadTimeout(ctx); timeout != 2*time.Second {
+				t.Errorf("timeout must be 2s, got %s", timeout)
+			}
+			return
+		})
+	})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if timeout := loadTimeout(ctx); timeout != 0 {
+		t.Errorf("timeout must be restored after fn, got %s", timeout)
+	}
+}
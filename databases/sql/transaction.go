@@ -11,6 +11,8 @@ import (
 	"github.com/aacfactory/fns/logs"
 	"github.com/aacfactory/fns/runtime"
 	"github.com/aacfactory/fns/services"
+	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -27,6 +29,13 @@ func loadTransaction(ctx context.Context) (tx *transactions.Transaction, has boo
 	return
 }
 
+// UseTransaction
+// binds tx into ctx, then statements with ctx are executed by tx directly instead of sql endpoint.
+// it is designed for test stand-in, such as sqltest, do not use it in services.
+func UseTransaction(ctx context.Context, id []byte, tx databases.Transaction) {
+	withTransaction(ctx, transactions.NewTransaction(id, nil, tx, time.Now().Add(10*time.Minute)))
+}
+
 func removeTransaction(ctx context.Context) {
 	if _, has := loadTransaction(ctx); has {
 		ctx.RemoveLocalValue(transactionContextKey)
@@ -34,6 +43,194 @@ func removeTransaction(ctx context.Context) {
 	return
 }
 
+// InTransaction
+// check whether ctx is in transaction or not.
+func InTransaction(ctx context.Context) bool {
+	if _, has := loadTransaction(ctx); has {
+		return true
+	}
+	_, has, _ := loadTransactionInfo(ctx)
+	return has
+}
+
+// +-------------------------------------------------------------------------------------------------------------------+
+
+var (
+	// committedHooks
+	// key is transaction id, value is *transactionHooks
+	committedHooks = sync.Map{}
+	// committedHooksMaxAge
+	// hooks of transaction which is not committed or rolled back in this process are dropped after it.
+	committedHooksMaxAge   = 10 * time.Minute
+	committedHooksSweeping = sync.Once{}
+)
+
+type transactionHooks struct {
+	locker   sync.Mutex
+	deadline time.Time
+	values   []func(ctx context.Context)
+}
+
+// AfterCommitted
+// hook is called after transaction of ctx is committed, and it is dropped when transaction is rolled back.
+// when ctx is not in transaction, hook is called at once.
+// note: hooks are kept in current process, so the transaction should be committed in the process which registers them,
+// use IncrAfterCommitted to invalidate caches across cluster.
+func AfterCommitted(ctx context.Context, hook func(ctx context.Context)) {
+	if hook == nil {
+		return
+	}
+	info, has, _ := loadTransactionInfo(ctx)
+	if !has {
+		hook(ctx)
+		return
+	}
+	committedHooksSweeping.Do(func() {
+		go sweepCommittedHooks()
+	})
+	value, _ := committedHooks.LoadOrStore(info.Id, &transactionHooks{
+		deadline: time.Now().Add(committedHooksMaxAge),
+	})
+	hooks := value.(*transactionHooks)
+	hooks.locker.Lock()
+	hooks.values = append(hooks.values, hook)
+	hooks.locker.Unlock()
+}
+
+// sweepCommittedHooks
+// drop expired hooks periodically.
+func sweepCommittedHooks() {
+	ticker := time.NewTicker(committedHooksMaxAge)
+	for now := range ticker.C {
+		committedHooks.Range(func(key, value any) bool {
+			if value.(*transactionHooks).deadline.Before(now) {
+				committedHooks.Delete(key)
+			}
+			return true
+		})
+	}
+}
+
+func runCommittedHooks(ctx context.Context, id string) {
+	value, has := committedHooks.LoadAndDelete(id)
+	if !has {
+		return
+	}
+	hooks := value.(*transactionHooks)
+	hooks.locker.Lock()
+	values := hooks.values
+	hooks.values = nil
+	hooks.locker.Unlock()
+	for _, hook := range values {
+		hook(ctx)
+	}
+}
+
+func dropCommittedHooks(id string) {
+	committedHooks.Delete(id)
+}
+
+// +-------------------------------------------------------------------------------------------------------------------+
+
+var (
+	transactionIncrFnName = []byte("incr_after_committed")
+)
+
+// IncrAfterCommitted
+// key of shared store is increased after transaction of ctx is committed, and it is dropped when transaction is rolled back.
+// key is kept with transaction in sql service, so it works when ctx is in a fn of another node.
+// when ctx is not in transaction, key is increased at once.
+func IncrAfterCommitted(ctx context.Context, key []byte) (err error) {
+	if tx, hasTx := loadTransaction(ctx); hasTx && !tx.Closed() {
+		tx.IncrAfterCommitted(key)
+		return
+	}
+	info, hasInfo, loadInfoErr := loadTransactionInfo(ctx)
+	if loadInfoErr != nil {
+		err = errors.Warning("sql: incr after committed failed").WithCause(loadInfoErr)
+		return
+	}
+	if !hasInfo {
+		if _, incrErr := runtime.SharedStore(ctx).Incr(ctx, key, 1); incrErr != nil {
+			err = errors.Warning("sql: incr after committed failed").WithCause(incrErr)
+			return
+		}
+		return
+	}
+	eps := runtime.Endpoints(ctx)
+	ep := endpointName
+	if epn := used(ctx); len(epn) > 0 {
+		ep = epn
+	}
+	_, handleErr := eps.Request(ctx, ep, transactionIncrFnName, transactionIncrParam{
+		Id:  info.Id,
+		Key: key,
+	}, services.WithEndpointId(bytex.FromString(info.EndpointId)))
+	if handleErr != nil {
+		err = handleErr
+		return
+	}
+	return
+}
+
+type transactionIncrParam struct {
+	Id  string `json:"id" avro:"id"`
+	Key []byte `json:"key" avro:"key"`
+}
+
+type transactionIncrFn struct {
+	group *transactions.Group
+}
+
+func (fn *transactionIncrFn) Name() string {
+	return string(transactionIncrFnName)
+}
+
+func (fn *transactionIncrFn) Internal() bool {
+	return true
+}
+
+func (fn *transactionIncrFn) Readonly() bool {
+	return false
+}
+
+func (fn *transactionIncrFn) Handle(r services.Request) (v interface{}, err error) {
+	param, paramErr := services.ValueOfParam[transactionIncrParam](r.Param())
+	if paramErr != nil {
+		err = errors.Warning("sql: incr after committed failed").WithCause(paramErr)
+		return
+	}
+	if len(param.Key) == 0 {
+		err = errors.Warning("sql: incr after committed failed").WithCause(fmt.Errorf("key is required"))
+		return
+	}
+	tx, hasTx := fn.group.Get(bytex.FromString(param.Id))
+	if !hasTx || tx.Closed() {
+		err = errors.Warning("sql: incr after committed failed").WithCause(fmt.Errorf("transaction was timeout"))
+		return
+	}
+	tx.IncrAfterCommitted(param.Key)
+	return
+}
+
+// incrCommitted
+// increase keys of committed transaction, failure of it does not fail the commit.
+func incrCommitted(ctx context.Context, tx *transactions.Transaction) {
+	keys := tx.Increments()
+	if len(keys) == 0 {
+		return
+	}
+	store := runtime.SharedStore(ctx)
+	for _, key := range keys {
+		if _, incrErr := store.Incr(ctx, key, 1); incrErr != nil {
+			log := logs.Load(ctx)
+			if log.WarnEnabled() {
+				log.Warn().Cause(incrErr).With("transaction", tx.Id).Message(fmt.Sprintf("sql: incr %s after committed failed", key))
+			}
+		}
+	}
+}
+
 // +-------------------------------------------------------------------------------------------------------------------+
 
 var (
@@ -78,12 +275,29 @@ func WithTransactionId(id string) databases.TransactionOption {
 	}
 }
 
-func Readonly() databases.TransactionOption {
+// ReadOnly
+// transaction is read only, it is begun on slaver when kind of database is masterSlave,
+// and Execute or Query which writes (such as INSERT ... RETURNING) in it returns databases.ErrReadOnlyTransaction.
+func ReadOnly() databases.TransactionOption {
 	return func(options *databases.TransactionOptions) {
 		options.Readonly = true
 	}
 }
 
+// Readonly
+// Deprecated: use ReadOnly
+func Readonly() databases.TransactionOption {
+	return ReadOnly()
+}
+
+// CrossShard
+// transaction can use more than one shards when database is sharding, note: it is not atomic.
+func CrossShard() databases.TransactionOption {
+	return func(options *databases.TransactionOptions) {
+		options.CrossShard = true
+	}
+}
+
 func Begin(ctx context.Context, options ...databases.TransactionOption) (err error) {
 	r, hasRequest := services.TryLoadRequest(ctx)
 	if !hasRequest {
@@ -99,10 +313,11 @@ func Begin(ctx context.Context, options ...databases.TransactionOption) (err err
 		id = r.Header().RequestId()
 	}
 	param := transactionBeginParam{
-		Readonly:  opt.Readonly,
-		Isolation: opt.Isolation,
-		Id:        id,
-		ProcessId: r.Header().ProcessId(),
+		Readonly:   opt.Readonly,
+		Isolation:  opt.Isolation,
+		Id:         id,
+		ProcessId:  r.Header().ProcessId(),
+		CrossShard: opt.CrossShard,
 	}
 	eps := runtime.Endpoints(ctx)
 	ep := endpointName
@@ -150,10 +365,11 @@ var (
 )
 
 type transactionBeginParam struct {
-	Readonly  bool                `json:"readonly" avro:"readonly"`
-	Isolation databases.Isolation `json:"isolation" avro:"isolation"`
-	Id        []byte              `json:"id" avro:"id"`
-	ProcessId []byte              `json:"processId" avro:"processId"`
+	Readonly   bool                `json:"readonly" avro:"readonly"`
+	Isolation  databases.Isolation `json:"isolation" avro:"isolation"`
+	Id         []byte              `json:"id" avro:"id"`
+	ProcessId  []byte              `json:"processId" avro:"processId"`
+	CrossShard bool                `json:"crossShard" avro:"crossShard"`
 }
 
 type transactionBeginFn struct {
@@ -162,6 +378,8 @@ type transactionBeginFn struct {
 	isolation  databases.Isolation
 	db         databases.Database
 	group      *transactions.Group
+	dialect    string
+	timeout    time.Duration
 }
 
 func (fn *transactionBeginFn) Name() string {
@@ -211,18 +429,38 @@ func (fn *transactionBeginFn) Handle(r services.Request) (v interface{}, err err
 		param.Isolation = fn.isolation
 	}
 	value, beginErr := fn.db.Begin(context.TODO(), databases.TransactionOptions{
-		Isolation: param.Isolation,
-		Readonly:  param.Readonly,
+		Isolation:  param.Isolation,
+		Readonly:   param.Readonly,
+		CrossShard: param.CrossShard,
 	})
 	if beginErr != nil {
 		err = errors.Warning("sql: begin transaction failed").WithCause(beginErr)
 		return
 	}
+	if param.Readonly {
+		if session := readOnlySession(fn.dialect); len(session) > 0 {
+			if _, sessionErr := value.Execute(context.TODO(), session, nil); sessionErr != nil {
+				_ = value.Rollback()
+				err = errors.Warning("sql: begin transaction failed").WithCause(sessionErr).WithMeta("query", string(session))
+				return
+			}
+		}
+	}
+	if session := statementTimeoutSession(fn.dialect, fn.timeout); len(session) > 0 {
+		if _, sessionErr := value.Execute(context.TODO(), session, nil); sessionErr != nil {
+			_ = value.Rollback()
+			err = errors.Warning("sql: begin transaction failed").WithCause(sessionErr).WithMeta("query", string(session))
+			return
+		}
+	}
 	tx, has = fn.group.Set(param.Id, param.ProcessId, value)
 	if !has {
 		err = errors.Warning("sql: begin transaction failed").WithCause(fmt.Errorf("maybe duplicate begon"))
 		return
 	}
+	tx.StatementTimeout = fn.timeout
+	tx.SessionTimeout = fn.timeout
+	tx.ReadOnly = param.Readonly
 	v = transactionAddress{
 		Id:         unsafe.String(unsafe.SliceData(param.Id), len(param.Id)),
 		EndpointId: fn.endpointId,
@@ -284,6 +522,7 @@ func Commit(ctx context.Context) (err error) {
 	case 2:
 		removeTransactionInfo(ctx)
 		removeTransaction(ctx)
+		runCommittedHooks(ctx, info.Id)
 		if log != nil && log.DebugEnabled() {
 			log.Debug().With("transaction", "commit").Caller().Message(fmt.Sprintf("sql: transaction committed"))
 		}
@@ -340,6 +579,7 @@ func (fn *transactionCommitFn) Handle(r services.Request) (v interface{}, err er
 	if tx.Closed() {
 		v = 2
 		fn.group.Remove(tid)
+		incrCommitted(r, tx)
 	}
 	return
 }
@@ -382,6 +622,8 @@ func Rollback(ctx context.Context) {
 	removeTransactionInfo(ctx)
 	// remove tx
 	removeTransaction(ctx)
+	// drop hooks
+	dropCommittedHooks(info.Id)
 }
 
 type transactionRollbackParam struct {
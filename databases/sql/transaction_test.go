@@ -0,0 +1,66 @@
+package sql
+
+import (
+	stdctx "context"
+	"github.com/aacfactory/fns-contrib/databases/sql/transactions"
+	"github.com/aacfactory/fns/context"
+	"testing"
+	"time"
+)
+
+func TestAfterCommitted(t *testing.T) {
+	ctx := context.Acquire(stdctx.TODO())
+	called := 0
+	hook := func(ctx context.Context) {
+		called++
+	}
+	// not in transaction
+	AfterCommitted(ctx, hook)
+	if called != 1 {
+		t.Errorf("hook must be called at once when not in transaction")
+		return
+	}
+	// committed
+	withTransactionInfo(ctx, transactionInfo{Id: "committed"})
+	AfterCommitted(ctx, hook)
+	if called != 1 {
+		t.Errorf("hook must be deferred in transaction")
+		return
+	}
+	removeTransactionInfo(ctx)
+	runCommittedHooks(ctx, "committed")
+	if called != 2 {
+		t.Errorf("hook must be called after committed")
+		return
+	}
+	// rolled back
+	withTransactionInfo(ctx, transactionInfo{Id: "rolled back"})
+	AfterCommitted(ctx, hook)
+	removeTransactionInfo(ctx)
+	dropCommittedHooks("rolled back")
+	runCommittedHooks(ctx, "rolled back")
+	if called != 2 {
+		t.Errorf("hook must be dropped after rolled back")
+		return
+	}
+}
+
+func TestIncrAfterCommitted(t *testing.T) {
+	ctx := context.Acquire(stdctx.TODO())
+	tx := transactions.NewTransaction([]byte("incr"), nil, &readOnlyTestTransaction{}, time.Now().Add(time.Minute))
+	withTransaction(ctx, tx)
+	for i := 0; i < 2; i++ {
+		if err := IncrAfterCommitted(ctx, []byte("version:USER")); err != nil {
+			t.Errorf("%+v", err)
+			return
+		}
+	}
+	if err := IncrAfterCommitted(ctx, []byte("version:POST")); err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	keys := tx.Increments()
+	if len(keys) != 2 || string(keys[0]) != "version:USER" || string(keys[1]) != "version:POST" {
+		t.Errorf("keys must be deferred with transaction once, got %q", keys)
+	}
+}
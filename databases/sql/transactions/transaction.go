@@ -1,6 +1,8 @@
 package transactions
 
 import (
+	"bytes"
+	"context"
 	"github.com/aacfactory/errors"
 	"github.com/aacfactory/fns-contrib/databases/sql/databases"
 	"sync"
@@ -27,8 +29,20 @@ type Transaction struct {
 	processId []byte
 	Acquires  int64
 	Deadline  time.Time
-	closed    bool
-	locker    sync.Locker
+	// StatementTimeout
+	// default timeout of statements in transaction
+	StatementTimeout time.Duration
+	// SessionTimeout
+	// current server side limit of statements which is set by session statement of dialect, zero means default of database
+	SessionTimeout time.Duration
+	// ReadOnly
+	// execute and query which writes, such as INSERT ... RETURNING, are rejected when transaction is read only
+	ReadOnly bool
+	// increments
+	// keys of shared store which are increased after transaction is committed
+	increments [][]byte
+	closed     bool
+	locker     sync.Locker
 }
 
 func (tx *Transaction) ProcessId() (id []byte) {
@@ -36,6 +50,47 @@ func (tx *Transaction) ProcessId() (id []byte) {
 	return
 }
 
+func (tx *Transaction) Query(ctx context.Context, query []byte, args []any) (rows databases.Rows, err error) {
+	if tx.ReadOnly && databases.IsWriteStatement(query) {
+		err = databases.ErrReadOnlyTransaction
+		return
+	}
+	rows, err = tx.Transaction.Query(ctx, query, args)
+	return
+}
+
+func (tx *Transaction) Execute(ctx context.Context, query []byte, args []any) (result databases.Result, err error) {
+	if tx.ReadOnly {
+		err = databases.ErrReadOnlyTransaction
+		return
+	}
+	result, err = tx.Transaction.Execute(ctx, query, args)
+	return
+}
+
+// IncrAfterCommitted
+// key of shared store is increased after transaction is committed, it is used to invalidate caches.
+func (tx *Transaction) IncrAfterCommitted(key []byte) {
+	tx.locker.Lock()
+	for _, increment := range tx.increments {
+		if bytes.Equal(increment, key) {
+			tx.locker.Unlock()
+			return
+		}
+	}
+	tx.increments = append(tx.increments, key)
+	tx.locker.Unlock()
+}
+
+// Increments
+// keys which should be increased after transaction is committed.
+func (tx *Transaction) Increments() (keys [][]byte) {
+	tx.locker.Lock()
+	keys = tx.increments
+	tx.locker.Unlock()
+	return
+}
+
 func (tx *Transaction) Acquire() (err error) {
 	tx.locker.Lock()
 	if tx.closed {
@@ -21,6 +21,9 @@ var (
 	jsonTimeType      = reflect.TypeOf(json.Time{})
 	rawType           = reflect.TypeOf(sql.RawBytes{})
 	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	valuerType        = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType       = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	unmarshalerType   = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
 	nullStringType    = reflect.TypeOf(sql.NullString{})
 	nullBoolType      = reflect.TypeOf(sql.NullBool{})
 	nullInt16Type     = reflect.TypeOf(sql.NullInt16{})
@@ -472,9 +475,16 @@ func (n *NullJson[E]) Scan(src any) error {
 	if src == nil {
 		return nil
 	}
-	p, ok := src.([]byte)
-	if !ok {
-		return errors.Warning("sql: null json scan failed").WithCause(fmt.Errorf("src is not bytes"))
+	var p []byte
+	switch s := src.(type) {
+	case []byte:
+		p = s
+		break
+	case string:
+		p = []byte(s)
+		break
+	default:
+		return errors.Warning("sql: null json scan failed").WithCause(fmt.Errorf("src is not bytes or string"))
 	}
 	err := n.UnmarshalJSON(p)
 	if err != nil {
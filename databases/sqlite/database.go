@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"context"
+	stdsql "database/sql"
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns/commons/bytex"
+	"github.com/aacfactory/logs"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	databaseName = "sqlite"
+	memory       = ":memory:"
+)
+
+// Database
+// sqlite database, which is a single file or in memory.
+// use it when kind of config is sqlite.
+func Database() databases.Database {
+	return &database{}
+}
+
+type DatabaseConfig struct {
+	// Driver
+	// name of registered driver, default is sqlite3.
+	Driver string `json:"driver"`
+	// File
+	// path of database file, or :memory:, default is :memory:.
+	File string `json:"file"`
+	// DSN
+	// it is used when it is not empty, and File is ignored.
+	DSN string `json:"dsn"`
+	// MaxOpens
+	// sqlite only has one writer, so default is 1, and it is always 1 when in memory.
+	MaxOpens int `json:"maxOpens"`
+	// BusyTimeout
+	// wait when database is locked, default is 5s.
+	BusyTimeout time.Duration `json:"busyTimeout"`
+	// JournalMode
+	// such as WAL, it is ignored when in memory.
+	JournalMode string `json:"journalMode"`
+	// ForeignKeys
+	// enable foreign keys constraint
+	ForeignKeys bool `json:"foreignKeys"`
+	// Pragmas
+	// extra pragmas, such as `synchronous = NORMAL`
+	Pragmas []string `json:"pragmas"`
+}
+
+type database struct {
+	log  logs.Logger
+	core *stdsql.DB
+}
+
+func (db *database) Name() string {
+	return databaseName
+}
+
+func (db *database) Construct(options databases.Options) (err error) {
+	db.log = options.Log
+	config := DatabaseConfig{}
+	configErr := options.Config.As(&config)
+	if configErr != nil {
+		err = errors.Warning("sqlite: database construct failed").WithCause(configErr)
+		return
+	}
+	driver := strings.TrimSpace(config.Driver)
+	if driver == "" {
+		driver = "sqlite3"
+	}
+	file := strings.TrimSpace(config.File)
+	if file == "" {
+		file = memory
+	}
+	dsn := strings.TrimSpace(config.DSN)
+	if dsn == "" {
+		dsn = file
+	}
+	inMemory := strings.Contains(dsn, memory) || strings.Contains(dsn, "mode=memory")
+	if !slices.Contains(stdsql.Drivers(), driver) {
+		err = errors.Warning("sqlite: database construct failed").
+			WithCause(fmt.Errorf("driver was not registered, import a sqlite driver such as github.com/mattn/go-sqlite3")).
+			WithMeta("driver", driver)
+		return
+	}
+	db.core, err = stdsql.Open(driver, dsn)
+	if err != nil {
+		err = errors.Warning("sqlite: database construct failed").WithCause(err)
+		return
+	}
+	maxOpens := config.MaxOpens
+	if maxOpens < 1 || inMemory {
+		maxOpens = 1
+	}
+	db.core.SetMaxOpenConns(maxOpens)
+	db.core.SetMaxIdleConns(maxOpens)
+	// connections are never closed, otherwise memory database and pragmas of connection are lost
+	db.core.SetConnMaxIdleTime(0)
+	db.core.SetConnMaxLifetime(0)
+
+	busyTimeout := config.BusyTimeout
+	if busyTimeout < 1 {
+		busyTimeout = 5 * time.Second
+	}
+	pragmas := make([]string, 0, 3+len(config.Pragmas))
+	pragmas = append(pragmas, fmt.Sprintf("busy_timeout = %d", busyTimeout.Milliseconds()))
+	if journalMode := strings.TrimSpace(config.JournalMode); journalMode != "" && !inMemory {
+		pragmas = append(pragmas, fmt.Sprintf("journal_mode = %s", journalMode))
+	}
+	if config.ForeignKeys {
+		pragmas = append(pragmas, "foreign_keys = ON")
+	}
+	pragmas = append(pragmas, config.Pragmas...)
+	for _, pragma := range pragmas {
+		pragma = strings.TrimSpace(pragma)
+		if pragma == "" {
+			continue
+		}
+		_, err = db.core.Exec(fmt.Sprintf("PRAGMA %s", pragma))
+		if err != nil {
+			_ = db.core.Close()
+			err = errors.Warning("sqlite: database construct failed").WithCause(err).WithMeta("pragma", pragma)
+			return
+		}
+	}
+	if maxOpens > 1 && db.log.WarnEnabled() {
+		db.log.Warn().Message("sqlite: pragmas only work on first connection when max opens is greater than 1, use dsn to set pragmas for all connections")
+	}
+	return
+}
+
+// Begin
+// isolation is ignored, transaction of sqlite is always serializable.
+// write statements are rejected in read only transaction, because drivers do not support it.
+func (db *database) Begin(ctx context.Context, options databases.TransactionOptions) (tx databases.Transaction, err error) {
+	core, begErr := db.core.BeginTx(ctx, nil)
+	if begErr != nil {
+		err = begErr
+		return
+	}
+	if options.Readonly {
+		tx = databases.NewReadOnlyTransaction(core)
+		return
+	}
+	tx = databases.NewTransaction(core)
+	return
+}
+
+func (db *database) Query(ctx context.Context, query []byte, args []any) (rows databases.Rows, err error) {
+	r, queryErr := db.core.QueryContext(ctx, bytex.ToString(query), args...)
+	if queryErr != nil {
+		err = queryErr
+		return
+	}
+	rows = databases.NewRows(r)
+	return
+}
+
+func (db *database) Execute(ctx context.Context, query []byte, args []any) (result databases.Result, err error) {
+	r, execErr := db.core.ExecContext(ctx, bytex.ToString(query), args...)
+	if execErr != nil {
+		err = execErr
+		return
+	}
+	rowsAffected, rowsAffectedErr := r.RowsAffected()
+	if rowsAffectedErr != nil {
+		err = rowsAffectedErr
+		return
+	}
+	lastInsertId, lastInsertIdErr := r.LastInsertId()
+	if lastInsertIdErr != nil {
+		lastInsertId = -1
+	}
+	result = databases.Result{
+		LastInsertId: lastInsertId,
+		RowsAffected: rowsAffected,
+	}
+	return
+}
+
+func (db *database) Close(_ context.Context) (err error) {
+	err = db.core.Close()
+	return
+}
@@ -0,0 +1,162 @@
+package dialect
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/conditions"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns/commons/bytex"
+	"io"
+	"strings"
+)
+
+var (
+	jsonExtract = []byte("json_extract")
+	jsonType    = []byte("json_type")
+	isNotNull   = []byte("IS NOT NULL")
+)
+
+const (
+	// jsonContainsArray
+	// all elements of argument are in column
+	jsonContainsArray = `NOT EXISTS (SELECT 1 FROM json_each(%s) AS "__E" WHERE NOT EXISTS (SELECT 1 FROM json_each(%s) AS "__C" WHERE "__C"."value" IS "__E"."value"))`
+	// jsonContainsObject
+	// all members of argument are in column
+	jsonContainsObject = `NOT EXISTS (SELECT 1 FROM json_each(%s) AS "__E" WHERE NOT EXISTS (SELECT 1 FROM json_each(%s) AS "__C" WHERE "__C"."key" = "__E"."key" AND "__C"."value" IS "__E"."value"))`
+	// jsonOverlaps
+	// any element of argument is in column
+	jsonOverlaps = `EXISTS (SELECT 1 FROM json_each(%s) AS "__E", json_each(%s) AS "__C" WHERE "__C"."value" IS "__E"."value")`
+	// jsonMemberOf
+	// element is in column
+	jsonMemberOf = `EXISTS (SELECT 1 FROM json_each(%s) AS "__C" WHERE "__C"."value" IS json_extract(%s, '$'))`
+	// jsonValue
+	// value of json argument
+	jsonValue = `json_extract(%s, '$')`
+)
+
+// RenderJsonPredicate
+// sqlite has no containment operator of json, so json_each of json1 is used.
+// nested objects and arrays are compared by minified json text.
+func (dialect *Dialect) RenderJsonPredicate(ctx specifications.Context, w io.Writer, column string, operator conditions.Operator, expr conditions.Json) (arguments []any, err error) {
+	switch operator {
+	case conditions.JSONCONTAINS:
+		value, valueErr := specifications.EncodeJsonValue(expr.Value)
+		if valueErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(valueErr).WithMeta("dialect", Name)
+			return
+		}
+		value = strings.TrimSpace(value)
+		placeholder := ctx.NextQueryPlaceholder()
+		if strings.HasPrefix(value, "[") {
+			_, _ = w.Write(bytex.FromString(fmt.Sprintf(jsonContainsArray, placeholder, column)))
+		} else if strings.HasPrefix(value, "{") {
+			_, _ = w.Write(bytex.FromString(fmt.Sprintf(jsonContainsObject, placeholder, column)))
+		} else {
+			_, _ = w.Write(bytex.FromString(fmt.Sprintf(jsonMemberOf, column, placeholder)))
+		}
+		arguments = append(arguments, value)
+		break
+	case conditions.JSONOVERLAPS:
+		value, valueErr := specifications.EncodeJsonValue(expr.Value)
+		if valueErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(valueErr).WithMeta("dialect", Name)
+			return
+		}
+		_, _ = w.Write(bytex.FromString(fmt.Sprintf(jsonOverlaps, ctx.NextQueryPlaceholder(), column)))
+		arguments = append(arguments, value)
+		break
+	case conditions.JSONMEMBEROF:
+		value, valueErr := specifications.EncodeJsonValue(expr.Value)
+		if valueErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(valueErr).WithMeta("dialect", Name)
+			return
+		}
+		_, _ = w.Write(bytex.FromString(fmt.Sprintf(jsonMemberOf, column, ctx.NextQueryPlaceholder())))
+		arguments = append(arguments, value)
+		break
+	case conditions.JSONHASKEY:
+		path, pathErr := jsonPath(expr.Path)
+		if pathErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(pathErr).WithMeta("dialect", Name)
+			return
+		}
+		// json_type is 'null' when value is json null, and NULL when path does not exist
+		_, _ = w.Write(jsonType)
+		_, _ = w.Write(specifications.LB)
+		_, _ = w.Write(bytex.FromString(column))
+		_, _ = w.Write(specifications.COMMA)
+		_, _ = w.Write(bytex.FromString(path))
+		_, _ = w.Write(specifications.RB)
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(isNotNull)
+		break
+	case conditions.Equal, conditions.NotEqual, conditions.GreatThan, conditions.GreatThanOrEqual, conditions.LessThan, conditions.LessThanOrEqual:
+		value, valueErr := specifications.EncodeJsonValue(expr.Value)
+		if valueErr != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(valueErr).WithMeta("dialect", Name)
+			return
+		}
+		_, err = dialect.RenderJsonPathOrder(ctx, w, column, expr.Path)
+		if err != nil {
+			err = errors.Warning("sql: render json predicate failed").WithCause(err).WithMeta("dialect", Name)
+			return
+		}
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(operator.Bytes())
+		_, _ = w.Write(specifications.SPACE)
+		_, _ = w.Write(bytex.FromString(fmt.Sprintf(jsonValue, ctx.NextQueryPlaceholder())))
+		arguments = append(arguments, value)
+		break
+	default:
+		err = errors.Warning("sql: render json predicate failed").WithCause(fmt.Errorf("%s is not supported", operator)).WithMeta("dialect", Name)
+		return
+	}
+	return
+}
+
+// RenderJsonPathOrder
+// json_extract(column, path)
+func (dialect *Dialect) RenderJsonPathOrder(_ specifications.Context, w io.Writer, column string, path string) (arguments []any, err error) {
+	p, pathErr := jsonPath(path)
+	if pathErr != nil {
+		err = errors.Warning("sql: render json path failed").WithCause(pathErr).WithMeta("dialect", Name)
+		return
+	}
+	_, _ = w.Write(jsonExtract)
+	_, _ = w.Write(specifications.LB)
+	_, _ = w.Write(bytex.FromString(column))
+	_, _ = w.Write(specifications.COMMA)
+	_, _ = w.Write(bytex.FromString(p))
+	_, _ = w.Write(specifications.RB)
+	return
+}
+
+// jsonPath
+// convert `a.b.0` to '$."a"."b"[0]'
+func jsonPath(path string) (v string, err error) {
+	keys := specifications.JsonPathKeys(path)
+	if len(keys) == 0 {
+		err = fmt.Errorf("path is required")
+		return
+	}
+	sb := strings.Builder{}
+	sb.WriteString("'$")
+	for _, key := range keys {
+		if specifications.IsJsonPathIndex(key) {
+			sb.WriteByte('[')
+			sb.WriteString(key)
+			sb.WriteByte(']')
+			continue
+		}
+		if strings.ContainsAny(key, `\"'`) {
+			err = fmt.Errorf("invalid key %s of path", key)
+			return
+		}
+		sb.WriteString(`."`)
+		sb.WriteString(key)
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('\'')
+	v = sb.String()
+	return
+}
@@ -0,0 +1,214 @@
+package columns
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/orders"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/valyala/bytebufferpool"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsonObject     = []byte("json_object")
+	jsonGroupArray = []byte("json_group_array")
+	jsonFn         = []byte("json")
+)
+
+// Fragment
+// column with alias in select
+func Fragment(ctx specifications.Context, spec *specifications.Specification, column *specifications.Column) (fragment string, err error) {
+	switch column.Kind {
+	case specifications.Reference, specifications.Link, specifications.Links, specifications.Virtual:
+		expr, exprErr := Expression(ctx, spec, column)
+		if exprErr != nil {
+			err = exprErr
+			return
+		}
+		fragment = fmt.Sprintf("%s %s %s", expr, specifications.AS, ctx.FormatIdent(column.Name))
+		break
+	default:
+		fragment = ctx.FormatIdent(column.Name)
+		break
+	}
+	return
+}
+
+// Expression
+// column without alias
+func Expression(ctx specifications.Context, spec *specifications.Specification, column *specifications.Column) (expr string, err error) {
+	switch column.Kind {
+	case specifications.Reference:
+		expr, err = Reference(ctx, spec, column)
+		break
+	case specifications.Link:
+		expr, err = Link(ctx, spec, column)
+		break
+	case specifications.Links:
+		expr, err = Links(ctx, spec, column)
+		break
+	case specifications.Virtual:
+		expr, err = Virtual(ctx, spec, column)
+		break
+	default:
+		expr = ctx.FormatIdent(column.Name)
+		break
+	}
+	return
+}
+
+// isJson
+// value of column is json text, so it should be wrapped by json() in json_object, otherwise it is a string.
+func isJson(column *specifications.Column) bool {
+	switch column.Kind {
+	case specifications.Json, specifications.Reference, specifications.Link, specifications.Links:
+		return true
+	case specifications.Virtual:
+		kind, _, _ := column.Virtual()
+		return kind == specifications.ObjectVirtualQuery || kind == specifications.ArrayVirtualQuery
+	default:
+		return false
+	}
+}
+
+// mappingQuery
+// (
+//
+//	SELECT json_object('id', "{src}"."id", ...) FROM (
+//		SELECT "ID" AS "id", ... FROM "schema"."away" WHERE "away" = "host"."column" ORDER BY ... LIMIT x
+//	) AS "{src}"
+//
+// )
+// json_object is wrapped by json_group_array when array is true.
+func mappingQuery(ctx specifications.Context, spec *specifications.Specification, column *specifications.Column,
+	mapping *specifications.Specification, awayColumn *specifications.Column, hostColumn *specifications.Column,
+	array bool, orders orders.Orders, length int) (fragment string, err error) {
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	hostTableName := ctx.FormatIdent(spec.Name)
+	if spec.Schema != "" {
+		hostTableName = fmt.Sprintf("%s.%s", ctx.FormatIdent(spec.Schema), hostTableName)
+	}
+	awayTableName := ctx.FormatIdent(mapping.Name)
+	if mapping.Schema != "" {
+		awayTableName = fmt.Sprintf("%s.%s", ctx.FormatIdent(mapping.Schema), awayTableName)
+	}
+	srcName := ctx.FormatIdent(fmt.Sprintf("%s_%s", spec.Name, mapping.Name))
+
+	_, _ = buf.Write(specifications.LB)
+	// json >>>
+	_, _ = buf.Write(specifications.SELECT)
+	_, _ = buf.Write(specifications.SPACE)
+	if array {
+		_, _ = buf.Write(jsonGroupArray)
+		_, _ = buf.Write(specifications.LB)
+	}
+	_, _ = buf.Write(jsonObject)
+	_, _ = buf.Write(specifications.LB)
+	for i, mappingColumn := range mapping.Columns {
+		if i > 0 {
+			_, _ = buf.Write(specifications.COMMA)
+		}
+		key := ctx.FormatIdent(mappingColumn.JsonIdent)
+		_, _ = buf.WriteString("'")
+		_, _ = buf.WriteString(strings.ReplaceAll(mappingColumn.JsonIdent, "'", "''"))
+		_, _ = buf.WriteString("'")
+		_, _ = buf.Write(specifications.COMMA)
+		value := fmt.Sprintf("%s.%s", srcName, key)
+		if isJson(mappingColumn) {
+			_, _ = buf.Write(jsonFn)
+			_, _ = buf.Write(specifications.LB)
+			_, _ = buf.WriteString(value)
+			_, _ = buf.Write(specifications.RB)
+		} else if mappingColumn.Type.Name == specifications.BoolType {
+			// bool is integer in sqlite
+			_, _ = buf.WriteString(fmt.Sprintf("CASE WHEN %s IS NULL THEN NULL WHEN %s THEN json('true') ELSE json('false') END", value, value))
+		} else {
+			_, _ = buf.WriteString(value)
+		}
+	}
+	_, _ = buf.Write(specifications.RB)
+	if array {
+		_, _ = buf.Write(specifications.RB)
+	}
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.FROM)
+	_, _ = buf.Write(specifications.SPACE)
+	// src >>>
+	_, _ = buf.Write(specifications.LB)
+	_, _ = buf.Write(specifications.SELECT)
+	_, _ = buf.Write(specifications.SPACE)
+	for i, mappingColumn := range mapping.Columns {
+		if i > 0 {
+			_, _ = buf.Write(specifications.COMMA)
+		}
+		expr, exprErr := Expression(ctx, mapping, mappingColumn)
+		if exprErr != nil {
+			err = exprErr
+			return
+		}
+		_, _ = buf.WriteString(expr)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.AS)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.WriteString(ctx.FormatIdent(mappingColumn.JsonIdent))
+	}
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.FROM)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.WriteString(awayTableName)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.WHERE)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.WriteString(ctx.FormatIdent(awayColumn.Name))
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.EQ)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.WriteString(hostTableName)
+	_, _ = buf.Write(specifications.DOT)
+	_, _ = buf.WriteString(ctx.FormatIdent(hostColumn.Name))
+	if len(orders) > 0 {
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.ORDER)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.BY)
+		_, _ = buf.Write(specifications.SPACE)
+		for i, order := range orders {
+			if i > 0 {
+				_, _ = buf.Write(specifications.COMMA)
+			}
+			mc, hasMC := mapping.ColumnByField(order.Name)
+			if !hasMC {
+				err = errors.Warning("sql: render mapping field failed").
+					WithCause(fmt.Errorf("%s is not found in %s", order.Name, mapping.Key)).
+					WithMeta("table", spec.Key).
+					WithMeta("field", column.Field)
+				return
+			}
+			_, _ = buf.WriteString(ctx.FormatIdent(mc.Name))
+			if order.Desc {
+				_, _ = buf.Write(specifications.SPACE)
+				_, _ = buf.Write(specifications.DESC)
+			}
+		}
+	}
+	if length > 0 {
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.Write(specifications.LIMIT)
+		_, _ = buf.Write(specifications.SPACE)
+		_, _ = buf.WriteString(strconv.Itoa(length))
+	}
+	_, _ = buf.Write(specifications.RB)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.Write(specifications.AS)
+	_, _ = buf.Write(specifications.SPACE)
+	_, _ = buf.WriteString(srcName)
+	// src <<<
+	// json <<<
+	_, _ = buf.Write(specifications.RB)
+
+	fragment = buf.String()
+	return
+}
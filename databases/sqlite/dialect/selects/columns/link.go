@@ -0,0 +1,50 @@
+package columns
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+)
+
+// Link
+// (
+//
+//	SELECT json_object(...) FROM (
+//		SELECT ... FROM "schema"."away" WHERE "away_column" = "host"."host_column" LIMIT 1
+//	) AS "{host}_{away}"
+//
+// )
+func Link(ctx specifications.Context, spec *specifications.Specification, column *specifications.Column) (fragment string, err error) {
+	hostField, awayField, mapping, ok := column.Link()
+	if !ok {
+		err = errors.Warning("sql: render link field failed").
+			WithCause(fmt.Errorf("%s is not link", column.Field)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	hostColumn, hasHostColumn := spec.ColumnByField(hostField)
+	if !hasHostColumn {
+		err = errors.Warning("sql: render link field failed").
+			WithCause(fmt.Errorf("%s is not found in %s", hostField, spec.Key)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	awayColumn, hasAwayColumn := mapping.ColumnByField(awayField)
+	if !hasAwayColumn {
+		err = errors.Warning("sql: render link field failed").
+			WithCause(fmt.Errorf("%s is not found in %s", awayField, mapping.Key)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	fragment, err = mappingQuery(ctx, spec, column, mapping, awayColumn, hostColumn, false, nil, 1)
+	if err != nil {
+		err = errors.Warning("sql: render link field failed").WithCause(err).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	return
+}
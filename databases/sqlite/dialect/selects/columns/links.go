@@ -0,0 +1,51 @@
+package columns
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+)
+
+// Links
+// (
+//
+//	SELECT json_group_array(json_object(...)) FROM (
+//		SELECT ... FROM "schema"."away" WHERE "away_column" = "host"."host_column" ORDER BY ... LIMIT y
+//	) AS "{host}_{away}"
+//
+// )
+// empty array when there is no linked row.
+func Links(ctx specifications.Context, spec *specifications.Specification, column *specifications.Column) (fragment string, err error) {
+	hostField, awayField, mapping, orders, length, ok := column.Links()
+	if !ok {
+		err = errors.Warning("sql: render links field failed").
+			WithCause(fmt.Errorf("%s is not links", column.Field)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	hostColumn, hasHostColumn := spec.ColumnByField(hostField)
+	if !hasHostColumn {
+		err = errors.Warning("sql: render links field failed").
+			WithCause(fmt.Errorf("%s is not found in %s", hostField, spec.Key)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	awayColumn, hasAwayColumn := mapping.ColumnByField(awayField)
+	if !hasAwayColumn {
+		err = errors.Warning("sql: render links field failed").
+			WithCause(fmt.Errorf("%s is not found in %s", awayField, mapping.Key)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	fragment, err = mappingQuery(ctx, spec, column, mapping, awayColumn, hostColumn, true, orders, length)
+	if err != nil {
+		err = errors.Warning("sql: render links field failed").WithCause(err).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	return
+}
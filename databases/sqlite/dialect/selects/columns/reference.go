@@ -0,0 +1,42 @@
+package columns
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+)
+
+// Reference
+// (
+//
+//	SELECT json_object(...) FROM (
+//		SELECT ... FROM "schema"."away" WHERE "pk" = "host"."ref_column" LIMIT 1
+//	) AS "{host}_{away}"
+//
+// )
+func Reference(ctx specifications.Context, spec *specifications.Specification, column *specifications.Column) (fragment string, err error) {
+	awayField, mapping, ok := column.Reference()
+	if !ok {
+		err = errors.Warning("sql: render reference field failed").
+			WithCause(fmt.Errorf("%s is not reference", column.Field)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	awayColumn, hasAwayColumn := mapping.ColumnByField(awayField)
+	if !hasAwayColumn {
+		err = errors.Warning("sql: render reference field failed").
+			WithCause(fmt.Errorf("%s is not found in %s", awayField, mapping.Key)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	fragment, err = mappingQuery(ctx, spec, column, mapping, awayColumn, column, false, nil, 1)
+	if err != nil {
+		err = errors.Warning("sql: render reference field failed").WithCause(err).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	return
+}
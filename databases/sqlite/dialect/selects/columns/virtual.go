@@ -0,0 +1,47 @@
+package columns
+
+import (
+	"fmt"
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/valyala/bytebufferpool"
+)
+
+// Virtual
+// sqlite can not convert row into json dynamically,
+// so select expr of query must be json (such as json_object and json_group_array) when kind is object or array.
+func Virtual(ctx specifications.Context, spec *specifications.Specification, column *specifications.Column) (fragment string, err error) {
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+
+	kind, query, ok := column.Virtual()
+	if !ok {
+		err = errors.Warning("sql: render virtual field failed").
+			WithCause(fmt.Errorf("%s is not virtual", column.Field)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+	switch kind {
+	case specifications.BasicVirtualQuery, specifications.ObjectVirtualQuery, specifications.ArrayVirtualQuery:
+		_, _ = buf.Write(specifications.LB)
+		_, _ = buf.WriteString(query)
+		_, _ = buf.Write(specifications.RB)
+		break
+	case specifications.AggregateVirtualQuery:
+		_, _ = buf.WriteString(query)
+		_, _ = buf.Write(specifications.LB)
+		_, _ = buf.WriteString(ctx.FormatIdent(column.Name))
+		_, _ = buf.Write(specifications.RB)
+		break
+	default:
+		err = errors.Warning("sql: render virtual field failed").
+			WithCause(fmt.Errorf("kind of %s is not valid virtual", column.Field)).
+			WithMeta("table", spec.Key).
+			WithMeta("field", column.Field)
+		return
+	}
+
+	fragment = buf.String()
+	return
+}
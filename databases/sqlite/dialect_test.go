@@ -0,0 +1,115 @@
+package sqlite_test
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sql/sqltest"
+	"github.com/aacfactory/fns-contrib/databases/sqlite/dialect"
+	"testing"
+)
+
+type Post struct {
+	Id      int64  `column:"ID,pk,incr"`
+	Title   string `column:"TITLE"`
+	Version int64  `column:"VERSION,aol"`
+}
+
+func (post Post) TableInfo() dac.TableInfo {
+	return dac.Info("POST")
+}
+
+type Org struct {
+	Id       string `column:"ID,pk" tree:"ParentId+Children"`
+	ParentId string `column:"PARENT_ID"`
+	Name     string `column:"NAME"`
+	Children []Org  `json:"children"`
+}
+
+func (org Org) TableInfo() dac.TableInfo {
+	return dac.Info("ORG")
+}
+
+func TestInsert(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	method, query, arguments, returning, err := specifications.BuildInsert[Post](ctx, []Post{{Title: "a"}})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	if method != specifications.QueryMethod {
+		t.Errorf("insert with incr column must be query method")
+	}
+	sqltest.AssertQuery(t, query, `INSERT INTO "POST" ("VERSION", "TITLE") VALUES (1, ?1) RETURNING "ID"`)
+	sqltest.AssertArguments(t, arguments, "a")
+	if len(returning) != 1 || returning[0] != "Id" {
+		t.Errorf("returning must be Id, got %v", returning)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	_, query, arguments, err := specifications.BuildUpdate[Post](ctx, []Post{{Id: 1, Title: "b", Version: 2}})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `UPDATE "POST" SET "VERSION" = "VERSION"+1, "TITLE" = ?1 WHERE "ID" = ?2 AND "VERSION" = ?3`)
+	sqltest.AssertArguments(t, arguments, "b", int64(1), int64(2))
+}
+
+func TestDelete(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	_, query, arguments, err := specifications.BuildDeleteByCondition[Post](ctx, specifications.Condition{Condition: dac.Eq("Title", "a")})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `DELETE FROM "POST" WHERE "TITLE" = ?1`)
+	sqltest.AssertArguments(t, arguments, "a")
+}
+
+func TestQuery(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	cond := specifications.Condition{Condition: dac.Eq("Title", "a")}
+	_, query, arguments, columns, err := specifications.BuildQuery[Post](ctx, cond, specifications.Orders(dac.Desc("Id")), 10, 20)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `SELECT "ID", "TITLE", "VERSION" FROM "POST" WHERE "TITLE" = ?1 ORDER BY "ID" DESC LIMIT ?2, ?3`)
+	sqltest.AssertArguments(t, arguments, "a", 10, 20)
+	if len(columns) != 3 {
+		t.Errorf("columns must be 3, got %v", columns)
+	}
+	_, query, arguments, err = specifications.BuildCount[Post](ctx, cond)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `SELECT COUNT(1) AS "_COUNT_" FROM "POST" WHERE "TITLE" = ?1`)
+	sqltest.AssertArguments(t, arguments, "a")
+	_, query, _, err = specifications.BuildExist[Post](ctx, cond)
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `SELECT 1 AS "_EXIST_" FROM "POST" WHERE "TITLE" = ?1`)
+}
+
+func TestTree(t *testing.T) {
+	ctx := sqltest.Context(dialect.Name)
+	_, query, arguments, _, err := specifications.BuildSubtree[Org](ctx, "root", 2, specifications.Condition{}, specifications.Orders(dac.Asc("Name")))
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `WITH RECURSIVE "__TREE" ("ID", "__DEPTH") AS (SELECT "ID", 0 FROM "ORG" WHERE "ID" = ?1 UNION SELECT "__NODE"."ID", "__TREE"."__DEPTH" + 1 FROM "ORG" AS "__NODE" INNER JOIN "__TREE" ON "__NODE"."PARENT_ID" = "__TREE"."ID" WHERE "__TREE"."__DEPTH" < 2) SELECT "ID", "PARENT_ID", "NAME" FROM "ORG" WHERE "ID" IN (SELECT "ID" FROM "__TREE") ORDER BY "NAME"`)
+	sqltest.AssertArguments(t, arguments, "root")
+	_, query, arguments, _, err = specifications.BuildAncestors[Org](ctx, "leaf", specifications.Condition{})
+	if err != nil {
+		t.Errorf("%+v", err)
+		return
+	}
+	sqltest.AssertQuery(t, query, `WITH RECURSIVE "__TREE" ("ID", "__PARENT") AS (SELECT "ID", "PARENT_ID" FROM "ORG" WHERE "ID" = ?1 UNION SELECT "__NODE"."ID", "__NODE"."PARENT_ID" FROM "ORG" AS "__NODE" INNER JOIN "__TREE" ON "__NODE"."ID" = "__TREE"."__PARENT") SELECT "ID", "PARENT_ID", "NAME" FROM "ORG" WHERE "ID" IN (SELECT "ID" FROM "__TREE")`)
+	sqltest.AssertArguments(t, arguments, "leaf")
+}
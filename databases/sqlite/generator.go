@@ -0,0 +1,13 @@
+package sqlite
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sqlite/generators"
+	"github.com/aacfactory/fns/cmd/generates/modules"
+)
+
+func FAG() []modules.FnAnnotationCodeWriter {
+	return []modules.FnAnnotationCodeWriter{
+		&generators.UseWriter{},
+		&generators.TransactionWriter{},
+	}
+}
@@ -0,0 +1,44 @@
+module github.com/aacfactory/fns-contrib/databases/sqlite
+
+go 1.22.1
+
+require (
+	github.com/aacfactory/errors v1.13.12
+	github.com/aacfactory/fns v1.3.0
+	github.com/aacfactory/fns-contrib/databases/sql v1.3.0
+	github.com/aacfactory/gcg v1.0.5
+	github.com/aacfactory/json v1.16.9
+	github.com/aacfactory/logs v1.13.13
+	github.com/valyala/bytebufferpool v1.0.0
+	golang.org/x/sync v0.7.0
+)
+
+require (
+	github.com/aacfactory/afssl v1.12.0 // indirect
+	github.com/aacfactory/avro v1.2.12 // indirect
+	github.com/aacfactory/cases v1.1.0 // indirect
+	github.com/aacfactory/configures v1.13.0 // indirect
+	github.com/aacfactory/copier v1.4.0 // indirect
+	github.com/aacfactory/workers v1.8.4 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/fatih/color v1.17.0 // indirect
+	github.com/goccy/go-yaml v1.11.3 // indirect
+	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/rs/xid v1.5.0 // indirect
+	github.com/tidwall/btree v1.7.0 // indirect
+	github.com/tidwall/gjson v1.17.1 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.1 // indirect
+	github.com/tidwall/sjson v1.2.5 // indirect
+	golang.org/x/crypto v0.23.0 // indirect
+	golang.org/x/mod v0.17.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+	golang.org/x/term v0.20.0 // indirect
+	golang.org/x/tools v0.19.0 // indirect
+	golang.org/x/xerrors v0.0.0-20231012003039-104605ab7028 // indirect
+)
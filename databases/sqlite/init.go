@@ -0,0 +1,10 @@
+package sqlite
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql/dac/specifications"
+	"github.com/aacfactory/fns-contrib/databases/sqlite/dialect"
+)
+
+func init() {
+	specifications.RegisterDialect(dialect.NewDialect())
+}
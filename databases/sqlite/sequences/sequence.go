@@ -0,0 +1,63 @@
+package sequences
+
+import (
+	"github.com/aacfactory/errors"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sqlite/dialect"
+	"github.com/aacfactory/fns/context"
+)
+
+// sqlite has no sequence, so sequences are rows of __SEQUENCES table, which is created when it does not exist.
+var (
+	createQuery  = []byte(`CREATE TABLE IF NOT EXISTS "__SEQUENCES" ("NAME" TEXT NOT NULL PRIMARY KEY, "VALUE" INTEGER NOT NULL DEFAULT 0)`)
+	nextQuery    = []byte(`INSERT INTO "__SEQUENCES" ("NAME", "VALUE") VALUES (?1, 1) ON CONFLICT ("NAME") DO UPDATE SET "VALUE" = "VALUE" + 1 RETURNING "VALUE"`)
+	currentQuery = []byte(`SELECT "VALUE" FROM "__SEQUENCES" WHERE "NAME" = ?1`)
+)
+
+func Next(ctx context.Context, key string) (n int64, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	_, createErr := sql.Execute(ctx, createQuery)
+	if createErr != nil {
+		err = errors.Warning("sqlite: next sequence value failed").WithCause(createErr)
+		return
+	}
+	rows, queryErr := sql.Query(ctx, nextQuery, key)
+	if queryErr != nil {
+		err = errors.Warning("sqlite: next sequence value failed").WithCause(queryErr)
+		return
+	}
+	if rows.Next() {
+		scanErr := rows.Scan(&n)
+		if scanErr != nil {
+			_ = rows.Close()
+			err = errors.Warning("sqlite: next sequence value failed").WithCause(scanErr)
+			return
+		}
+	}
+	_ = rows.Close()
+	return
+}
+
+func Current(ctx context.Context, key string) (n int64, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	_, createErr := sql.Execute(ctx, createQuery)
+	if createErr != nil {
+		err = errors.Warning("sqlite: current sequence value failed").WithCause(createErr)
+		return
+	}
+	rows, queryErr := sql.Query(ctx, currentQuery, key)
+	if queryErr != nil {
+		err = errors.Warning("sqlite: current sequence value failed").WithCause(queryErr)
+		return
+	}
+	if rows.Next() {
+		scanErr := rows.Scan(&n)
+		if scanErr != nil {
+			_ = rows.Close()
+			err = errors.Warning("sqlite: current sequence value failed").WithCause(scanErr)
+			return
+		}
+	}
+	_ = rows.Close()
+	return
+}
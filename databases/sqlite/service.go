@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"crypto/tls"
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns-contrib/databases/sqlite/dialect"
+	"github.com/aacfactory/fns/context"
+	"github.com/aacfactory/fns/services"
+)
+
+func WithName(name string) sql.Option {
+	return sql.WithName(name)
+}
+
+func WithDatabase(db databases.Database) sql.Option {
+	return sql.WithDatabase(db)
+}
+
+type RegisterTLSFunc func(config *tls.Config) (err error)
+
+func WithTLS(fn RegisterTLSFunc) sql.Option {
+	return sql.WithTLS(sql.RegisterTLSFunc(fn))
+}
+
+// New
+// database is sqlite Database by default, so kind of config should be sqlite.
+func New(options ...sql.Option) services.Service {
+	options = append([]sql.Option{sql.WithDatabase(Database())}, options...)
+	options = append(options, sql.WithDialect(dialect.Name))
+	return sql.New(options...)
+}
+
+func Use(ctx context.Context, endpointName []byte) context.Context {
+	return sql.Use(ctx, endpointName)
+}
+
+func Disuse(ctx context.Context) context.Context {
+	return sql.Disuse(ctx)
+}
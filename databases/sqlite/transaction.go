@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/databases"
+	"github.com/aacfactory/fns/context"
+)
+
+func Begin(ctx context.Context, options ...databases.TransactionOption) (err error) {
+	err = sql.Begin(ctx, options...)
+	return
+}
+
+// ReadOnly
+// transaction is read only, write statements are rejected in it.
+func ReadOnly() databases.TransactionOption {
+	return sql.ReadOnly()
+}
+
+func Commit(ctx context.Context) (err error) {
+	err = sql.Commit(ctx)
+	return
+}
+
+func Rollback(ctx context.Context) {
+	sql.Rollback(ctx)
+	return
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"github.com/aacfactory/fns-contrib/databases/sql"
+	"github.com/aacfactory/fns-contrib/databases/sql/dac"
+	"github.com/aacfactory/fns-contrib/databases/sqlite/dialect"
+	"github.com/aacfactory/fns/context"
+)
+
+func Tree[T Table](ctx context.Context, options ...QueryOption) (entry T, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	opts := acquireQueryOptions()
+	for _, option := range options {
+		opts = append(opts, dac.QueryOption(option))
+	}
+	entry, err = dac.Tree[T](ctx, opts...)
+	releaseQueryOptions(opts)
+	return
+}
+
+func Trees[T Table](ctx context.Context, options ...QueryOption) (entries []T, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	opts := acquireQueryOptions()
+	for _, option := range options {
+		opts = append(opts, dac.QueryOption(option))
+	}
+	entries, err = dac.Trees[T](ctx, opts...)
+	releaseQueryOptions(opts)
+	return
+}
+
+func Subtree[T Table](ctx context.Context, root any, depth int, options ...QueryOption) (entry T, has bool, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	opts := acquireQueryOptions()
+	for _, option := range options {
+		opts = append(opts, dac.QueryOption(option))
+	}
+	entry, has, err = dac.Subtree[T](ctx, root, depth, opts...)
+	releaseQueryOptions(opts)
+	return
+}
+
+func Ancestors[T Table](ctx context.Context, id any, options ...QueryOption) (entries []T, err error) {
+	sql.ForceDialect(ctx, dialect.Name)
+	opts := acquireQueryOptions()
+	for _, option := range options {
+		opts = append(opts, dac.QueryOption(option))
+	}
+	entries, err = dac.Ancestors[T](ctx, id, opts...)
+	releaseQueryOptions(opts)
+	return
+}